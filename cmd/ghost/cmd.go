@@ -1,17 +1,22 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	logrusFlag "github.com/toknowwhy/theunit-oracle/pkg/log/logrus/flag"
 )
 
 type options struct {
-	LogVerbosity   string
-	LogFormat      logrusFlag.FormatTypeValue
-	ConfigFilePath string
-	Config         Config
-	GoferNoRPC     bool
+	LogVerbosity    string
+	LogFormat       logrusFlag.FormatTypeValue
+	ConfigFilePaths []string
+	ConfigWatch     time.Duration
+	Config          Config
+	GoferNoRPC      bool
+	DryRun          bool
+	OnceTimeout     time.Duration
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -35,11 +40,11 @@ func NewRootCommand(opts *options) *cobra.Command {
 		"log.format",
 		"log format",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&opts.ConfigFilePath,
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&opts.ConfigFilePaths,
 		"config", "c",
-		"./config.json",
-		"ghost config file",
+		[]string{"./config.json"},
+		"ghost config file, may be used multiple times to merge overlay files",
 	)
 	rootCmd.PersistentFlags().BoolVar(
 		&opts.GoferNoRPC,
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/geth"
+	"github.com/toknowwhy/theunit-oracle/pkg/ghost/journal"
+)
+
+type journalOptions struct {
+	From string
+	To   string
+}
+
+func NewJournalCmd(opts *options) *cobra.Command {
+	var journalOpts journalOptions
+	cmd := &cobra.Command{
+		Use:   "journal",
+		Short: "Query the journal of signed prices",
+		Long: `Commands to query the journal in which Ghost records every signed price.
+
+The --from and --to flags accept a date in the RFC3339 format, a Unix
+timestamp or a duration relative to the current time (e.g. 24h).`,
+	}
+	cmd.PersistentFlags().StringVar(
+		&journalOpts.From,
+		"from",
+		"24h",
+		"start of the time range",
+	)
+	cmd.PersistentFlags().StringVar(
+		&journalOpts.To,
+		"to",
+		"",
+		"end of the time range (default now)",
+	)
+	cmd.AddCommand(
+		NewJournalQueryCmd(opts, &journalOpts),
+		NewJournalVerifyCmd(opts, &journalOpts),
+	)
+	return cmd
+}
+
+func NewJournalQueryCmd(opts *options, journalOpts *journalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "query [PAIR]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Print journal entries as NDJSON",
+		RunE: func(_ *cobra.Command, args []string) error {
+			entries, err := queryJournal(opts, journalOpts, args)
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func NewJournalVerifyCmd(opts *options, journalOpts *journalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify [PAIR]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Verify signatures of journal entries",
+		Long: `Verify if signatures of journal entries are valid and if they were created
+by recorded signers. If the stark.oracleName option is set, StarkWare
+signatures are verified too. Invalid entries are printed and the command
+exits with a non-zero status code.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			entries, err := queryJournal(opts, journalOpts, args)
+			if err != nil {
+				return err
+			}
+			signer := geth.NewSigner(nil)
+			starkName := opts.Config.Stark.OracleName
+			invalid := 0
+			for _, e := range entries {
+				err := journal.Verify(e, signer)
+				if err == nil && starkName != "" && e.Price.HasStarkSignature() {
+					err = e.Price.StarkVerify(starkName)
+				}
+				if err != nil {
+					invalid++
+					wat := ""
+					if e.Price != nil {
+						wat = e.Price.Wat
+					}
+					fmt.Printf("%s %s: %s\n", e.Time.Format(time.RFC3339Nano), wat, err)
+				}
+			}
+			fmt.Printf("%d entries verified, %d invalid\n", len(entries), invalid)
+			if invalid > 0 {
+				return fmt.Errorf("%d entries have invalid signatures", invalid)
+			}
+			return nil
+		},
+	}
+}
+
+func queryJournal(opts *options, journalOpts *journalOptions, args []string) ([]journal.Entry, error) {
+	if err := config.ParseFiles(&opts.Config, opts.ConfigFilePaths); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	jou, err := opts.Config.Ghost.ConfigureJournal()
+	if err != nil {
+		return nil, err
+	}
+	if jou == nil {
+		return nil, errors.New("journal is not enabled in the configuration file")
+	}
+	now := time.Now()
+	from, err := parseJournalTime(journalOpts.From, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from value: %w", err)
+	}
+	to, err := parseJournalTime(journalOpts.To, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --to value: %w", err)
+	}
+	q := journal.Query{From: from, To: to}
+	if len(args) > 0 {
+		q.AssetPair = args[0]
+	}
+	return jou.Query(q)
+}
+
+// parseJournalTime parses a time given as a RFC3339 date, a Unix timestamp
+// or a duration relative to now. An empty string gives a zero time.
+func parseJournalTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			d = -d
+		}
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("unable to parse %q as a date, timestamp or duration", s)
+}
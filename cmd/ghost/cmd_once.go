@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewOnceCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "once",
+		Args:  cobra.ExactArgs(0),
+		Short: "Broadcast prices for all pairs once and exit",
+		Long: `Fetch, sign and broadcast prices for all configured pairs once, and exit
+after they are published. Prices are broadcast regardless of pair policies,
+but they still have to pass quality gates. The command waits until at least
+one peer subscribes to the price topic before prices are broadcast.
+
+The command exits with a non-zero status code if any price could not be
+broadcast.
+
+With the --dry-run flag, the P2P transport is not started. Instead, price
+messages are printed to stdout, one per line, and they are not recorded
+in the journal.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			srv, err := PrepareServices(context.Background(), opts)
+			if err != nil {
+				return err
+			}
+			stats, err := srv.Once(opts.OnceTimeout)
+			if err != nil {
+				return err
+			}
+			if failed := stats.Pairs - stats.Broadcast; failed > 0 {
+				return fmt.Errorf("%d of %d prices were not broadcast", failed, stats.Pairs)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(
+		&opts.DryRun,
+		"dry-run",
+		false,
+		"print price messages instead of broadcasting them",
+	)
+	cmd.Flags().DurationVar(
+		&opts.OnceTimeout,
+		"timeout",
+		time.Minute,
+		"maximum time for waiting for peers and broadcasting prices",
+	)
+	return cmd
+}
@@ -5,17 +5,27 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 )
 
 func NewRunCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "run",
 		Args:    cobra.ExactArgs(0),
 		Aliases: []string{"agent"},
 		Short:   "",
-		Long:    ``,
+		Long: `Price models and pairs are reloaded when the SIGHUP signal is received
+or when config files are changed. An invalid config is rejected and the
+current one is left running. If the Gofer RPC agent is used, price models
+are reloaded by the agent. Changes in other config sections require a restart.
+
+With the --dry-run flag, the P2P transport is not started. Instead, price
+messages are printed to stdout, one per line, and they are not recorded
+in the journal.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			srv, err := PrepareServices(context.Background(), opts)
 			if err != nil {
@@ -26,11 +36,42 @@ func NewRunCmd(opts *options) *cobra.Command {
 			}
 			defer srv.CancelAndWait()
 
-			c := make(chan os.Signal, 1)
-			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-			<-c
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+			var watchCh <-chan struct{}
+			if opts.ConfigWatch > 0 {
+				watchCh = config.Watch(ctx, opts.ConfigFilePaths, opts.ConfigWatch)
+			}
 
-			return nil
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+			for {
+				select {
+				case s := <-c:
+					if s != syscall.SIGHUP {
+						return nil
+					}
+				case <-watchCh:
+				}
+				if err := srv.Reload(opts); err != nil {
+					srv.Logger.WithError(err).Error("Unable to reload config, the current config is left running")
+					continue
+				}
+				srv.Logger.Info("Config reloaded")
+			}
 		},
 	}
+	cmd.Flags().BoolVar(
+		&opts.DryRun,
+		"dry-run",
+		false,
+		"print price messages instead of broadcasting them",
+	)
+	cmd.Flags().DurationVar(
+		&opts.ConfigWatch,
+		"config.watch",
+		10*time.Second,
+		"how often config files are checked for changes, 0 disables watching",
+	)
+	return cmd
 }
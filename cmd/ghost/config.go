@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -12,27 +14,45 @@ import (
 	feedsConfig "github.com/toknowwhy/theunit-oracle/internal/config/feeds"
 	ghostConfig "github.com/toknowwhy/theunit-oracle/internal/config/ghost"
 	goferConfig "github.com/toknowwhy/theunit-oracle/internal/config/gofer"
+	starkConfig "github.com/toknowwhy/theunit-oracle/internal/config/stark"
 	transportConfig "github.com/toknowwhy/theunit-oracle/internal/config/transport"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/ghost"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph"
 	logLogrus "github.com/toknowwhy/theunit-oracle/pkg/log/logrus"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/logrus/formatter"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 )
 
+// publishWait is the time for which the Services.Once method waits after
+// broadcasting prices, so the transport can deliver them to peers.
+const publishWait = 2 * time.Second
+
+// peerWaiter is implemented by transports which can wait for peers before
+// broadcasting messages.
+type peerWaiter interface {
+	WaitForPeers(ctx context.Context, topic string, n int) error
+}
+
 type Config struct {
 	Gofer     goferConfig.Gofer         `json:"gofer"`
 	Ethereum  ethereumConfig.Ethereum   `json:"ethereum"`
 	Transport transportConfig.Transport `json:"transport"`
 	Ghost     ghostConfig.Ghost         `json:"ghost"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Stark     starkConfig.Stark         `json:"stark"`
 }
 
 type Dependencies struct {
 	Context context.Context
 	Logger  log.Logger
+	// DryRun, if true, replaces the P2P transport with one that prints
+	// messages to stdout, and disables the journal.
+	DryRun bool
 }
 
 func (c *Config) Configure(d Dependencies, noGoferRPC bool) (transport.Transport, gofer.Gofer, *ghost.Ghost, error) {
@@ -52,25 +72,39 @@ func (c *Config) Configure(d Dependencies, noGoferRPC bool) (transport.Transport
 	if sig.Address() == ethereum.EmptyAddress {
 		return nil, nil, nil, errors.New("ethereum account must be configured")
 	}
-	fed, err := c.Feeds.Addresses()
+	starkSig, err := c.Stark.ConfigureSigner()
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	tra, err := c.Transport.Configure(transportConfig.Dependencies{
-		Context: d.Context,
-		Signer:  sig,
-		Feeds:   fed,
-		Logger:  d.Logger,
-	})
-	if err != nil {
-		return nil, nil, nil, err
+	ghoCfg := c.Ghost
+	var tra transport.Transport
+	if d.DryRun {
+		tra = newDryRunTransport(d.Context, os.Stdout)
+		ghoCfg.Journal = ghostConfig.Journal{}
+	} else {
+		fed, err := c.Feeds.Addresses()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		tra, err = c.Transport.Configure(transportConfig.Dependencies{
+			Context:         d.Context,
+			Signer:          sig,
+			Feeds:           fed,
+			Logger:          d.Logger,
+			StarkOracleName: c.Stark.OracleName,
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
-	gho, err := c.Ghost.Configure(ghostConfig.Dependencies{
-		Context:   d.Context,
-		Gofer:     gof,
-		Signer:    sig,
-		Transport: tra,
-		Logger:    d.Logger,
+	gho, err := ghoCfg.Configure(ghostConfig.Dependencies{
+		Context:         d.Context,
+		Gofer:           gof,
+		Signer:          sig,
+		StarkSigner:     starkSig,
+		StarkOracleName: c.Stark.OracleName,
+		Transport:       tra,
+		Logger:          d.Logger,
 	})
 	if err != nil {
 		return nil, nil, nil, err
@@ -83,6 +117,7 @@ type Services struct {
 	Transport transport.Transport
 	Gofer     gofer.Gofer
 	Ghost     *ghost.Ghost
+	Logger    log.Logger
 }
 
 func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
@@ -95,7 +130,7 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -107,13 +142,14 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
 	tra, gof, gho, err := opts.Config.Configure(Dependencies{
 		Context: ctx,
 		Logger:  logger,
+		DryRun:  opts.DryRun,
 	}, opts.GoferNoRPC)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load Ghost configuration: %w", err)
@@ -124,6 +160,7 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 		Transport: tra,
 		Gofer:     gof,
 		Ghost:     gho,
+		Logger:    logger,
 	}, nil
 }
 
@@ -143,6 +180,90 @@ func (s *Services) Start() error {
 	return nil
 }
 
+// Reload parses config files again and replaces Gofer price models and
+// Ghost pairs with the new ones. If the new config is invalid, an error is
+// returned and the current config is left running. Changes in other config
+// sections require a restart.
+func (s *Services) Reload(opts *options) error {
+	var cfg Config
+	if err := config.ParseFiles(&cfg, opts.ConfigFilePaths); err != nil {
+		return fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	pairs := cfg.Ghost.ConfigurePairs()
+	// If Gofer RPC agent is used, price models are reloaded by the agent:
+	if u, ok := s.Gofer.(graph.Updater); ok {
+		graphs, err := opts.Config.Gofer.ReloadPriceModels(&cfg.Gofer)
+		if err != nil {
+			return err
+		}
+		var goferPairs []gofer.Pair
+		for p := range graphs {
+			goferPairs = append(goferPairs, p)
+		}
+		var names []string
+		for _, p := range pairs {
+			names = append(names, p.AssetPair)
+		}
+		if _, err := ghost.MatchPairs(names, goferPairs); err != nil {
+			return err
+		}
+		if err := u.UpdateGraphs(graphs); err != nil {
+			return err
+		}
+	}
+	if err := s.Ghost.SetPairs(pairs); err != nil {
+		return err
+	}
+	opts.Config = cfg
+	return nil
+}
+
+// Once starts Gofer and the transport, waits until at least one peer
+// subscribes to the price topic, broadcasts prices for all pairs in
+// a single round, and then stops all services. The timeout limits
+// the whole operation.
+func (s *Services) Once(timeout time.Duration) (ghost.RoundStats, error) {
+	defer s.cancelAndWaitOnce()
+	if g, ok := s.Gofer.(gofer.StartableGofer); ok {
+		if err := g.Start(); err != nil {
+			return ghost.RoundStats{}, err
+		}
+	}
+	if err := s.Transport.Start(); err != nil {
+		return ghost.RoundStats{}, err
+	}
+	ctx, ctxCancel := context.WithTimeout(context.Background(), timeout)
+	defer ctxCancel()
+	if p, ok := s.Transport.(peerWaiter); ok {
+		s.Logger.Info("Waiting for peers")
+		if err := p.WaitForPeers(ctx, messages.PriceMessageName, 1); err != nil {
+			return ghost.RoundStats{}, err
+		}
+	}
+	stats, err := s.Ghost.Once(ctx)
+	if err != nil {
+		return stats, err
+	}
+	if _, ok := s.Transport.(peerWaiter); ok && stats.Broadcast > 0 {
+		// Messages are sent to peers asynchronously, so they may be lost
+		// if the transport is stopped immediately:
+		select {
+		case <-ctx.Done():
+		case <-time.After(publishWait):
+		}
+	}
+	return stats, nil
+}
+
+// cancelAndWaitOnce stops services used by the Once method.
+func (s *Services) cancelAndWaitOnce() {
+	s.ctxCancel()
+	s.Transport.Wait()
+	if g, ok := s.Gofer.(gofer.StartableGofer); ok {
+		g.Wait()
+	}
+}
+
 func (s *Services) CancelAndWait() {
 	s.ctxCancel()
 	s.Transport.Wait()
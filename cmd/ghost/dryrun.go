@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+)
+
+// dryRunTransport implements the transport.Transport interface. Instead of
+// sending messages to the network, it writes them to the writer, one
+// message per line. It does not receive any messages.
+type dryRunTransport struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	doneCh chan struct{}
+	w      io.Writer
+}
+
+func newDryRunTransport(ctx context.Context, w io.Writer) *dryRunTransport {
+	return &dryRunTransport{
+		ctx:    ctx,
+		doneCh: make(chan struct{}),
+		w:      w,
+	}
+}
+
+// Broadcast implements the transport.Transport interface.
+func (t *dryRunTransport) Broadcast(_ string, message transport.Message) error {
+	b, err := message.Marshall()
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err = t.w.Write(append(b, '\n'))
+	return err
+}
+
+// Messages implements the transport.Transport interface.
+func (t *dryRunTransport) Messages(_ string) chan transport.ReceivedMessage {
+	return nil
+}
+
+// Start implements the transport.Transport interface.
+func (t *dryRunTransport) Start() error {
+	go func() {
+		<-t.ctx.Done()
+		close(t.doneCh)
+	}()
+	return nil
+}
+
+// Wait implements the transport.Transport interface.
+func (t *dryRunTransport) Wait() {
+	<-t.doneCh
+}
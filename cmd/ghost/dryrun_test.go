@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+func Test_dryRunTransport(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	buf := &bytes.Buffer{}
+	tra := newDryRunTransport(ctx, buf)
+	require.NoError(t, tra.Start())
+
+	for _, wat := range []string{"AAABBB", "XXXYYY"} {
+		price := &oracle.Price{Wat: wat, Age: time.Unix(100, 0)}
+		price.SetFloat64Price(1)
+		require.NoError(t, tra.Broadcast(messages.PriceMessageName, &messages.Price{
+			Price: price,
+			Trace: []byte(`{"type":"aggregator"}`),
+		}))
+	}
+	assert.Nil(t, tra.Messages(messages.PriceMessageName))
+
+	// Every message is written in a separate line:
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	for i, wat := range []string{"AAABBB", "XXXYYY"} {
+		msg := &messages.Price{}
+		require.NoError(t, msg.Unmarshall([]byte(lines[i])))
+		assert.Equal(t, wat, msg.Price.Wat)
+		assert.JSONEq(t, `{"type":"aggregator"}`, string(msg.Trace))
+	}
+
+	ctxCancel()
+	tra.Wait()
+}
@@ -29,12 +29,12 @@ with aggregates that increase reliability in the DeFi environment.`,
 		"log.format",
 		"log format",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&opts.ConfigFilePath,
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&opts.ConfigFilePaths,
 		"config",
 		"c",
-		"./config.json",
-		"config file",
+		[]string{"./config.json"},
+		"config file, may be used multiple times to merge overlay files",
 	)
 	rootCmd.PersistentFlags().VarP(
 		&opts.Format,
@@ -42,6 +42,12 @@ with aggregates that increase reliability in the DeFi environment.`,
 		"f",
 		"output format",
 	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.Expand,
+		"expand",
+		false,
+		"list origin prices under each pair (table and csv formats only)",
+	)
 	rootCmd.PersistentFlags().BoolVar(
 		&opts.NoRPC,
 		"norpc",
@@ -5,16 +5,23 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 )
 
 func NewAgentCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "agent",
 		Args:  cobra.NoArgs,
 		Short: "Start an RPC server",
-		Long:  `Start an RPC server.`,
+		Long: `Start an RPC server.
+
+Price models are reloaded when the SIGHUP signal is received or when config
+files are changed. An invalid config is rejected and the current one is left
+running. Origins are not reloaded, changing them requires a restart.`,
 		RunE: func(_ *cobra.Command, args []string) error {
 			srv, err := PrepareGoferAgentService(context.Background(), opts)
 			if err != nil {
@@ -25,12 +32,37 @@ func NewAgentCmd(opts *options) *cobra.Command {
 			}
 			defer srv.CancelAndWait()
 
-			// Wait for the interrupt signal:
-			c := make(chan os.Signal, 1)
-			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-			<-c
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+			var watchCh <-chan struct{}
+			if opts.ConfigWatch > 0 {
+				watchCh = config.Watch(ctx, opts.ConfigFilePaths, opts.ConfigWatch)
+			}
 
-			return nil
+			// Wait for the interrupt signal, reload the config on SIGHUP:
+			c := make(chan os.Signal, 1)
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+			for {
+				select {
+				case s := <-c:
+					if s != syscall.SIGHUP {
+						return nil
+					}
+				case <-watchCh:
+				}
+				if err := srv.Reload(opts); err != nil {
+					srv.Logger.WithError(err).Error("Unable to reload config, the current config is left running")
+					continue
+				}
+				srv.Logger.Info("Config reloaded")
+			}
 		},
 	}
+	cmd.Flags().DurationVar(
+		&opts.ConfigWatch,
+		"config.watch",
+		10*time.Second,
+		"how often config files are checked for changes, 0 disables watching",
+	)
+	return cmd
 }
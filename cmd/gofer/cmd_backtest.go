@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
+)
+
+type backtestOptions struct {
+	History   string
+	Models    []string
+	Reference string
+	From      string
+	To        string
+}
+
+func NewBacktestCmd(opts *options) *cobra.Command {
+	var backtestOpts backtestOptions
+	cmd := &cobra.Command{
+		Use:   "backtest [PAIR...]",
+		Args:  cobra.MinimumNArgs(0),
+		Short: "Replay recorded prices through price models",
+		Long: `Replay origin prices recorded in the price history through one or more
+price models and compare their results.
+
+Models are loaded from config files given by the --model flag. If no models
+are given, the files specified by the --config flag are used. The first model
+is used as a reference unless the --reference flag is used. The reference
+may also be set to "history" to compare models with aggregator prices
+recorded in the history.
+
+If no pairs are specified, all pairs of the first model are tested.`,
+		RunE: func(c *cobra.Command, args []string) (err error) {
+			mar, err := marshal.NewMarshalWithOptions(opts.Format.format, opts.marshalOptions())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err != nil {
+					exitCode = 1
+					_ = mar.Write(os.Stderr, err)
+				}
+				_ = mar.Flush()
+				// Set err to nil because error was already handled by marshaller.
+				err = nil
+			}()
+
+			// Every model is loaded from a single file, except the default
+			// model, which is loaded from the merged config files.
+			var sources [][]string
+			for _, path := range backtestOpts.Models {
+				sources = append(sources, []string{path})
+			}
+			if len(sources) == 0 {
+				sources = [][]string{opts.ConfigFilePaths}
+			}
+			var models []backtest.Model
+			for _, paths := range sources {
+				var cfg Config
+				if err = config.ParseFiles(&cfg, paths); err != nil {
+					return fmt.Errorf("failed to parse configuration file: %w", err)
+				}
+				name := strings.Join(paths, "+")
+				graphs, err := cfg.Gofer.ConfigurePriceModels()
+				if err != nil {
+					return fmt.Errorf("failed to load models from %s: %w", name, err)
+				}
+				models = append(models, backtest.Model{Name: name, Graphs: graphs})
+				if backtestOpts.History == "" {
+					backtestOpts.History = cfg.Gofer.History.Path
+				}
+			}
+			if backtestOpts.History == "" {
+				return errors.New("the --history flag is required if the price history is not configured")
+			}
+
+			pairs, err := gofer.NewPairs(args...)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			from, err := parseHistoryTime(backtestOpts.From, now)
+			if err != nil {
+				return fmt.Errorf("invalid --from value: %w", err)
+			}
+			to, err := parseHistoryTime(backtestOpts.To, now)
+			if err != nil {
+				return fmt.Errorf("invalid --to value: %w", err)
+			}
+			records, err := history.Load(backtestOpts.History, history.Query{From: from, To: to})
+			if err != nil {
+				return fmt.Errorf("failed to load price history: %w", err)
+			}
+
+			results, err := backtest.Run(backtest.Config{
+				Models:    models,
+				Pairs:     pairs,
+				Reference: backtestOpts.Reference,
+				Records:   records,
+			})
+			if err != nil {
+				return err
+			}
+			for i := range results {
+				if mErr := mar.Write(os.Stdout, &results[i]); mErr != nil {
+					_ = mar.Write(os.Stderr, mErr)
+				}
+			}
+
+			return
+		},
+	}
+	cmd.Flags().StringVar(
+		&backtestOpts.History,
+		"history",
+		"",
+		"price history directory or NDJSON file (default is the history path from the config)",
+	)
+	cmd.Flags().StringArrayVar(
+		&backtestOpts.Models,
+		"model",
+		nil,
+		"config file with price models to test, may be used multiple times",
+	)
+	cmd.Flags().StringVar(
+		&backtestOpts.Reference,
+		"reference",
+		"",
+		"model used as a reference or \"history\" (default is the first model)",
+	)
+	cmd.Flags().StringVar(
+		&backtestOpts.From,
+		"from",
+		"",
+		"start of the time range",
+	)
+	cmd.Flags().StringVar(
+		&backtestOpts.To,
+		"to",
+		"",
+		"end of the time range",
+	)
+	return cmd
+}
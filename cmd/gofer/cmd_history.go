@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
+)
+
+type historyOptions struct {
+	From   string
+	To     string
+	Origin string
+}
+
+func NewHistoryCmd(opts *options) *cobra.Command {
+	var historyOpts historyOptions
+	cmd := &cobra.Command{
+		Use:   "history PAIR",
+		Args:  cobra.ExactArgs(1),
+		Short: "Return recorded prices for given PAIR",
+		Long: `Return prices recorded in the price history for given PAIR.
+
+The --from and --to flags accept a date in the RFC3339 format, a Unix
+timestamp or a duration relative to the current time (e.g. 24h).`,
+		RunE: func(c *cobra.Command, args []string) (err error) {
+			mar, err := marshal.NewMarshalWithOptions(opts.Format.format, opts.marshalOptions())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err != nil {
+					exitCode = 1
+					_ = mar.Write(os.Stderr, err)
+				}
+				_ = mar.Flush()
+				// Set err to nil because error was already handled by marshaller.
+				err = nil
+			}()
+
+			err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
+			if err != nil {
+				return fmt.Errorf("failed to parse configuration file: %w", err)
+			}
+			store, err := opts.Config.Gofer.ConfigureHistoryStore()
+			if err != nil {
+				return err
+			}
+			if store == nil {
+				return errors.New("price history is not enabled in the configuration file")
+			}
+
+			pair, err := gofer.NewPair(args[0])
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			from, err := parseHistoryTime(historyOpts.From, now)
+			if err != nil {
+				return fmt.Errorf("invalid --from value: %w", err)
+			}
+			to, err := parseHistoryTime(historyOpts.To, now)
+			if err != nil {
+				return fmt.Errorf("invalid --to value: %w", err)
+			}
+
+			records, err := store.Query(history.Query{
+				Pair:   pair,
+				From:   from,
+				To:     to,
+				Origin: historyOpts.Origin,
+			})
+			if err != nil {
+				return err
+			}
+			for i := range records {
+				if mErr := mar.Write(os.Stdout, &records[i]); mErr != nil {
+					_ = mar.Write(os.Stderr, mErr)
+				}
+			}
+
+			return
+		},
+	}
+	cmd.Flags().StringVar(
+		&historyOpts.From,
+		"from",
+		"24h",
+		"start of the time range",
+	)
+	cmd.Flags().StringVar(
+		&historyOpts.To,
+		"to",
+		"",
+		"end of the time range (default now)",
+	)
+	cmd.Flags().StringVar(
+		&historyOpts.Origin,
+		"origin",
+		"",
+		"show only prices from given origin",
+	)
+	return cmd
+}
+
+// parseHistoryTime parses a time given as a RFC3339 date, a Unix timestamp
+// or a duration relative to now. An empty string gives a zero time.
+func parseHistoryTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			d = -d
+		}
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("unable to parse %q as a date, timestamp or duration", s)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2021, 5, 18, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "", want: time.Time{}},
+		{in: "2021-05-17T10:30:00Z", want: time.Date(2021, 5, 17, 10, 30, 0, 0, time.UTC)},
+		{in: "1621247400", want: time.Unix(1621247400, 0)},
+		{in: "1h", want: now.Add(-time.Hour)},
+		{in: "-1h", want: now.Add(-time.Hour)},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseHistoryTime(tt.in, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got))
+		})
+	}
+}
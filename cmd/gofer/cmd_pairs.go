@@ -3,14 +3,17 @@ package main
 import (
 	"context"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
 func NewPairsCmd(opts *options) *cobra.Command {
 	return &cobra.Command{
 		Use:     "pairs [PAIR...]",
-		Aliases: []string{"pair"},
+		Aliases: []string{"pair", "models"},
 		Args:    cobra.MinimumNArgs(0),
 		Short:   "List all supported asset pairs",
 		Long:    `List all supported asset pairs.`,
@@ -33,22 +36,29 @@ func NewPairsCmd(opts *options) *cobra.Command {
 			}
 			defer srv.CancelAndWait()
 
-			//pairs, e
-			//rr := gofer.NewPairs(args...)
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//models, err := srv.Gofer.Models(pairs...)
-			//if err != nil {
-			//	return err
-			//}
-			//
-			//for _, p := range models {
-			//	if mErr := srv.Marshaller.Write(os.Stdout, p); mErr != nil {
-			//		_ = srv.Marshaller.Write(os.Stderr, mErr)
-			//	}
-			//}
+			pairs, err := gofer.NewPairs(args...)
+			if err != nil {
+				return err
+			}
+
+			models, err := srv.Gofer.Models(pairs...)
+			if err != nil {
+				return err
+			}
+
+			// Sort models by pair to make the output deterministic:
+			var sorted []gofer.Pair
+			for p := range models {
+				sorted = append(sorted, p)
+			}
+			sort.Slice(sorted, func(i, j int) bool {
+				return sorted[i].String() < sorted[j].String()
+			})
+			for _, p := range sorted {
+				if mErr := srv.Marshaller.Write(os.Stdout, models[p]); mErr != nil {
+					_ = srv.Marshaller.Write(os.Stderr, mErr)
+				}
+			}
 
 			return
 		},
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
 
@@ -45,8 +46,16 @@ func NewPricesCmd(opts *options) *cobra.Command {
 				return err
 			}
 
-			for _, p := range prices {
-				if mErr := srv.Marshaller.Write(os.Stdout, p); mErr != nil {
+			// Sort prices by pair to make the output deterministic:
+			var sorted []gofer.Pair
+			for p := range prices {
+				sorted = append(sorted, p)
+			}
+			sort.Slice(sorted, func(i, j int) bool {
+				return sorted[i].String() < sorted[j].String()
+			})
+			for _, p := range sorted {
+				if mErr := srv.Marshaller.Write(os.Stdout, prices[p]); mErr != nil {
 					_ = srv.Marshaller.Write(os.Stderr, mErr)
 				}
 			}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -11,9 +12,11 @@ import (
 	goferConfig "github.com/toknowwhy/theunit-oracle/internal/config/gofer"
 	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal"
 	pkgGofer "github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/rpc"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	logLogrus "github.com/toknowwhy/theunit-oracle/pkg/log/logrus"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/logrus/formatter"
 )
 
 type Config struct {
@@ -53,7 +56,7 @@ func PrepareGoferClientServices(ctx context.Context, opts *options) (*GoferClien
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -65,7 +68,7 @@ func PrepareGoferClientServices(ctx context.Context, opts *options) (*GoferClien
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
@@ -73,7 +76,7 @@ func PrepareGoferClientServices(ctx context.Context, opts *options) (*GoferClien
 	if err != nil {
 		return nil, fmt.Errorf("failed to load Gofer configuration1: %w", err)
 	}
-	mar, err := marshal.NewMarshal(opts.Format.format)
+	mar, err := marshal.NewMarshalWithOptions(opts.Format.format, opts.marshalOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +105,7 @@ func (s *GoferClientServices) CancelAndWait() {
 type GoferAgentService struct {
 	ctxCancel context.CancelFunc
 	Agent     *rpc.Agent
+	Logger    log.Logger
 }
 
 func PrepareGoferAgentService(ctx context.Context, opts *options) (*GoferAgentService, error) {
@@ -114,7 +118,7 @@ func PrepareGoferAgentService(ctx context.Context, opts *options) (*GoferAgentSe
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -126,7 +130,7 @@ func PrepareGoferAgentService(ctx context.Context, opts *options) (*GoferAgentSe
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
@@ -138,6 +142,7 @@ func PrepareGoferAgentService(ctx context.Context, opts *options) (*GoferAgentSe
 	return &GoferAgentService{
 		ctxCancel: ctxCancel,
 		Agent:     age,
+		Logger:    logger,
 	}, nil
 }
 
@@ -145,6 +150,30 @@ func (s *GoferAgentService) Start() error {
 	return s.Agent.Start()
 }
 
+// Reload parses config files again and replaces price models with the new
+// ones. If the new config is invalid, an error is returned and the current
+// config is left running. Changes in other config sections require
+// a restart.
+func (s *GoferAgentService) Reload(opts *options) error {
+	var cfg Config
+	if err := config.ParseFiles(&cfg, opts.ConfigFilePaths); err != nil {
+		return fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	u, ok := s.Agent.Gofer().(graph.Updater)
+	if !ok {
+		return errors.New("gofer does not support reloading price models")
+	}
+	graphs, err := opts.Config.Gofer.ReloadPriceModels(&cfg.Gofer)
+	if err != nil {
+		return err
+	}
+	if err := u.UpdateGraphs(graphs); err != nil {
+		return err
+	}
+	opts.Config = cfg
+	return nil
+}
+
 func (s *GoferAgentService) CancelAndWait() {
 	s.ctxCancel()
 	s.Agent.Wait()
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+)
+
+// sharedConfig is a config file shared by all binaries. Sections which are
+// not used by Gofer must not be reported as unknown fields.
+const sharedConfig = `{
+	"gofer": {},
+	"ethereum": {},
+	"transport": {"foo": "bar"},
+	"ghost": {"foo": "bar"},
+	"spectre": {"foo": "bar"},
+	"spire": {"foo": "bar"},
+	"feeds": ["0x2d800d93b065ce011af83f316cef9f0d005b0aa4"],
+	"stark": {"oracleName": "Maker"}
+}`
+
+func TestConfig_ValidateSharedConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shared.json")
+	require.NoError(t, os.WriteFile(path, []byte(sharedConfig), 0600))
+
+	var cfg Config
+	paths := []string{path}
+	cmd := config.NewCommand(&cfg, &paths)
+	cmd.SetArgs([]string{"validate"})
+	require.NoError(t, cmd.Execute())
+}
+
+func TestConfig_ValidateSampleConfig(t *testing.T) {
+	var cfg Config
+	paths := []string{"../../config.json"}
+	cmd := config.NewCommand(&cfg, &paths)
+	cmd.SetArgs([]string{"validate"})
+	require.NoError(t, cmd.Execute())
+}
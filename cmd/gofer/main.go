@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 )
 
 // exitCode to be returned by the application.
@@ -19,6 +21,9 @@ func main() {
 		NewPricesCmd(&opts),
 		NewAgentCmd(&opts),
 		NewSupplyCmd(&opts),
+		NewHistoryCmd(&opts),
+		NewBacktestCmd(&opts),
+		config.NewCommand(&opts.Config, &opts.ConfigFilePaths),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
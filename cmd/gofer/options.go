@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	logrusFlag "github.com/toknowwhy/theunit-oracle/pkg/log/logrus/flag"
 
@@ -11,20 +12,26 @@ import (
 
 // These are the command options that can be set by CLI flags.
 type options struct {
-	LogVerbosity   string
-	LogFormat      logrusFlag.FormatTypeValue
-	ConfigFilePath string
-	Format         formatTypeValue
-	Config         Config
-	NoRPC          bool
-	Version        string
+	LogVerbosity    string
+	LogFormat       logrusFlag.FormatTypeValue
+	ConfigFilePaths []string
+	ConfigWatch     time.Duration
+	Format          formatTypeValue
+	Expand          bool
+	Config          Config
+	NoRPC           bool
+	Version         string
 }
 
 var formatMap = map[marshal.FormatType]string{
-	marshal.Plain:  "plain",
-	marshal.Trace:  "trace",
-	marshal.JSON:   "json",
-	marshal.NDJSON: "ndjson",
+	marshal.Plain:   "plain",
+	marshal.Trace:   "trace",
+	marshal.JSON:    "json",
+	marshal.NDJSON:  "ndjson",
+	marshal.CSV:     "csv",
+	marshal.Dot:     "dot",
+	marshal.Mermaid: "mermaid",
+	marshal.Table:   "table",
 }
 
 // formatTypeValue is a wrapper for the FormatType to allow implement
@@ -56,5 +63,10 @@ func (v *formatTypeValue) Set(s string) error {
 }
 
 func (v *formatTypeValue) Type() string {
-	return "plain|trace|json|ndjson"
+	return "plain|trace|json|ndjson|csv|table|dot|mermaid"
+}
+
+// marshalOptions returns options for marshallers set by CLI flags.
+func (o *options) marshalOptions() marshal.Options {
+	return marshal.Options{Expand: o.Expand}
 }
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	logrusFlag "github.com/toknowwhy/theunit-oracle/pkg/log/logrus/flag"
 )
 
 type options struct {
-	LogVerbosity   string
-	LogFormat      logrusFlag.FormatTypeValue
-	ConfigFilePath string
-	Config         Config
+	LogVerbosity    string
+	LogFormat       logrusFlag.FormatTypeValue
+	ConfigFilePaths []string
+	Config          Config
+	DryRun          bool
+	DryRunWait      time.Duration
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -34,12 +38,12 @@ func NewRootCommand(opts *options) *cobra.Command {
 		"log.format",
 		"log format",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&opts.ConfigFilePath,
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&opts.ConfigFilePaths,
 		"config",
 		"c",
-		"./config.json",
-		"spectre config file",
+		[]string{"./config.json"},
+		"spectre config file, may be used multiple times to merge overlay files",
 	)
 
 	return rootCmd
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	oracleGeth "github.com/toknowwhy/theunit-oracle/pkg/oracle/geth"
+)
+
+type medianOptions struct {
+	Yes      bool
+	Calldata bool
+}
+
+func NewMedianCmd(opts *options) *cobra.Command {
+	var medianOpts medianOptions
+
+	cmd := &cobra.Command{
+		Use:   "median",
+		Short: "Manage Oracle contracts",
+		Long: `Manage Oracle contracts. The Oracle may be given either as a contract address or as a name of a pair
+from the spectre.medianizers config section.
+
+Transactions are always simulated first and they are sent only if the --yes flag is used. With the --calldata
+flag, the calldata is printed instead, so it can be submitted using a different account, e.g. a multisig wallet.`,
+	}
+
+	cmd.PersistentFlags().BoolVar(
+		&medianOpts.Yes,
+		"yes",
+		false,
+		"send the transaction after a successful simulation",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&medianOpts.Calldata,
+		"calldata",
+		false,
+		"print the calldata instead of simulating and sending the transaction",
+	)
+
+	cmd.AddCommand(
+		NewMedianFeedsCmd(opts),
+		NewMedianLiftCmd(opts, &medianOpts),
+		NewMedianDropCmd(opts, &medianOpts),
+		NewMedianSetBarCmd(opts, &medianOpts),
+	)
+
+	return cmd
+}
+
+func NewMedianFeedsCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "feeds ORACLE",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print feeds lifted in the Oracle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := prepareMedian(opts, args[0])
+			if err != nil {
+				return err
+			}
+			feeds, err := m.Feeds(context.Background())
+			if err != nil {
+				return err
+			}
+			for _, f := range feeds {
+				fmt.Fprintln(cmd.OutOrStdout(), f.String())
+			}
+			return nil
+		},
+	}
+}
+
+func NewMedianLiftCmd(opts *options, medianOpts *medianOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lift ORACLE ADDRESS...",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "Add feeds to the Oracle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addresses, err := parseAddresses(args[1:])
+			if err != nil {
+				return err
+			}
+			m, err := prepareMedian(opts, args[0])
+			if err != nil {
+				return err
+			}
+			cd, err := m.LiftCalldata(addresses)
+			if err != nil {
+				return err
+			}
+			return runMedianTx(cmd.OutOrStdout(), m, cd, medianOpts, func(ctx context.Context) (*ethereum.Hash, error) {
+				return m.Lift(ctx, addresses, false)
+			})
+		},
+	}
+}
+
+func NewMedianDropCmd(opts *options, medianOpts *medianOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "drop ORACLE ADDRESS...",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "Remove feeds from the Oracle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addresses, err := parseAddresses(args[1:])
+			if err != nil {
+				return err
+			}
+			m, err := prepareMedian(opts, args[0])
+			if err != nil {
+				return err
+			}
+			cd, err := m.DropCalldata(addresses)
+			if err != nil {
+				return err
+			}
+			return runMedianTx(cmd.OutOrStdout(), m, cd, medianOpts, func(ctx context.Context) (*ethereum.Hash, error) {
+				return m.Drop(ctx, addresses, false)
+			})
+		},
+	}
+}
+
+func NewMedianSetBarCmd(opts *options, medianOpts *medianOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-bar ORACLE BAR",
+		Args:  cobra.ExactArgs(2),
+		Short: "Set the minimum number of prices required to update the Oracle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bar, ok := new(big.Int).SetString(args[1], 10)
+			if !ok || bar.Sign() <= 0 {
+				return fmt.Errorf("invalid bar %q, it must be a positive integer", args[1])
+			}
+			m, err := prepareMedian(opts, args[0])
+			if err != nil {
+				return err
+			}
+			cd, err := m.SetBarCalldata(bar)
+			if err != nil {
+				return err
+			}
+			return runMedianTx(cmd.OutOrStdout(), m, cd, medianOpts, func(ctx context.Context) (*ethereum.Hash, error) {
+				return m.SetBar(ctx, bar, false)
+			})
+		},
+	}
+}
+
+func prepareMedian(opts *options, oracle string) (*oracleGeth.Median, error) {
+	if err := config.ParseFiles(&opts.Config, opts.ConfigFilePaths); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	return opts.Config.ConfigureMedian(oracle)
+}
+
+func parseAddresses(args []string) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, arg := range args {
+		if !ethereum.IsHexAddress(arg) {
+			return nil, fmt.Errorf("invalid Ethereum address %q", arg)
+		}
+		addresses = append(addresses, ethereum.HexToAddress(arg))
+	}
+	return addresses, nil
+}
+
+// runMedianTx prints the calldata, or simulates the transaction and sends
+// it using the send function if the --yes flag is used.
+func runMedianTx(
+	w io.Writer,
+	m *oracleGeth.Median,
+	calldata []byte,
+	medianOpts *medianOptions,
+	send func(ctx context.Context) (*ethereum.Hash, error),
+) error {
+	if medianOpts.Calldata {
+		fmt.Fprintf(w, "to:   %s\n", m.Address().String())
+		fmt.Fprintf(w, "data: 0x%s\n", hex.EncodeToString(calldata))
+		return nil
+	}
+	ctx := context.Background()
+	if err := m.Simulate(ctx, calldata); err != nil {
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+	if !medianOpts.Yes {
+		fmt.Fprintln(w, "Simulation succeeded. Use the --yes flag to send the transaction.")
+		return nil
+	}
+	tx, err := send(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Transaction sent: %s\n", tx.String())
+	return nil
+}
@@ -5,32 +5,76 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 func NewRunCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "run",
 		Args:    cobra.ExactArgs(0),
 		Aliases: []string{"agent"},
 		Short:   "",
 		Long:    ``,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			srv, err := PrepareServices(context.Background(), opts)
 			if err != nil {
 				return err
 			}
-			if err = srv.Start(); err != nil {
+			if opts.DryRun {
+				err = srv.StartPricesOnly()
+			} else {
+				err = srv.Start()
+			}
+			if err != nil {
 				return err
 			}
 			defer srv.CancelAndWait()
 
 			c := make(chan os.Signal, 1)
 			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-			<-c
 
-			return nil
+			if opts.DryRun {
+				// Wait for prices from feeders before evaluating Oracles:
+				select {
+				case <-c:
+					return nil
+				case <-time.After(opts.DryRunWait):
+				}
+				printDryRun(cmd.OutOrStdout(), srv.Spectre.DryRun())
+				return nil
+			}
+
+			// SIGHUP refreshes feeds fetched from Oracle contracts, so
+			// changes made by the lift or drop methods take effect
+			// immediately:
+			hup := make(chan os.Signal, 1)
+			signal.Notify(hup, syscall.SIGHUP)
+			for {
+				select {
+				case <-c:
+					return nil
+				case <-hup:
+					// Errors are logged by the datastore:
+					_ = srv.RefreshFeeds(context.Background())
+				}
+			}
 		},
 	}
+
+	cmd.Flags().BoolVar(
+		&opts.DryRun,
+		"dry-run",
+		false,
+		"evaluate all Oracles once and simulate updates without sending transactions",
+	)
+	cmd.Flags().DurationVar(
+		&opts.DryRunWait,
+		"dry-run.wait",
+		time.Minute,
+		"time to wait for prices from feeders before evaluating Oracles in the dry run mode",
+	)
+
+	return cmd
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/spectre"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+type statusOptions struct {
+	Format string
+	Source string
+	Wait   time.Duration
+}
+
+func NewStatusCmd(opts *options) *cobra.Command {
+	var statusOpts statusOptions
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Args:  cobra.ExactArgs(0),
+		Short: "Print the state of all Oracles and prices available to update them",
+		Long:  ``,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if statusOpts.Format != "table" && statusOpts.Format != "json" {
+				return fmt.Errorf("unsupported format %q, must be either table or json", statusOpts.Format)
+			}
+			if statusOpts.Source != "datastore" && statusOpts.Source != "spire" {
+				return fmt.Errorf("unsupported source %q, must be either datastore or spire", statusOpts.Source)
+			}
+
+			srv, err := PrepareServices(context.Background(), opts)
+			if err != nil {
+				return err
+			}
+
+			var prices []*messages.Price
+			switch statusOpts.Source {
+			case "spire":
+				if err = srv.StartSpireClient(); err != nil {
+					return err
+				}
+				defer srv.CancelAndWait()
+				if prices, err = srv.SpireClient.PullPrices("", ""); err != nil {
+					return err
+				}
+			case "datastore":
+				if err = srv.StartPricesOnly(); err != nil {
+					return err
+				}
+				defer srv.CancelAndWait()
+
+				// Wait for prices from feeders:
+				c := make(chan os.Signal, 1)
+				signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+				select {
+				case <-c:
+					return nil
+				case <-time.After(statusOpts.Wait):
+				}
+				for _, msg := range srv.Datastore.Prices().All() {
+					prices = append(prices, msg)
+				}
+			}
+
+			statuses := srv.Spectre.Status(prices)
+			if statusOpts.Format == "json" {
+				return printStatusJSON(cmd.OutOrStdout(), statuses)
+			}
+			printStatusTable(cmd.OutOrStdout(), statuses)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&statusOpts.Format,
+		"format",
+		"table",
+		"output format: table or json",
+	)
+	cmd.Flags().StringVar(
+		&statusOpts.Source,
+		"source",
+		"datastore",
+		"source of prices: datastore collects prices from the network, spire pulls them from the Spire agent",
+	)
+	cmd.Flags().DurationVar(
+		&statusOpts.Wait,
+		"wait",
+		time.Minute,
+		"time to wait for prices from feeders if the datastore source is used",
+	)
+
+	return cmd
+}
+
+func printStatusTable(w io.Writer, statuses []*spectre.OracleStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PAIR\tVAL\tAGE\tEXPIRES IN\tSPREAD\tPRICES\tINACTIVE FEEDS")
+	for _, st := range statuses {
+		if st.Err != nil {
+			fmt.Fprintf(tw, "%s\terror: %s\n", st.AssetPair, st.Err)
+			continue
+		}
+		var inactive []string
+		for _, f := range st.InactiveFeeds {
+			inactive = append(inactive, f.String())
+		}
+		expiresIn := st.TimeToExpiration.Round(time.Second).String()
+		if st.Expired() {
+			expiresIn = "expired"
+		}
+		spread := "n/a"
+		if !math.IsInf(st.Spread, 0) {
+			spread = fmt.Sprintf("%.4f%%", st.Spread)
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s / %g%%\t%d / %d\t%s\n",
+			st.AssetPair,
+			decimal.NewFromBigInt(st.Val, -oracle.PriceDecimals),
+			st.Age.UTC().Format(time.RFC3339),
+			expiresIn,
+			spread,
+			st.OracleSpread,
+			st.ValidPrices,
+			st.Bar,
+			strings.Join(inactive, ","),
+		)
+	}
+	_ = tw.Flush()
+}
+
+// jsonStatus is the JSON representation of the spectre.OracleStatus.
+type jsonStatus struct {
+	AssetPair        string   `json:"assetPair"`
+	Contract         string   `json:"contract"`
+	Error            string   `json:"error,omitempty"`
+	Wat              string   `json:"wat,omitempty"`
+	Val              string   `json:"val,omitempty"`
+	Age              int64    `json:"age,omitempty"`
+	Bar              int64    `json:"bar,omitempty"`
+	Feeds            []string `json:"feeds,omitempty"`
+	OracleExpiration int64    `json:"oracleExpiration,omitempty"`
+	TimeToExpiration int64    `json:"timeToExpiration,omitempty"`
+	Expired          bool     `json:"expired"`
+	OracleSpread     float64  `json:"oracleSpread,omitempty"`
+	Spread           *float64 `json:"spread"`
+	Stale            bool     `json:"stale"`
+	ValidPrices      int      `json:"validPrices"`
+	Quorum           bool     `json:"quorum"`
+	InactiveFeeds    []string `json:"inactiveFeeds"`
+}
+
+func printStatusJSON(w io.Writer, statuses []*spectre.OracleStatus) error {
+	list := make([]jsonStatus, 0, len(statuses))
+	for _, st := range statuses {
+		j := jsonStatus{
+			AssetPair: st.AssetPair,
+			Contract:  st.Contract.String(),
+		}
+		if st.Err != nil {
+			j.Error = st.Err.Error()
+			list = append(list, j)
+			continue
+		}
+		j.Wat = st.Wat
+		j.Val = decimal.NewFromBigInt(st.Val, -oracle.PriceDecimals).String()
+		j.Age = st.Age.Unix()
+		j.Bar = st.Bar
+		for _, f := range st.Feeds {
+			j.Feeds = append(j.Feeds, f.String())
+		}
+		j.OracleExpiration = int64(st.OracleExpiration.Seconds())
+		j.TimeToExpiration = int64(st.TimeToExpiration.Seconds())
+		j.Expired = st.Expired()
+		j.OracleSpread = st.OracleSpread
+		if !math.IsInf(st.Spread, 0) {
+			spread := st.Spread
+			j.Spread = &spread
+		}
+		j.Stale = st.Stale()
+		j.ValidPrices = st.ValidPrices
+		j.Quorum = st.Quorum()
+		j.InactiveFeeds = []string{}
+		for _, f := range st.InactiveFeeds {
+			j.InactiveFeeds = append(j.InactiveFeeds, f.String())
+		}
+		list = append(list, j)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
@@ -10,11 +10,18 @@ import (
 	ethereumConfig "github.com/toknowwhy/theunit-oracle/internal/config/ethereum"
 	feedsConfig "github.com/toknowwhy/theunit-oracle/internal/config/feeds"
 	spectreConfig "github.com/toknowwhy/theunit-oracle/internal/config/spectre"
+	spireConfig "github.com/toknowwhy/theunit-oracle/internal/config/spire"
+	starkConfig "github.com/toknowwhy/theunit-oracle/internal/config/stark"
 	transportConfig "github.com/toknowwhy/theunit-oracle/internal/config/transport"
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/txmanager"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	logLogrus "github.com/toknowwhy/theunit-oracle/pkg/log/logrus"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/logrus/formatter"
+	oracleGeth "github.com/toknowwhy/theunit-oracle/pkg/oracle/geth"
 	"github.com/toknowwhy/theunit-oracle/pkg/spectre"
+	"github.com/toknowwhy/theunit-oracle/pkg/spire"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 )
 
@@ -22,7 +29,9 @@ type Config struct {
 	Transport transportConfig.Transport `json:"transport"`
 	Ethereum  ethereumConfig.Ethereum   `json:"ethereum"`
 	Spectre   spectreConfig.Spectre     `json:"spectre"`
+	Spire     spireConfig.Spire         `json:"spire"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Stark     starkConfig.Stark         `json:"stark"`
 }
 
 type Dependencies struct {
@@ -30,56 +39,125 @@ type Dependencies struct {
 	Logger  log.Logger
 }
 
-func (c *Config) Configure(d Dependencies) (transport.Transport, datastore.Datastore, *spectre.Spectre, error) {
+func (c *Config) Configure(d Dependencies) (
+	transport.Transport,
+	datastore.Datastore,
+	*txmanager.Manager,
+	*spectre.Spectre,
+	*spire.Client,
+	error,
+) {
+
 	sig, err := c.Ethereum.ConfigureSigner()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 	cli, err := c.Ethereum.ConfigureEthereumClient(sig)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
+	}
+	txm, err := c.Spectre.ConfigureTxManager(spectreConfig.TxManagerDependencies{
+		Context:        d.Context,
+		Signer:         sig,
+		EthereumClient: cli,
+		Nonces:         cli.NonceManager(),
+		Logger:         d.Logger,
+	})
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
 	}
 	fed, err := c.Feeds.Addresses()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 	tra, err := c.Transport.Configure(transportConfig.Dependencies{
-		Context: d.Context,
-		Signer:  sig,
-		Feeds:   fed,
-		Logger:  d.Logger,
+		Context:         d.Context,
+		Signer:          sig,
+		Feeds:           fed,
+		Logger:          d.Logger,
+		StarkOracleName: c.Stark.OracleName,
+		Topics:          c.Spectre.Topics(),
 	})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 	dat, err := c.Spectre.ConfigureDatastore(spectreConfig.DatastoreDependencies{
-		Context:   d.Context,
-		Signer:    sig,
-		Transport: tra,
-		Feeds:     fed,
-		Logger:    d.Logger,
+		Context:         d.Context,
+		Signer:          sig,
+		Transport:       tra,
+		EthereumClient:  cli,
+		Feeds:           fed,
+		Logger:          d.Logger,
+		StarkOracleName: c.Stark.OracleName,
 	})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 	spe, err := c.Spectre.ConfigureSpectre(spectreConfig.Dependencies{
 		Context:        d.Context,
 		Signer:         sig,
 		Datastore:      dat,
-		EthereumClient: cli,
+		EthereumClient: txm,
+		TxStatus:       txm,
+		Transport:      tra,
 		Logger:         d.Logger,
 	})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
-	return tra, dat, spe, nil
+	spi, err := c.Spire.ConfigureClient(spireConfig.ClientDependencies{
+		Context: d.Context,
+		Signer:  sig,
+	})
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	return tra, dat, txm, spe, spi, nil
 }
 
+// ConfigureMedian returns the Median instance for the Oracle given either
+// as a contract address or as a name of a pair from the spectre.medianizers
+// config section. Gas is always estimated, because administrative
+// transactions may need much more gas than Oracle updates.
+func (c *Config) ConfigureMedian(oracle string) (*oracleGeth.Median, error) {
+	var address ethereum.Address
+	switch m, ok := c.Spectre.Medianizers[oracle]; {
+	case ok:
+		address = ethereum.HexToAddress(m.Contract)
+	case ethereum.IsHexAddress(oracle):
+		address = ethereum.HexToAddress(oracle)
+	default:
+		return nil, fmt.Errorf("%q is neither an Oracle address nor a configured pair", oracle)
+	}
+	sig, err := c.Ethereum.ConfigureSigner()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := c.Ethereum.ConfigureEthereumClient(sig)
+	if err != nil {
+		return nil, err
+	}
+	return oracleGeth.NewMedianWithGas(cli, address, oracleGeth.GasConfig{
+		EstimateGas:   true,
+		GasMultiplier: adminGasMultiplier,
+	}), nil
+}
+
+// adminGasMultiplier is applied to the estimated gas of administrative
+// transactions.
+const adminGasMultiplier = 1.25
+
 type Services struct {
 	ctxCancel context.CancelFunc
+	// started is the list of started services.
+	started   []interface{ Wait() }
 	Transport transport.Transport
 	Datastore datastore.Datastore
+	TxManager *txmanager.Manager
 	Spectre   *spectre.Spectre
+	// SpireClient is a client for the Spire agent configured in the spire
+	// section. It is not started by default.
+	SpireClient *spire.Client
 }
 
 func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
@@ -92,7 +170,7 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -104,11 +182,11 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
-	tra, dat, spe, err := opts.Config.Configure(Dependencies{
+	tra, dat, txm, spe, spi, err := opts.Config.Configure(Dependencies{
 		Context: ctx,
 		Logger:  logger,
 	})
@@ -117,30 +195,59 @@ func PrepareServices(ctx context.Context, opts *options) (*Services, error) {
 	}
 
 	return &Services{
-		ctxCancel: ctxCancel,
-		Transport: tra,
-		Datastore: dat,
-		Spectre:   spe,
+		ctxCancel:   ctxCancel,
+		Transport:   tra,
+		Datastore:   dat,
+		TxManager:   txm,
+		Spectre:     spe,
+		SpireClient: spi,
 	}, nil
 }
 
 func (s *Services) Start() error {
-	var err error
-	if err = s.Transport.Start(); err != nil {
-		return err
-	}
-	if err = s.Datastore.Start(); err != nil {
-		return err
+	return s.start(s.Transport, s.Datastore, s.TxManager, s.Spectre)
+}
+
+// StartPricesOnly starts only services needed to collect prices from
+// feeders. Spectre may be used then to evaluate Oracles, but it does not
+// update them.
+func (s *Services) StartPricesOnly() error {
+	return s.start(s.Transport, s.Datastore)
+}
+
+// StartSpireClient starts only the Spire client. Spectre may be used then
+// to evaluate Oracles using prices from the Spire agent.
+func (s *Services) StartSpireClient() error {
+	return s.start(s.SpireClient)
+}
+
+func (s *Services) start(services ...interface {
+	Start() error
+	Wait()
+}) error {
+	for _, srv := range services {
+		if err := srv.Start(); err != nil {
+			return err
+		}
+		s.started = append(s.started, srv)
 	}
-	if err = s.Spectre.Start(); err != nil {
-		return err
+	return nil
+}
+
+// RefreshFeeds refreshes lists of feeds allowed to send prices, if
+// the datastore supports it.
+func (s *Services) RefreshFeeds(ctx context.Context) error {
+	if r, ok := s.Datastore.(interface {
+		RefreshFeeds(ctx context.Context) error
+	}); ok {
+		return r.RefreshFeeds(ctx)
 	}
 	return nil
 }
 
 func (s *Services) CancelAndWait() {
 	s.ctxCancel()
-	s.Transport.Wait()
-	s.Datastore.Wait()
-	s.Spectre.Wait()
+	for _, srv := range s.started {
+		srv.Wait()
+	}
 }
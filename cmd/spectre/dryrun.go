@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/spectre"
+)
+
+// printDryRun prints results of the dry run in a human-readable form.
+func printDryRun(w io.Writer, results []*spectre.DryRunResult) {
+	for _, r := range results {
+		fmt.Fprintf(w, "%s: %s\n", r.AssetPair, dryRunDecision(r))
+		if r.Err != nil {
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+		fmt.Fprintf(tw, "  bar:\t%d\n", r.Bar)
+		fmt.Fprintf(tw, "  age:\t%s (%s ago)\n", r.Age.UTC().Format(time.RFC3339), time.Since(r.Age).Round(time.Second))
+		fmt.Fprintf(tw, "  val:\t%s\n", decimal.NewFromBigInt(r.Val, -oracle.PriceDecimals))
+		fmt.Fprintf(tw, "  spread:\t%g%%\n", r.Spread)
+		_ = tw.Flush()
+		printDryRunPrices(w, "prices", r.Prices)
+		printDryRunPrices(w, "excluded", r.Excluded)
+		if r.Calldata != nil {
+			fmt.Fprintf(w, "  calldata: 0x%s\n", hex.EncodeToString(r.Calldata))
+		}
+		switch {
+		case r.Simulation != nil:
+			fmt.Fprintf(w, "  simulation: failed: %s\n", r.Simulation)
+		case r.Calldata != nil:
+			fmt.Fprintln(w, "  simulation: ok")
+		}
+	}
+}
+
+func printDryRunPrices(w io.Writer, name string, prices []spectre.DryRunPrice) {
+	if len(prices) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  %s:\n", name)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range prices {
+		feeder := "unknown"
+		if p.Feeder != nil {
+			feeder = p.Feeder.String()
+		}
+		fmt.Fprintf(tw, "    %s\t%s\t%s\n", feeder, p.Price.DecimalPrice(), p.Price.Age.UTC().Format(time.RFC3339))
+	}
+	_ = tw.Flush()
+}
+
+// dryRunDecision describes whether the Oracle would be updated and why.
+func dryRunDecision(r *spectre.DryRunResult) string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("unable to evaluate the Oracle: %s", r.Err)
+	case !r.Expired && !r.Stale:
+		return "no update, the Oracle price is still valid"
+	case !r.Quorum:
+		return fmt.Sprintf("no update, not enough prices to achieve a quorum (%d of %d)", len(r.Prices), r.Bar)
+	case r.Coordination != nil:
+		return fmt.Sprintf("no update, %s", r.Coordination)
+	}
+	var reasons []string
+	if r.Expired {
+		reasons = append(reasons, "expired")
+	}
+	if r.Stale {
+		reasons = append(reasons, "stale")
+	}
+	return fmt.Sprintf("update (%s)", strings.Join(reasons, ", "))
+}
@@ -7,10 +7,10 @@ import (
 )
 
 type options struct {
-	LogVerbosity   string
-	LogFormat      logrusFlag.FormatTypeValue
-	ConfigFilePath string
-	Config         Config
+	LogVerbosity    string
+	LogFormat       logrusFlag.FormatTypeValue
+	ConfigFilePaths []string
+	Config          Config
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -34,11 +34,11 @@ func NewRootCommand(opts *options) *cobra.Command {
 		"log.format",
 		"log format",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&opts.ConfigFilePath,
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&opts.ConfigFilePaths,
 		"config", "c",
-		"./config.json",
-		"ghost config file",
+		[]string{"./config.json"},
+		"ghost config file, may be used multiple times to merge overlay files",
 	)
 
 	return rootCmd
@@ -8,6 +8,7 @@ import (
 	"github.com/toknowwhy/theunit-oracle/internal/config"
 	transportConfig "github.com/toknowwhy/theunit-oracle/internal/config/transport"
 	logLogrus "github.com/toknowwhy/theunit-oracle/pkg/log/logrus"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/logrus/formatter"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
@@ -48,7 +49,7 @@ func PrepareService(ctx context.Context, opts *options) (*Service, error) {
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +61,7 @@ func PrepareService(ctx context.Context, opts *options) (*Service, error) {
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 )
 
 func main() {
@@ -10,6 +12,7 @@ func main() {
 
 	rootCmd.AddCommand(
 		NewRunCmd(&opts),
+		config.NewCommand(&opts.Config, &opts.ConfigFilePaths),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
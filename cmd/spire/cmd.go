@@ -3,15 +3,16 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 	logrusFlag "github.com/toknowwhy/theunit-oracle/pkg/log/logrus/flag"
 )
 
 type options struct {
-	LogVerbosity   string
-	LogFormat      logrusFlag.FormatTypeValue
-	ConfigFilePath string
-	Config         Config
-	Version        string
+	LogVerbosity    string
+	LogFormat       logrusFlag.FormatTypeValue
+	ConfigFilePaths []string
+	Config          Config
+	Version         string
 }
 
 func NewRootCommand(opts *options) *cobra.Command {
@@ -35,18 +36,19 @@ func NewRootCommand(opts *options) *cobra.Command {
 		"log.format",
 		"log format",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&opts.ConfigFilePath,
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&opts.ConfigFilePaths,
 		"config",
 		"c",
-		"./config.json",
-		"spire config file",
+		[]string{"./config.json"},
+		"spire config file, may be used multiple times to merge overlay files",
 	)
 
 	rootCmd.AddCommand(
 		NewAgentCmd(opts),
 		NewPullCmd(opts),
 		NewPushCmd(opts),
+		config.NewCommand(&opts.Config, &opts.ConfigFilePaths),
 	)
 
 	return rootCmd
@@ -10,10 +10,12 @@ import (
 	ethereumConfig "github.com/toknowwhy/theunit-oracle/internal/config/ethereum"
 	feedsConfig "github.com/toknowwhy/theunit-oracle/internal/config/feeds"
 	spireConfig "github.com/toknowwhy/theunit-oracle/internal/config/spire"
+	starkConfig "github.com/toknowwhy/theunit-oracle/internal/config/stark"
 	transportConfig "github.com/toknowwhy/theunit-oracle/internal/config/transport"
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	logLogrus "github.com/toknowwhy/theunit-oracle/pkg/log/logrus"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/logrus/formatter"
 	"github.com/toknowwhy/theunit-oracle/pkg/spire"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 )
@@ -23,6 +25,7 @@ type Config struct {
 	Ethereum  ethereumConfig.Ethereum   `json:"ethereum"`
 	Spire     spireConfig.Spire         `json:"spire"`
 	Feeds     feedsConfig.Feeds         `json:"feeds"`
+	Stark     starkConfig.Stark         `json:"stark"`
 }
 
 type ClientDependencies struct {
@@ -59,20 +62,22 @@ func (c *Config) ConfigureAgent(d AgentDependencies) (transport.Transport, datas
 		return nil, nil, nil, err
 	}
 	tra, err := c.Transport.Configure(transportConfig.Dependencies{
-		Context: d.Context,
-		Signer:  sig,
-		Feeds:   fed,
-		Logger:  d.Logger,
+		Context:         d.Context,
+		Signer:          sig,
+		Feeds:           fed,
+		Logger:          d.Logger,
+		StarkOracleName: c.Stark.OracleName,
 	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	dat, err := c.Spire.ConfigureDatastore(spireConfig.DatastoreDependencies{
-		Context:   d.Context,
-		Signer:    sig,
-		Transport: tra,
-		Feeds:     fed,
-		Logger:    d.Logger,
+		Context:         d.Context,
+		Signer:          sig,
+		Transport:       tra,
+		Feeds:           fed,
+		Logger:          d.Logger,
+		StarkOracleName: c.Stark.OracleName,
 	})
 	if err != nil {
 		return nil, nil, nil, err
@@ -106,7 +111,7 @@ func PrepareClientServices(ctx context.Context, opts *options) (*ClientServices,
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -155,7 +160,7 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*AgentServices, e
 	}()
 
 	// Load config file:
-	err = config.ParseFile(&opts.Config, opts.ConfigFilePath)
+	err = config.ParseFiles(&opts.Config, opts.ConfigFilePaths)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
 	}
@@ -167,7 +172,7 @@ func PrepareAgentServices(ctx context.Context, opts *options) (*AgentServices, e
 	}
 	lr := logrus.New()
 	lr.SetLevel(ll)
-	lr.SetFormatter(opts.LogFormat.Formatter())
+	lr.SetFormatter(&formatter.RedactFormatter{Formatter: opts.LogFormat.Formatter(), Redact: config.Redact})
 	logger := logLogrus.New(lr)
 
 	// Services:
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the "config" command, shared by all binaries. The out
+// argument is a config structure of the binary and paths points to the list
+// of config files given by the user.
+func NewCommand(out interface{}, paths *[]string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Args:  cobra.NoArgs,
+		Short: "Commands related to config files",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "validate",
+			Args:  cobra.NoArgs,
+			Short: "Validate config files",
+			Long: `Validate config files given by the --config flag.
+
+All problems found in the config are reported together with their JSON paths. The user is not asked for
+secrets, so the content of such secrets is not validated.`,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				if err := parseForCommand(out, *paths); err != nil {
+					return err
+				}
+				fmt.Println("The config is valid.")
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "show",
+			Args:  cobra.NoArgs,
+			Short: "Print the config after merging all config files",
+			Long: `Print the config after merging all config files given by the --config flag.
+
+Secrets are redacted. The user is not asked for secrets, such secrets are shown as references.`,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				if err := parseForCommand(out, *paths); err != nil {
+					return err
+				}
+				b, err := MarshalRedacted(out)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(os.Stdout, string(b))
+				return err
+			},
+		},
+	)
+	return cmd
+}
+
+// parseForCommand parses config files without asking the user for secrets,
+// references to interactive secrets are left unresolved.
+func parseForCommand(out interface{}, paths []string) error {
+	err := parseFiles(out, paths, secretResolver{noPrompt: true})
+	var vErr ValidationErrors
+	if errors.As(err, &vErr) {
+		var s []string
+		for _, e := range vErr {
+			s = append(s, "  "+e.Error())
+		}
+		return fmt.Errorf("the config is invalid:\n%s", strings.Join(s, "\n"))
+	}
+	return err
+}
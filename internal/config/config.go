@@ -1,34 +1,112 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
-	"path/filepath"
+	"reflect"
 )
 
+// ParseFile parses a single JSON config file. It is the same as calling
+// ParseFiles with only one path.
 func ParseFile(out interface{}, path string) error {
-	p, err := filepath.Abs(path)
+	return ParseFiles(out, []string{path})
+}
+
+// ParseFiles parses JSON config files. The first file is a base config and
+// the following files are overlays, merged in order on top of it. Objects
+// are merged recursively, other values, including arrays, are replaced.
+//
+// Before merging, every file is processed as follows:
+//
+// 1. The "${VAR}" and "${VAR:-default}" expressions in string values are
+// replaced with environment variables. The "$${" sequence is replaced with
+// a literal "${".
+//
+// 2. Objects with the "include" key are replaced with the content of the
+// included file or files, paths are relative to the including file. Other
+// keys of that object are merged on top of the included content.
+//
+// After merging, secret references in fields marked as secrets are
+// resolved, secret references in other fields are reported as errors. See the SecretProviders variable for details. Finally, the config is
+// validated: unknown fields and values of invalid types are reported, and
+// the Validate method is called on every structure which implements the
+// Validator interface. Validation problems are returned as ValidationErrors.
+func ParseFiles(out interface{}, paths []string) error {
+	return parseFiles(out, paths, secretResolver{})
+}
+
+func parseFiles(out interface{}, paths []string, r secretResolver) error {
+	if len(paths) == 0 {
+		return errors.New("no config files given")
+	}
+	var merged interface{}
+	for _, path := range paths {
+		v, err := loadFile(path, nil)
+		if err != nil {
+			return fmt.Errorf("failed to load JSON config file: %w", err)
+		}
+		merged = merge(merged, v)
+	}
+	return decode(out, merged, r)
+}
+
+// Parse parses the JSON config. It works the same as ParseFiles, except
+// that included files are relative to the working directory.
+func Parse(out interface{}, config []byte) error {
+	v, err := unmarshal(config)
 	if err != nil {
 		return err
 	}
-	f, err := os.Open(p)
+	wd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to load JSON config file: %w", err)
+		return err
 	}
-	defer f.Close()
-	b, err := ioutil.ReadAll(f)
+	v, err = process(v, wd, nil)
 	if err != nil {
-		return fmt.Errorf("failed to load JSON config file: %w", err)
+		return err
 	}
-	return Parse(out, b)
+	return decode(out, v, secretResolver{})
 }
 
-func Parse(out interface{}, config []byte) error {
-	err := json.Unmarshal(config, out)
+// decode resolves secrets and decodes the raw JSON value into out. The
+// value is validated before and after decoding, all problems found are
+// returned as ValidationErrors.
+func decode(out interface{}, v interface{}, r secretResolver) error {
+	v, err := r.resolveFields(v, reflect.TypeOf(out), "")
 	if err != nil {
 		return err
 	}
+	if errs := checkTypes(v, reflect.TypeOf(out), "", true); len(errs) > 0 {
+		return errs
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return err
+	}
+	if errs := checkValues(reflect.ValueOf(out), ""); len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
+
+// unmarshal decodes JSON into a raw value. Numbers are decoded as
+// json.Number to preserve their precision.
+func unmarshal(b []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level JSON value")
+	}
+	return v, nil
+}
@@ -3,13 +3,11 @@ package ethereum
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strings"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 	"github.com/toknowwhy/theunit-oracle/internal/rpcsplitter"
 	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
 
@@ -24,7 +22,11 @@ var ethClientFactory = func(endpoints []string) (geth.EthClient, error) {
 	case 0:
 		return nil, errors.New("missing address to a RPC client in the configuration file")
 	case 1:
-		return ethclient.Dial(endpoints[0])
+		rpcClient, err := rpc.Dial(endpoints[0])
+		if err != nil {
+			return nil, err
+		}
+		return geth.NewRPCEthClient(rpcClient), nil
 	default:
 		// TODO: pass logger
 		splitter, err := rpcsplitter.NewTransport(endpoints, splitterVirtualHost, nil, null.New())
@@ -40,16 +42,21 @@ var ethClientFactory = func(endpoints []string) (geth.EthClient, error) {
 		if err != nil {
 			return nil, err
 		}
-		return ethclient.NewClient(rpcClient), nil
+		return geth.NewRPCEthClient(rpcClient), nil
 	}
 
 }
 
 type Ethereum struct {
-	From     string      `json:"from"`
-	Keystore string      `json:"keystore"`
-	Password string      `json:"password"`
-	RPC      interface{} `json:"rpc"`
+	From     string `json:"from"`
+	Keystore string `json:"keystore"`
+	// Password is a keystore password. It should be given as a secret
+	// reference, e.g. "file:/path/to/password" or "env:ETH_PASSWORD", rather
+	// than in plain text.
+	Password string `json:"password" config:"secret"`
+	// RPC is a single RPC endpoint or a list of endpoints. Endpoint URLs
+	// often contain API keys, so they may be given as secret references.
+	RPC interface{} `json:"rpc" config:"secret"`
 }
 
 func (c *Ethereum) ConfigureSigner() (ethereum.Signer, error) {
@@ -86,27 +93,32 @@ func (c *Ethereum) configureAccount() (*geth.Account, error) {
 	if c.From == "" {
 		return nil, nil
 	}
-	fmt.Println(c.Password)
-	//passphrase, err := c.readAccountPassphrase(c.Password)
-	//if err != nil {
-	//	return nil, err
-	//}
-	passphrase := c.Password
-
-	account, err := geth.NewAccount(c.Keystore, passphrase, ethereum.HexToAddress(c.From))
+	account, err := geth.NewAccount(c.Keystore, c.Password, ethereum.HexToAddress(c.From))
 	if err != nil {
 		return nil, err
 	}
 	return account, nil
 }
 
-func (c *Ethereum) readAccountPassphrase(path string) (string, error) {
-	if path == "" {
-		return "", nil
+// Validate implements the config.Validator interface.
+func (c *Ethereum) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.From != "" && !ethereum.IsHexAddress(c.From) {
+		errs = append(errs, config.ValidationError{Path: "from", Msg: fmt.Sprintf("invalid Ethereum address %q", c.From)})
 	}
-	passphrase, err := ioutil.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to read Ethereum password file: %w", err)
+	switch v := c.RPC.(type) {
+	case nil, string:
+	case []interface{}:
+		for i, s := range v {
+			if _, ok := s.(string); !ok {
+				errs = append(errs, config.ValidationError{
+					Path: config.JoinPath("rpc", config.IndexPath(i)),
+					Msg:  "must be a string",
+				})
+			}
+		}
+	default:
+		errs = append(errs, config.ValidationError{Path: "rpc", Msg: "must be a string or an array of strings"})
 	}
-	return strings.TrimSuffix(string(passphrase), "\n"), nil
+	return errs
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	internalConfig "github.com/toknowwhy/theunit-oracle/internal/config"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/geth"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/geth/mocks"
 )
@@ -33,12 +34,13 @@ func TestEthereum_ConfigureSigner_WithoutPassword(t *testing.T) {
 }
 
 func TestEthereum_ConfigureSigner_WithPassword(t *testing.T) {
-	config := Ethereum{
-		From:     "2d800d93b065ce011af83f316cef9f0d005b0aa4",
-		Keystore: "./testdata/keystore",
-		Password: "./testdata/2.pass",
-		RPC:      "",
-	}
+	var config Ethereum
+	err := internalConfig.Parse(&config, []byte(`{
+		"from": "2d800d93b065ce011af83f316cef9f0d005b0aa4",
+		"keystore": "./testdata/keystore",
+		"password": "file:./testdata/2.pass"
+	}`))
+	require.NoError(t, err)
 
 	signer, err := config.ConfigureSigner()
 	require.NoError(t, err)
@@ -99,3 +101,13 @@ func TestEthereum_ConfigureEthereumClientWithMultipleEndpoints(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, client)
 }
+
+func TestEthereum_Validate(t *testing.T) {
+	assert.Empty(t, (&Ethereum{From: "0x07a35a1d4b751a818d93aa38e615c0df23064881", RPC: "1.2.3.4:1234"}).Validate())
+	assert.Empty(t, (&Ethereum{RPC: []interface{}{"1.2.3.4:1234"}}).Validate())
+
+	errs := (&Ethereum{From: "0x123", RPC: []interface{}{"1.2.3.4:1234", 1}}).Validate()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "from", errs[0].Path)
+	assert.Equal(t, "rpc[1]", errs[1].Path)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
@@ -21,3 +23,17 @@ func (f *Feeds) Addresses() ([]ethereum.Address, error) {
 	}
 	return addrs, nil
 }
+
+// Validate implements the config.Validator interface.
+func (f *Feeds) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	for i, addr := range *f {
+		if !ethereum.IsHexAddress(addr) {
+			errs = append(errs, config.ValidationError{
+				Path: config.IndexPath(i),
+				Msg:  fmt.Sprintf("invalid Ethereum address %q", addr),
+			})
+		}
+	}
+	return errs
+}
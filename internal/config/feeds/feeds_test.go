@@ -24,3 +24,11 @@ func TestFeeds_Addresses_Invalid(t *testing.T) {
 
 	require.ErrorIs(t, err, ErrInvalidEthereumAddress)
 }
+
+func TestFeeds_Validate(t *testing.T) {
+	feeds := Feeds{"0x07a35a1d4b751a818d93aa38e615c0df23064881", "abc"}
+	errs := feeds.Validate()
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "[1]", errs[0].Path)
+}
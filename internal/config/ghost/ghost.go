@@ -2,12 +2,21 @@ package ghost
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/ghost"
+	"github.com/toknowwhy/theunit-oracle/pkg/ghost/journal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 )
 
@@ -17,26 +26,210 @@ var ghostFactory = func(ctx context.Context, cfg ghost.Config) (*ghost.Ghost, er
 }
 
 type Ghost struct {
-	Interval int      `json:"interval"`
-	Pairs    []string `json:"pairs"`
+	Interval int   `json:"interval"`
+	Pairs    Pairs `json:"pairs"`
+	// SignWorkers is the maximum number of prices signed concurrently.
+	SignWorkers int `json:"signWorkers"`
+	// Journal configures the journal of signed prices.
+	Journal Journal `json:"journal"`
+}
+
+// Journal configures the journal in which every signed price is recorded.
+// The journal is disabled if the path is empty.
+type Journal struct {
+	// Path is a directory in which the journal is stored.
+	Path string `json:"path"`
+	// MaxSize is the maximum size of a single journal file in bytes, after
+	// which the file is rotated. Rotated files are never removed.
+	MaxSize int64 `json:"maxSize"`
+}
+
+// Pairs is a map of pairs with their broadcasting policies. In the config
+// file, it may be defined as an object or as a list of pair names, in which
+// case every pair is broadcast on every interval.
+type Pairs map[string]Pair
+
+type Pair struct {
+	// Interval is the number of seconds between price checks, if zero, the
+	// ghost interval is used.
+	Interval int `json:"interval"`
+	// Spread is the minimum price change, in percent, since the last
+	// broadcast price required to broadcast a new price.
+	Spread float64 `json:"spread"`
+	// Expiration is the maximum number of seconds between broadcasts.
+	Expiration int `json:"expiration"`
+	// Gates are quality requirements which the price must meet before it
+	// is signed.
+	Gates Gates `json:"gates"`
+}
+
+// Gates describes quality gates of a pair, zero values disable gates.
+type Gates struct {
+	// MinSources is the minimum number of origin prices used to calculate
+	// the price.
+	MinSources int `json:"minSources"`
+	// MaxSourceSpread is the maximum spread, in percent, between origin
+	// prices.
+	MaxSourceSpread float64 `json:"maxSourceSpread"`
+	// MaxSourceAge is the maximum age, in seconds, of the oldest origin
+	// price.
+	MaxSourceAge int `json:"maxSourceAge"`
+	// MaxJump is the maximum spread, in percent, between the previous price
+	// fetched for the pair and the new price.
+	MaxJump float64 `json:"maxJump"`
+}
+
+// SelectType implements the config.TypeSelector interface.
+func (p Pairs) SelectType(raw interface{}) reflect.Type {
+	if _, ok := raw.([]interface{}); ok {
+		return reflect.TypeOf([]string{})
+	}
+	return reflect.TypeOf(map[string]Pair{})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Pairs) UnmarshalJSON(b []byte) error {
+	var names []string
+	if err := json.Unmarshal(b, &names); err == nil {
+		*p = make(Pairs, len(names))
+		for _, name := range names {
+			(*p)[name] = Pair{}
+		}
+		return nil
+	}
+	return json.Unmarshal(b, (*map[string]Pair)(p))
 }
 
 type Dependencies struct {
-	Context   context.Context
-	Gofer     gofer.Gofer
-	Signer    ethereum.Signer
-	Transport transport.Transport
-	Logger    log.Logger
+	Context         context.Context
+	Gofer           gofer.Gofer
+	Signer          ethereum.Signer
+	StarkSigner     stark.Signer
+	StarkOracleName string
+	Transport       transport.Transport
+	Logger          log.Logger
 }
 
 func (c *Ghost) Configure(d Dependencies) (*ghost.Ghost, error) {
+	jou, err := c.ConfigureJournal()
+	if err != nil {
+		return nil, err
+	}
 	cfg := ghost.Config{
-		Gofer:     d.Gofer,
-		Signer:    d.Signer,
-		Transport: d.Transport,
-		Logger:    d.Logger,
-		Interval:  time.Second * time.Duration(c.Interval),
-		Pairs:     c.Pairs,
+		Gofer:           d.Gofer,
+		Signer:          d.Signer,
+		StarkSigner:     d.StarkSigner,
+		StarkOracleName: d.StarkOracleName,
+		Transport:       d.Transport,
+		Logger:          d.Logger,
+		Interval:        time.Second * time.Duration(c.Interval),
+		Pairs:           c.ConfigurePairs(),
+		SignWorkers:     c.SignWorkers,
+		Journal:         jou,
 	}
 	return ghostFactory(d.Context, cfg)
 }
+
+// ConfigureJournal returns a new journal.Journal instance, or nil if
+// the journal is disabled.
+func (c *Ghost) ConfigureJournal() (journal.Journal, error) {
+	if c.Journal.Path == "" {
+		return nil, nil
+	}
+	jou, err := journal.NewFileJournal(journal.FileJournalConfig{
+		Path:    c.Journal.Path,
+		MaxSize: c.Journal.MaxSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize journal: %w", err)
+	}
+	return jou, nil
+}
+
+// ConfigurePairs returns pairs with their broadcasting policies, sorted by
+// names.
+func (c *Ghost) ConfigurePairs() []*ghost.Pair {
+	var pairs []*ghost.Pair
+	for name, pair := range c.Pairs {
+		pairs = append(pairs, &ghost.Pair{
+			AssetPair:  name,
+			Interval:   time.Second * time.Duration(pair.Interval),
+			Spread:     pair.Spread,
+			Expiration: time.Second * time.Duration(pair.Expiration),
+			Gates: ghost.Gates{
+				MinSources:      pair.Gates.MinSources,
+				MaxSourceSpread: pair.Gates.MaxSourceSpread,
+				MaxSourceAge:    time.Second * time.Duration(pair.Gates.MaxSourceAge),
+				MaxJump:         pair.Gates.MaxJump,
+			},
+		})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].AssetPair < pairs[j].AssetPair
+	})
+	return pairs
+}
+
+var pairRegexp = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// Validate implements the config.Validator interface.
+func (c *Ghost) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.Interval <= 0 {
+		errs = append(errs, config.ValidationError{Path: "interval", Msg: "must be greater than zero"})
+	}
+	if c.SignWorkers < 0 {
+		errs = append(errs, config.ValidationError{Path: "signWorkers", Msg: "must not be negative"})
+	}
+	for name := range c.Pairs {
+		if !pairRegexp.MatchString(name) {
+			errs = append(errs, config.ValidationError{
+				Path: config.JoinPath("pairs", config.KeyPath(name)),
+				Msg:  fmt.Sprintf("invalid pair name %q, pairs must be written as uppercase symbols without separators, e.g. BTCUSD", name),
+			})
+		}
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Pair) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.Interval < 0 {
+		errs = append(errs, config.ValidationError{Path: "interval", Msg: "must not be negative"})
+	}
+	if c.Spread < 0 || c.Spread > 100 {
+		errs = append(errs, config.ValidationError{Path: "spread", Msg: "must be between 0 and 100"})
+	}
+	if c.Expiration < 0 {
+		errs = append(errs, config.ValidationError{Path: "expiration", Msg: "must not be negative"})
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Gates) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.MinSources < 0 {
+		errs = append(errs, config.ValidationError{Path: "minSources", Msg: "must not be negative"})
+	}
+	if c.MaxSourceSpread < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxSourceSpread", Msg: "must not be negative"})
+	}
+	if c.MaxSourceAge < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxSourceAge", Msg: "must not be negative"})
+	}
+	if c.MaxJump < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxJump", Msg: "must not be negative"})
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Journal) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.MaxSize < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxSize", Msg: "must not be negative"})
+	}
+	return errs
+}
@@ -2,6 +2,13 @@ package ghost
 
 import (
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+	"github.com/toknowwhy/theunit-oracle/pkg/ghost"
 )
 
 func TestGhost_Configure(t *testing.T) {
@@ -41,3 +48,57 @@ func TestGhost_Configure(t *testing.T) {
 	//require.NoError(t, err)
 	//assert.NotNil(t, g)
 }
+
+func TestGhost_Validate(t *testing.T) {
+	assert.Empty(t, (&Ghost{Interval: 10, Pairs: Pairs{"AAABBB": {}}}).Validate())
+
+	errs := (&Ghost{Interval: 0, Pairs: Pairs{"AAABBB": {}, "AAA/BBB": {}}, SignWorkers: -1}).Validate()
+	require.Len(t, errs, 3)
+	assert.Equal(t, "interval", errs[0].Path)
+	assert.Equal(t, "signWorkers", errs[1].Path)
+	assert.Equal(t, `pairs["AAA/BBB"]`, errs[2].Path)
+
+	errs = (&Pair{Interval: -1, Spread: 101, Expiration: -1}).Validate()
+	require.Len(t, errs, 3)
+	assert.Equal(t, "interval", errs[0].Path)
+	assert.Equal(t, "spread", errs[1].Path)
+	assert.Equal(t, "expiration", errs[2].Path)
+
+	errs = (&Gates{MinSources: -1, MaxSourceSpread: -1, MaxSourceAge: -1, MaxJump: -1}).Validate()
+	require.Len(t, errs, 4)
+
+	errs = (&Journal{MaxSize: -1}).Validate()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "maxSize", errs[0].Path)
+}
+
+func TestGhost_Pairs(t *testing.T) {
+	var list Ghost
+	require.NoError(t, config.Parse(&list, []byte(`{"interval": 60, "pairs": ["BTCUSD", "ETHUSD"]}`)))
+	assert.Equal(t, Pairs{"BTCUSD": {}, "ETHUSD": {}}, list.Pairs)
+
+	var obj Ghost
+	require.NoError(t, config.Parse(&obj, []byte(`{
+		"interval": 60,
+		"pairs": {
+			"BTCUSD": {"interval": 10, "spread": 0.5, "expiration": 3600, "gates": {"minSources": 2, "maxSourceAge": 60}},
+			"ETHUSD": {}
+		}
+	}`)))
+	assert.Equal(t, []*ghost.Pair{
+		{
+			AssetPair:  "BTCUSD",
+			Interval:   10 * time.Second,
+			Spread:     0.5,
+			Expiration: time.Hour,
+			Gates:      ghost.Gates{MinSources: 2, MaxSourceAge: time.Minute},
+		},
+		{AssetPair: "ETHUSD"},
+	}, obj.ConfigurePairs())
+
+	var invalid Ghost
+	err := config.Parse(&invalid, []byte(`{"interval": 60, "pairs": {"BTCUSD": {"sprad": 1}}}`))
+	var vErr config.ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, "pairs.BTCUSD.sprad", vErr[0].Path)
+}
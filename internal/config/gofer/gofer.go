@@ -3,18 +3,22 @@ package gofer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 	"github.com/toknowwhy/theunit-oracle/internal/query"
 	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/feeder"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/origins"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/rpc"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
@@ -22,6 +26,7 @@ import (
 
 const defaultTTL = 60 * time.Second
 const maxTTL = 60 * time.Second
+const defaultHistoryInterval = 10 * time.Second
 
 type ErrCyclicReference struct {
 	Pair gofer.Pair
@@ -48,10 +53,25 @@ func (e ErrCyclicReference) Error() string {
 
 type Gofer struct {
 	RPC                     RPC                   `json:"rpc"`
-	EthRPC                  string                `json:"ethRpc"`
+	EthRPC                  string                `json:"ethRpc" config:"secret"`
 	Origins                 map[string]Origin     `json:"origins"`
 	PriceModels             map[string]PriceModel `json:"priceModels"`
 	CirculatingSupplyModels map[string]PriceModel `json:"circulatingSupplyModels"`
+	History                 History               `json:"history"`
+}
+
+// History configures the price history store. The history is disabled if
+// the path is empty.
+type History struct {
+	// Path is a directory in which the history is stored.
+	Path string `json:"path"`
+	// MaxAge is the number of seconds after which records are removed.
+	MaxAge int `json:"maxAge"`
+	// MaxSize is the maximum size of the history in bytes.
+	MaxSize int64 `json:"maxSize"`
+	// Interval is the number of seconds between recording prices for all
+	// pairs by the agent.
+	Interval int `json:"interval"`
 }
 
 type RPC struct {
@@ -59,9 +79,11 @@ type RPC struct {
 }
 
 type Origin struct {
-	Type   string          `json:"type"`
-	Name   string          `json:"name"`
-	Params json.RawMessage `json:"params"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Params may contain API keys, so all string values in it may be given
+	// as secret references.
+	Params json.RawMessage `json:"params" config:"secret"`
 }
 
 type PriceModel struct {
@@ -115,10 +137,15 @@ func (c *Gofer) ConfigureRPCAgent(ctx context.Context, cli pkgEthereum.Client, l
 		return nil, err
 	}
 	fed := feeder.NewFeeder(ctx, originSet, logger)
-	gof, err := graph.NewAsyncGofer(ctx, gra, fed)
+	var gof gofer.Gofer
+	gof, err = graph.NewAsyncGofer(ctx, gra, fed)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize RPC agent: %w", err)
 	}
+	gof, err = c.configureHistory(ctx, gof, logger, defaultHistoryInterval)
+	if err != nil {
+		return nil, err
+	}
 	srv, err := rpc.NewAgent(ctx, rpc.AgentConfig{
 		Gofer:   gof,
 		Network: "tcp",
@@ -143,8 +170,74 @@ func (c *Gofer) configureGofer(ctx context.Context, cli pkgEthereum.Client, logg
 		return nil, err
 	}
 	fed := feeder.NewFeeder(ctx, originSet, logger)
-	gof := graph.NewGofer(gra, fed)
-	return gof, nil
+	return c.configureHistory(ctx, graph.NewGofer(gra, fed), logger, 0)
+}
+
+// ConfigureHistoryStore returns a new history.Store instance, or nil if the
+// history is disabled.
+func (c *Gofer) ConfigureHistoryStore() (history.Store, error) {
+	if c.History.Path == "" {
+		return nil, nil
+	}
+	store, err := history.NewFileStore(history.FileStoreConfig{
+		Path:    c.History.Path,
+		MaxAge:  time.Second * time.Duration(c.History.MaxAge),
+		MaxSize: c.History.MaxSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize price history: %w", err)
+	}
+	return store, nil
+}
+
+// configureHistory wraps the given Gofer instance to record returned prices
+// in the history store. If the history is disabled, the given Gofer instance
+// is returned unchanged. The defaultInterval is used if the interval is not
+// set in the config.
+func (c *Gofer) configureHistory(
+	ctx context.Context,
+	gof gofer.Gofer,
+	logger log.Logger,
+	defaultInterval time.Duration) (gofer.Gofer, error) {
+
+	store, err := c.ConfigureHistoryStore()
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return gof, nil
+	}
+	interval := defaultInterval
+	if c.History.Interval > 0 {
+		interval = time.Second * time.Duration(c.History.Interval)
+	}
+	return history.NewGofer(ctx, history.Config{
+		Gofer:    gof,
+		Store:    store,
+		Interval: interval,
+		Logger:   logger,
+	})
+}
+
+// ConfigurePriceModels returns graphs for all price models. Origin nodes in
+// returned graphs are not fed with prices.
+func (c *Gofer) ConfigurePriceModels() (map[gofer.Pair]nodes.Aggregator, error) {
+	gra, err := c.buildGraphs()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load price models: %w", err)
+	}
+	return gra, nil
+}
+
+// ReloadPriceModels returns graphs for all price models from the next
+// config. These graphs may be used to replace price models of a running
+// Gofer which implements the graph.Updater interface. Origins cannot be
+// reloaded, so an error is returned if the origins configuration differs.
+func (c *Gofer) ReloadPriceModels(next *Gofer) (map[gofer.Pair]nodes.Aggregator, error) {
+	if !reflect.DeepEqual(c.Origins, next.Origins) {
+		return nil, errors.New("origins configuration cannot be reloaded, a restart is required")
+	}
+	return next.ConfigurePriceModels()
 }
 
 // configureRPCClient returns a new rpc.RPC instance.
@@ -334,3 +427,97 @@ func sortGraphs(graphs map[gofer.Pair]nodes.Aggregator) []gofer.Pair {
 	})
 	return ps
 }
+
+// Validate implements the config.Validator interface.
+func (c *Gofer) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	for _, name := range sortedModelNames(c.PriceModels) {
+		path := config.JoinPath("priceModels", config.KeyPath(name))
+		if _, err := gofer.NewPair(name); err != nil {
+			errs = append(errs, config.ValidationError{
+				Path: path,
+				Msg:  fmt.Sprintf("invalid pair name %q, pairs must be written as BASE/QUOTE", name),
+			})
+		}
+		errs = append(errs, c.validatePriceModel(c.PriceModels[name]).Prefix(path)...)
+	}
+	return errs
+}
+
+func (c *Gofer) validatePriceModel(model PriceModel) config.ValidationErrors {
+	var errs config.ValidationErrors
+	if model.Method != "median" {
+		errs = append(errs, config.ValidationError{Path: "method", Msg: fmt.Sprintf("unknown method %q", model.Method)})
+	}
+	if model.TTL < 0 {
+		errs = append(errs, config.ValidationError{Path: "ttl", Msg: "must not be negative"})
+	}
+	for i, sources := range model.Sources {
+		for j, source := range sources {
+			path := config.JoinPath("sources", config.IndexPath(i)+config.IndexPath(j))
+			pair, err := gofer.NewPair(source.Pair)
+			if err != nil {
+				errs = append(errs, config.ValidationError{
+					Path: config.JoinPath(path, "pair"),
+					Msg:  fmt.Sprintf("invalid pair name %q, pairs must be written as BASE/QUOTE", source.Pair),
+				})
+			} else if source.Origin == "." && !c.hasPriceModel(pair) {
+				errs = append(errs, config.ValidationError{
+					Path: config.JoinPath(path, "pair"),
+					Msg:  fmt.Sprintf("unable to find price model for the %s pair", pair),
+				})
+			}
+			if source.Origin == "" {
+				errs = append(errs, config.ValidationError{Path: config.JoinPath(path, "origin"), Msg: "must not be empty"})
+			}
+			if source.TTL < 0 {
+				errs = append(errs, config.ValidationError{Path: config.JoinPath(path, "ttl"), Msg: "must not be negative"})
+			}
+		}
+	}
+	return errs
+}
+
+func (c *Gofer) hasPriceModel(pair gofer.Pair) bool {
+	for name := range c.PriceModels {
+		if p, err := gofer.NewPair(name); err == nil && p == pair {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate implements the config.Validator interface.
+func (c *RPC) Validate() config.ValidationErrors {
+	if c.Address == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return config.ValidationErrors{{Path: "address", Msg: fmt.Sprintf("invalid address: %s", err)}}
+	}
+	return nil
+}
+
+// Validate implements the config.Validator interface.
+func (c *History) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.MaxAge < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxAge", Msg: "must not be negative"})
+	}
+	if c.MaxSize < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxSize", Msg: "must not be negative"})
+	}
+	if c.Interval < 0 {
+		errs = append(errs, config.ValidationError{Path: "interval", Msg: "must not be negative"})
+	}
+	return errs
+}
+
+func sortedModelNames(models map[string]PriceModel) []string {
+	var names []string
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -267,3 +267,51 @@ func TestConfig_buildGraphs_MedianTTL(t *testing.T) {
 	assert.Equal(t, 180*time.Second, g[p].Children()[0].(*nodes.OriginNode).MaxTTL())
 	assert.Equal(t, 120*time.Second, g[p].Children()[0].(*nodes.OriginNode).MinTTL())
 }
+
+func TestConfig_Validate(t *testing.T) {
+	config := Gofer{
+		RPC: RPC{Address: "localhost"},
+		PriceModels: map[string]PriceModel{
+			"A/B": {
+				Method: "median",
+				Sources: [][]Source{
+					{{Origin: "a", Pair: "A/B", TTL: 10}},
+					{{Origin: ".", Pair: "A/C"}, {Origin: ".", Pair: "X/Y"}},
+				},
+			},
+			"A/C": {
+				Method: "mean",
+				TTL:    -1,
+				Sources: [][]Source{
+					{{Origin: "", Pair: "AC"}},
+				},
+			},
+			"A_B": {
+				Method: "median",
+			},
+		},
+		History: History{MaxAge: -1},
+	}
+
+	var errs []string
+	for _, err := range config.Validate() {
+		errs = append(errs, err.Error())
+	}
+	for _, err := range config.RPC.Validate() {
+		errs = append(errs, err.Error())
+	}
+	for _, err := range config.History.Validate() {
+		errs = append(errs, err.Error())
+	}
+
+	assert.Equal(t, []string{
+		`priceModels["A/B"].sources[1][1].pair: unable to find price model for the X/Y pair`,
+		`priceModels["A/C"].method: unknown method "mean"`,
+		`priceModels["A/C"].ttl: must not be negative`,
+		`priceModels["A/C"].sources[0][0].pair: invalid pair name "AC", pairs must be written as BASE/QUOTE`,
+		`priceModels["A/C"].sources[0][0].origin: must not be empty`,
+		`priceModels.A_B: invalid pair name "A_B", pairs must be written as BASE/QUOTE`,
+		`address: invalid address: address localhost: missing port in address`,
+		`maxAge: must not be negative`,
+	}, errs)
+}
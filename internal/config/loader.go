@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// includeKey is the key of the include directive.
+const includeKey = "include"
+
+// maxIncludeDepth limits the nesting of included files.
+const maxIncludeDepth = 16
+
+// interpolationRegexp matches the "$${", "${VAR}" and "${VAR:-default}"
+// expressions.
+var interpolationRegexp = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?}`)
+
+// IncludeError is returned when an included file cannot be loaded.
+type IncludeError struct {
+	Path string
+	Err  error
+}
+
+func (e IncludeError) Error() string {
+	return fmt.Sprintf("unable to include the %s file: %s", e.Path, e.Err)
+}
+
+func (e IncludeError) Unwrap() error {
+	return e.Err
+}
+
+// InterpolationError is returned when a string value refers to an undefined
+// environment variable.
+type InterpolationError struct {
+	Name string
+}
+
+func (e InterpolationError) Error() string {
+	return fmt.Sprintf("environment variable %s used in the config is not set", e.Name)
+}
+
+// loadFile loads and processes a single config file. The stack contains
+// paths of files which include the loaded file, it is used to detect
+// include cycles.
+func loadFile(path string, stack []string) (interface{}, error) {
+	p, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stack {
+		if s == p {
+			return nil, errors.New("include cycle detected")
+		}
+	}
+	if len(stack) >= maxIncludeDepth {
+		return nil, errors.New("too many nested includes")
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	v, err := unmarshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return process(v, filepath.Dir(p), append(stack, p))
+}
+
+// process interpolates environment variables and handles the include
+// directives in the raw JSON value. The dir is used to resolve relative
+// paths of included files.
+func process(v interface{}, dir string, stack []string) (interface{}, error) {
+	var err error
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range tv {
+			if tv[k], err = process(e, dir, stack); err != nil {
+				return nil, err
+			}
+		}
+		if inc, ok := tv[includeKey]; ok {
+			return include(tv, inc, dir, stack)
+		}
+	case []interface{}:
+		for i, e := range tv {
+			if tv[i], err = process(e, dir, stack); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		return interpolate(tv)
+	}
+	return v, nil
+}
+
+// include replaces the object with the content of included files. The
+// inc value may be a path or a list of paths. Files are merged in order and
+// then the remaining keys of the object are merged on top of them.
+func include(obj map[string]interface{}, inc interface{}, dir string, stack []string) (interface{}, error) {
+	var paths []string
+	switch ti := inc.(type) {
+	case string:
+		paths = []string{ti}
+	case []interface{}:
+		for _, p := range ti {
+			s, ok := p.(string)
+			if !ok {
+				return nil, errors.New("the include directive must be a path or a list of paths")
+			}
+			paths = append(paths, s)
+		}
+	default:
+		return nil, errors.New("the include directive must be a path or a list of paths")
+	}
+	var merged interface{}
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		v, err := loadFile(path, stack)
+		if err != nil {
+			return nil, IncludeError{Path: path, Err: err}
+		}
+		merged = merge(merged, v)
+	}
+	delete(obj, includeKey)
+	if len(obj) == 0 {
+		return merged, nil
+	}
+	return merge(merged, obj), nil
+}
+
+// merge merges the overlay value on top of the base value. Objects are
+// merged recursively, other values are replaced.
+func merge(base, overlay interface{}) interface{} {
+	bm, bok := base.(map[string]interface{})
+	om, ook := overlay.(map[string]interface{})
+	if !bok || !ook {
+		return overlay
+	}
+	for k, v := range om {
+		bm[k] = merge(bm[k], v)
+	}
+	return bm
+}
+
+// interpolate replaces environment variable expressions in the string.
+func interpolate(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var err error
+	r := interpolationRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "$${" {
+			return "${"
+		}
+		sm := interpolationRegexp.FindStringSubmatch(m)
+		if v, ok := os.LookupEnv(sm[1]); ok {
+			return v
+		}
+		if sm[2] != "" {
+			return sm[3]
+		}
+		if err == nil {
+			err = InterpolationError{Name: sm[1]}
+		}
+		return m
+	})
+	return r, err
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderTestConfig struct {
+	Name    string                 `json:"name"`
+	RPC     []string               `json:"rpc"`
+	Nested  map[string]interface{} `json:"nested"`
+	Models  map[string]string      `json:"models"`
+	Address string                 `json:"address"`
+	Secret  string                 `json:"secret" config:"secret"`
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestParseFiles_Overlay(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json":         `{"name":"base","rpc":["a","b"],"nested":{"a":1,"b":{"c":2,"d":3}}}`,
+		"config.mainnet.json": `{"rpc":["c"],"nested":{"b":{"d":4}}}`,
+	})
+
+	var cfg loaderTestConfig
+	err := ParseFiles(&cfg, []string{
+		filepath.Join(dir, "config.json"),
+		filepath.Join(dir, "config.mainnet.json"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "base", cfg.Name)
+	assert.Equal(t, []string{"c"}, cfg.RPC)
+	assert.Equal(t, map[string]interface{}{
+		"a": float64(1),
+		"b": map[string]interface{}{"c": float64(2), "d": float64(4)},
+	}, cfg.Nested)
+}
+
+func TestParseFiles_Include(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json":        `{"name":"base","models":{"include":["models/a.json","models/b.json"],"C/D":"override"}}`,
+		"models/a.json":      `{"include":"common.json","A/B":"a"}`,
+		"models/b.json":      `{"C/D":"b"}`,
+		"models/common.json": `{"X/Y":"common"}`,
+	})
+
+	var cfg loaderTestConfig
+	err := ParseFile(&cfg, filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"X/Y": "common",
+		"A/B": "a",
+		"C/D": "override",
+	}, cfg.Models)
+}
+
+func TestParseFiles_IncludeCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"models":{"include":"b.json"}}`,
+		"b.json": `{"include":"a.json"}`,
+	})
+
+	var cfg loaderTestConfig
+	err := ParseFile(&cfg, filepath.Join(dir, "a.json"))
+	require.Error(t, err)
+
+	var iErr IncludeError
+	assert.ErrorAs(t, err, &iErr)
+}
+
+func TestParseFiles_InvalidInclude(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"models":{"include":1}}`,
+	})
+
+	var cfg loaderTestConfig
+	assert.Error(t, ParseFile(&cfg, filepath.Join(dir, "config.json")))
+}
+
+func TestParseFiles_NoFiles(t *testing.T) {
+	var cfg loaderTestConfig
+	assert.Error(t, ParseFiles(&cfg, nil))
+}
+
+func TestParse_Interpolation(t *testing.T) {
+	require.NoError(t, os.Setenv("CONFIG_TEST_HOST", "localhost"))
+	defer os.Unsetenv("CONFIG_TEST_HOST")
+
+	var cfg loaderTestConfig
+	err := Parse(&cfg, []byte(`{
+		"address": "${CONFIG_TEST_HOST}:${CONFIG_TEST_PORT:-8080}",
+		"name": "$${CONFIG_TEST_HOST}",
+		"rpc": ["http://${CONFIG_TEST_HOST}"]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost:8080", cfg.Address)
+	assert.Equal(t, "${CONFIG_TEST_HOST}", cfg.Name)
+	assert.Equal(t, []string{"http://localhost"}, cfg.RPC)
+}
+
+func TestParse_InterpolationMissingVariable(t *testing.T) {
+	var cfg loaderTestConfig
+	err := Parse(&cfg, []byte(`{"address":"${CONFIG_TEST_MISSING}"}`))
+	require.Error(t, err)
+
+	var iErr InterpolationError
+	assert.ErrorAs(t, err, &iErr)
+	assert.Equal(t, "CONFIG_TEST_MISSING", iErr.Name)
+}
+
+func TestParse_InterpolatedSecret(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"password": "secret\n"})
+	require.NoError(t, os.Setenv("CONFIG_TEST_DIR", dir))
+	defer os.Unsetenv("CONFIG_TEST_DIR")
+
+	var cfg loaderTestConfig
+	err := Parse(&cfg, []byte(`{"secret":"file:${CONFIG_TEST_DIR}/password"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", cfg.Secret)
+}
@@ -0,0 +1,341 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// RedactedSecret is used in place of secret values in logs and in
+// the printed configuration.
+const RedactedSecret = "***"
+
+// SecretProvider returns a secret for the given reference. The reference is
+// a part of the config value after the provider prefix, e.g. for the
+// "env:PASSWORD" value, the "env" provider is called with "PASSWORD".
+type SecretProvider func(ref string) (string, error)
+
+// SecretProviders is a list of supported secret providers. Values of config
+// fields marked as secrets which begin with a provider name followed by
+// a colon are replaced with secrets returned by that provider. It is safe
+// to add custom providers to this map.
+//
+// Fields are marked as secrets with the `config:"secret"` struct tag. If
+// a marked field is an object or an array, all string values in it may be
+// secret references. Secret references in other fields are reported as
+// validation errors, so they are never used literally.
+var SecretProviders = map[string]SecretProvider{
+	"file":   fileSecret,
+	"env":    envSecret,
+	"prompt": promptSecret,
+}
+
+// InteractiveSecretProviders is a list of providers which ask the user for
+// a secret. Their secrets are resolved only once, so the user is not asked
+// again when the config is reloaded. Secrets from other providers are read
+// every time the config is parsed, so rotated secrets are picked up.
+var InteractiveSecretProviders = map[string]bool{
+	"prompt": true,
+}
+
+// secretPromptInput and secretPromptOutput are used by the prompt provider.
+var secretPromptInput io.Reader = os.Stdin
+var secretPromptOutput io.Writer = os.Stderr
+
+var secretsMu sync.Mutex
+
+// secrets contains the latest resolved secrets by their references. They
+// are used to redact secrets and as a cache for interactive providers.
+var secrets = map[string]string{}
+
+// secretTag is the struct tag used to mark secret fields.
+const secretTag = "secret"
+
+// SecretError is returned when a secret reference cannot be resolved.
+type SecretError struct {
+	Ref string
+	Err error
+}
+
+func (e SecretError) Error() string {
+	return fmt.Sprintf("unable to resolve the %q secret: %s", e.Ref, e.Err)
+}
+
+func (e SecretError) Unwrap() error {
+	return e.Err
+}
+
+// Redact replaces all known secrets in the given string with the
+// RedactedSecret value.
+func Redact(s string) string {
+	secretsMu.Lock()
+	values := make([]string, 0, len(secrets))
+	for _, v := range secrets {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	secretsMu.Unlock()
+	// Longer secrets first, in case one secret contains another one:
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, RedactedSecret)
+	}
+	return s
+}
+
+// MarshalRedacted returns the indented JSON representation of the config with
+// all known secrets redacted. It should be used whenever the config is shown
+// to the user.
+func MarshalRedacted(config interface{}) ([]byte, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redactSecrets(raw), "", "  ")
+}
+
+func redactSecrets(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range tv {
+			tv[k] = redactSecrets(e)
+		}
+	case []interface{}:
+		for i, e := range tv {
+			tv[i] = redactSecrets(e)
+		}
+	case string:
+		return Redact(tv)
+	}
+	return v
+}
+
+// IsSecretReference returns true if the given value is a secret reference.
+// Config values are secret references only if interactive providers were
+// disabled while parsing the config, e.g. by the "config validate" command,
+// so validators should not check their content.
+func IsSecretReference(s string) bool {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return false
+	}
+	_, ok := SecretProviders[s[:i]]
+	return ok
+}
+
+// secretResolver replaces secret references in fields marked as secrets.
+type secretResolver struct {
+	// noPrompt disables interactive providers. References to them are left
+	// unresolved.
+	noPrompt bool
+}
+
+// resolveFields walks through the decoded JSON value using the type of
+// the config structure and resolves values of fields marked as secrets.
+// If a string value of another field is a secret reference, a validation
+// error is returned.
+func (r secretResolver) resolveFields(v interface{}, typ reflect.Type, path string) (interface{}, error) {
+	var err error
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return r.resolveFields(v, typ.Elem(), path)
+	case reflect.String:
+		if s, ok := v.(string); ok && IsSecretReference(s) {
+			return nil, ValidationErrors{{Path: path, Msg: "secret references are allowed only in secret fields"}}
+		}
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v, nil
+		}
+		fields := jsonFields(typ)
+		for k, e := range obj {
+			field, ok := findField(fields, k)
+			if !ok {
+				continue
+			}
+			sf := typ.Field(field.index)
+			if hasTag(sf.Tag.Get("config"), secretTag) {
+				obj[k], err = r.resolveAll(e)
+			} else {
+				obj[k], err = r.resolveFields(e, sf.Type, JoinPath(path, KeyPath(k)))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v, nil
+		}
+		for k, e := range obj {
+			if obj[k], err = r.resolveFields(e, typ.Elem(), JoinPath(path, KeyPath(k))); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return v, nil
+		}
+		for i, e := range arr {
+			if arr[i], err = r.resolveFields(e, typ.Elem(), JoinPath(path, IndexPath(i))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// resolveAll replaces all secret references in the decoded JSON value.
+func (r secretResolver) resolveAll(v interface{}) (interface{}, error) {
+	var err error
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range tv {
+			if tv[k], err = r.resolveAll(e); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, e := range tv {
+			if tv[i], err = r.resolveAll(e); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		return r.resolve(tv)
+	}
+	return v, nil
+}
+
+// resolve returns a secret if the given value is a secret reference,
+// otherwise it returns the value unchanged.
+func (r secretResolver) resolve(s string) (string, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return s, nil
+	}
+	name := s[:i]
+	provider, ok := SecretProviders[name]
+	if !ok {
+		return s, nil
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if InteractiveSecretProviders[name] {
+		if secret, ok := secrets[s]; ok {
+			return secret, nil
+		}
+		if r.noPrompt {
+			return s, nil
+		}
+	}
+	secret, err := provider(s[i+1:])
+	if err != nil {
+		return "", SecretError{Ref: s, Err: err}
+	}
+	secrets[s] = secret
+	return secret, nil
+}
+
+func hasTag(tag, name string) bool {
+	for _, t := range strings.Split(tag, ",") {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fileSecret reads a secret from a file. A trailing new line is removed.
+func fileSecret(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("file path is empty")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return trimNewLine(string(b)), nil
+}
+
+// envSecret reads a secret from an environment variable.
+func envSecret(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("environment variable name is empty")
+	}
+	secret, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return secret, nil
+}
+
+// promptSecret asks the user for a secret. The optional reference is used
+// as a prompt label. If the input is a terminal, the secret is not echoed,
+// otherwise a single line is read from the input.
+func promptSecret(label string) (string, error) {
+	if label == "" {
+		label = "Secret"
+	}
+	if _, err := fmt.Fprintf(secretPromptOutput, "%s: ", label); err != nil {
+		return "", err
+	}
+	if f, ok := secretPromptInput.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		b, err := term.ReadPassword(int(f.Fd()))
+		_, _ = fmt.Fprintln(secretPromptOutput)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return readLine(secretPromptInput)
+}
+
+// readLine reads a single line from the reader. The reader is read byte by
+// byte, so the rest of the input is left for subsequent prompts.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if errors.Is(err, io.EOF) && len(line) > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return trimNewLine(string(line)), nil
+}
+
+func trimNewLine(s string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secretTestConfig struct {
+	Password string            `json:"password" config:"secret"`
+	APIKey   string            `json:"apiKey" config:"secret"`
+	Plain    string            `json:"plain"`
+	Number   float64           `json:"number"`
+	Params   map[string]string `json:"params" config:"secret"`
+	Nested   []secretTestItem  `json:"nested"`
+}
+
+type secretTestItem struct {
+	Token string `json:"token" config:"secret"`
+}
+
+func TestParse_FileSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, ioutil.WriteFile(path, []byte("file-secret\n"), 0600))
+
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"password":"file:`+path+`","plain":"http://localhost:8080","number":1.5}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-secret", cfg.Password)
+	assert.Equal(t, "http://localhost:8080", cfg.Plain)
+	assert.Equal(t, 1.5, cfg.Number)
+}
+
+func TestParse_EnvSecret(t *testing.T) {
+	require.NoError(t, os.Setenv("CONFIG_TEST_API_KEY", "env-secret"))
+	defer os.Unsetenv("CONFIG_TEST_API_KEY")
+
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"params":{"apiKey":"env:CONFIG_TEST_API_KEY"}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "env-secret", cfg.Params["apiKey"])
+}
+
+func TestParse_MissingEnvSecret(t *testing.T) {
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"password":"env:CONFIG_TEST_MISSING"}`))
+	require.Error(t, err)
+
+	var sErr SecretError
+	assert.ErrorAs(t, err, &sErr)
+	assert.Equal(t, "env:CONFIG_TEST_MISSING", sErr.Ref)
+}
+
+func TestParse_PromptSecret(t *testing.T) {
+	in, out := secretPromptInput, secretPromptOutput
+	defer func() { secretPromptInput, secretPromptOutput = in, out }()
+	b := &bytes.Buffer{}
+	secretPromptInput = strings.NewReader("first\nsecond\n")
+	secretPromptOutput = b
+
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"password":"prompt:Keystore password","apiKey":"prompt:API key"}`))
+	require.NoError(t, err)
+
+	// Map keys are not ordered, so prompts may be shown in any order:
+	assert.ElementsMatch(t, []string{"first", "second"}, []string{cfg.Password, cfg.APIKey})
+	assert.Contains(t, b.String(), "Keystore password: ")
+	assert.Contains(t, b.String(), "API key: ")
+}
+
+func TestParse_InvalidJSON(t *testing.T) {
+	var cfg secretTestConfig
+	assert.Error(t, Parse(&cfg, []byte(`{"plain":"a"`)))
+	assert.Error(t, Parse(&cfg, []byte(`{"plain":"a"} {}`)))
+}
+
+func TestRedact(t *testing.T) {
+	require.NoError(t, os.Setenv("CONFIG_TEST_REDACT", "redact-me"))
+	defer os.Unsetenv("CONFIG_TEST_REDACT")
+
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"password":"env:CONFIG_TEST_REDACT","plain":"plain"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "url?key=***", Redact("url?key=redact-me"))
+
+	b, err := MarshalRedacted(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"password": "***"`)
+	assert.Contains(t, string(b), `"plain": "plain"`)
+	assert.NotContains(t, string(b), "redact-me")
+}
+
+func TestParse_UnmarkedSecret(t *testing.T) {
+	require.NoError(t, os.Setenv("CONFIG_TEST_UNMARKED", "env-secret"))
+	defer os.Unsetenv("CONFIG_TEST_UNMARKED")
+
+	// Fields marked as secrets are resolved:
+	var cfg secretTestConfig
+	err := Parse(&cfg, []byte(`{"plain":"value","nested":[{"token":"env:CONFIG_TEST_UNMARKED"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "env-secret", cfg.Nested[0].Token)
+
+	// References in other fields must not be used literally:
+	err = Parse(&cfg, []byte(`{"plain":"env:CONFIG_TEST_UNMARKED"}`))
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, "plain", errs[0].Path)
+}
+
+func TestParse_RotatedSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	config := []byte(`{"password":"file:` + path + `"}`)
+
+	// Secrets from files are read every time the config is parsed:
+	var cfg secretTestConfig
+	require.NoError(t, ioutil.WriteFile(path, []byte("old"), 0600))
+	require.NoError(t, Parse(&cfg, config))
+	assert.Equal(t, "old", cfg.Password)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("new"), 0600))
+	require.NoError(t, Parse(&cfg, config))
+	assert.Equal(t, "new", cfg.Password)
+}
+
+func TestParse_PromptSecretOnce(t *testing.T) {
+	in, out := secretPromptInput, secretPromptOutput
+	defer func() { secretPromptInput, secretPromptOutput = in, out }()
+	secretPromptInput = strings.NewReader("first\nsecond\n")
+	secretPromptOutput = &bytes.Buffer{}
+
+	// The user is asked for the secret only once:
+	config := []byte(`{"password":"prompt:Prompted once"}`)
+	var cfg secretTestConfig
+	require.NoError(t, Parse(&cfg, config))
+	require.NoError(t, Parse(&cfg, config))
+	assert.Equal(t, "first", cfg.Password)
+}
+
+func TestParseForCommand_NoPrompt(t *testing.T) {
+	in, out := secretPromptInput, secretPromptOutput
+	defer func() { secretPromptInput, secretPromptOutput = in, out }()
+	b := &bytes.Buffer{}
+	secretPromptInput = strings.NewReader("secret\n")
+	secretPromptOutput = b
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"password":"prompt:Not prompted"}`), 0600))
+
+	// The validate and show commands must not ask for secrets:
+	var cfg secretTestConfig
+	require.NoError(t, parseForCommand(&cfg, []string{path}))
+	assert.Equal(t, "prompt:Not prompted", cfg.Password)
+	assert.Empty(t, b.String())
+	assert.True(t, IsSecretReference(cfg.Password))
+	assert.False(t, IsSecretReference("http://localhost"))
+}
@@ -2,15 +2,25 @@ package spectre
 
 import (
 	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	datastoreMemory "github.com/toknowwhy/theunit-oracle/pkg/datastore/memory"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/txmanager"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	oracleGeth "github.com/toknowwhy/theunit-oracle/pkg/oracle/geth"
 	"github.com/toknowwhy/theunit-oracle/pkg/spectre"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 )
 
 // nolint
@@ -18,13 +28,74 @@ var spectreFactory = func(ctx context.Context, cfg spectre.Config) (*spectre.Spe
 	return spectre.NewSpectre(ctx, cfg)
 }
 
+var txManagerFactory = func(ctx context.Context, cfg txmanager.Config) (*txmanager.Manager, error) {
+	return txmanager.NewManager(ctx, cfg)
+}
+
 var datastoreFactory = func(ctx context.Context, cfg datastoreMemory.Config) (datastore.Datastore, error) {
 	return datastoreMemory.NewDatastore(ctx, cfg)
 }
 
 type Spectre struct {
-	Interval    int64                 `json:"interval"`
+	Interval int64 `json:"interval"`
+	// Workers is the number of pairs which are evaluated concurrently. If
+	// zero, the spectre.DefaultWorkers value is used.
+	Workers     int                   `json:"workers"`
 	Medianizers map[string]Medianizer `json:"medianizers"`
+	// Gas is the default gas configuration for all medianizers.
+	Gas Gas `json:"gas"`
+	// Transactions configures tracking of update transactions.
+	Transactions Transactions `json:"transactions"`
+	// Coordination enables taking turns with other relayers. If nil, every
+	// relayer updates Oracles whenever they are expired or stale.
+	Coordination *Coordination `json:"coordination"`
+	// FeedsFromOracle enables accepting prices for a pair only from feeds
+	// lifted in its Oracle contract. The static feeds list is used until
+	// feeds are fetched from the contract for the first time. The P2P
+	// transport accepts price messages from feeds lifted in any of
+	// the contracts.
+	FeedsFromOracle bool `json:"feedsFromOracle"`
+	// FeedsRefreshInterval is the interval in seconds at which feeds are
+	// fetched from Oracle contracts. If zero, the default interval is used.
+	FeedsRefreshInterval int64 `json:"feedsRefreshInterval"`
+	// SpendingLimitsPath is a directory in which recent spends counted in
+	// spending limits are stored, so limits are not reset when Spectre is
+	// restarted. If empty, limits are kept only in memory and apply to
+	// a single process lifetime.
+	SpendingLimitsPath string `json:"spendingLimitsPath"`
+
+	// limits contains spending limits of all medianizers. They are shared
+	// by medianizers and the transaction manager, which counts the cost
+	// of replacement transactions.
+	limits map[string][]*oracleGeth.SpendingLimit
+}
+
+// Coordination configures taking turns with other relayers.
+type Coordination struct {
+	// Relayers is the list of addresses of all relayers, including this one.
+	Relayers []string `json:"relayers"`
+	// SlotDuration is the duration of a single turn in seconds.
+	SlotDuration int64 `json:"slotDuration"`
+	// GracePeriod is the time in seconds after the Oracle expiration after
+	// which any relayer may update the Oracle.
+	GracePeriod int64 `json:"gracePeriod"`
+	// AnnounceIntents enables announcing sent updates to other relayers
+	// using the transport.
+	AnnounceIntents bool `json:"announceIntents"`
+}
+
+// Transactions configures tracking and replacement of sent transactions.
+type Transactions struct {
+	// PendingTimeout is the time in seconds after which a pending
+	// transaction is replaced with a transaction with higher fees.
+	PendingTimeout int64 `json:"pendingTimeout"`
+	// FeeBump is the fraction by which fees are increased in replacement
+	// transactions, e.g. 0.125 for 12.5%.
+	FeeBump float64 `json:"feeBump"`
+	// MaxReplacements is the maximum number of replacements of a single
+	// transaction. After that, the Oracle is not updated again until
+	// the transaction is mined or its nonce is used.
+	MaxReplacements int `json:"maxReplacements"`
 }
 
 type Medianizer struct {
@@ -32,6 +103,38 @@ type Medianizer struct {
 	OracleSpread     float64 `json:"oracleSpread"`
 	OracleExpiration int64   `json:"oracleExpiration"`
 	MsgExpiration    int64   `json:"msgExpiration"`
+	// Interval is the check interval for this pair in seconds. If zero,
+	// the global interval is used.
+	Interval int64 `json:"interval"`
+	// Gas overrides the global gas configuration for this medianizer.
+	Gas *Gas `json:"gas"`
+	// PriceSelection describes which prices are used if there are more
+	// prices than required to achieve a quorum, either "closestToMedian" or
+	// "freshest". If empty, "closestToMedian" is used.
+	PriceSelection string `json:"priceSelection"`
+}
+
+// Gas describes how gas limits and fees of Oracle updates are chosen.
+type Gas struct {
+	// GasLimit is a fixed gas limit. If zero, the gas is estimated when
+	// EstimateGas is true, otherwise a default limit is used.
+	GasLimit uint64 `json:"gasLimit"`
+	// EstimateGas enables gas estimation.
+	EstimateGas bool `json:"estimateGas"`
+	// GasMultiplier is applied to the estimated gas.
+	GasMultiplier float64 `json:"gasMultiplier"`
+	// PriorityFeePercentile is the percentile of priority fees paid in recent
+	// blocks which is used as the priority fee. If zero, the priority fee
+	// suggested by the node is used.
+	PriorityFeePercentile float64 `json:"priorityFeePercentile"`
+	// MaxFee is the cap for the fee per gas in gwei. If current fees are
+	// higher, the update is deferred. If zero, fees are not capped.
+	MaxFee decimal.Decimal `json:"maxFee"`
+	// MaxSpendPerHour is the maximum amount of ether which may be spent on
+	// fees during an hour. If zero, spending is not limited. The global
+	// limit is shared by all medianizers. Spent amounts are forgotten on
+	// restart unless the SpendingLimitsPath is set.
+	MaxSpendPerHour decimal.Decimal `json:"maxSpendPerHour"`
 }
 
 type Dependencies struct {
@@ -39,7 +142,19 @@ type Dependencies struct {
 	Signer         ethereum.Signer
 	Datastore      datastore.Datastore
 	EthereumClient ethereum.Client
-	Feeds          []ethereum.Address
+	TxStatus       spectre.TxStatus
+	// Transport is used to announce intents if enabled in the coordination
+	// config.
+	Transport transport.Transport
+	Feeds     []ethereum.Address
+	Logger    log.Logger
+}
+
+type TxManagerDependencies struct {
+	Context        context.Context
+	Signer         ethereum.Signer
+	EthereumClient ethereum.Client
+	Nonces         txmanager.NonceSource
 	Logger         log.Logger
 }
 
@@ -47,38 +162,376 @@ type DatastoreDependencies struct {
 	Context   context.Context
 	Signer    ethereum.Signer
 	Transport transport.Transport
-	Feeds     []ethereum.Address
-	Logger    log.Logger
+	// EthereumClient is used to fetch feeds from Oracle contracts if
+	// the feedsFromOracle option is enabled.
+	EthereumClient ethereum.Client
+	Feeds          []ethereum.Address
+	Logger         log.Logger
+	// StarkOracleName is used to verify StarkWare signatures of prices. If
+	// empty, StarkWare signatures are not verified.
+	StarkOracleName string
+}
+
+// override returns a copy of the global gas configuration with non-zero
+// values of the medianizer's configuration applied. The spending limit is
+// not overridden because the global limit is always applied.
+func (c Gas) override(o *Gas) Gas {
+	if o == nil {
+		return c
+	}
+	if o.GasLimit > 0 {
+		c.GasLimit = o.GasLimit
+		c.EstimateGas = false
+	}
+	if o.EstimateGas {
+		c.GasLimit = 0
+		c.EstimateGas = true
+	}
+	if o.GasMultiplier > 0 {
+		c.GasMultiplier = o.GasMultiplier
+	}
+	if o.PriorityFeePercentile > 0 {
+		c.PriorityFeePercentile = o.PriorityFeePercentile
+	}
+	if !o.MaxFee.IsZero() {
+		c.MaxFee = o.MaxFee
+	}
+	return c
+}
+
+// gasConfig returns the oracleGeth.GasConfig for the configuration. The
+// MaxSpendPerHour field is ignored, spending limits must be given
+// explicitly.
+func (c Gas) gasConfig(limits []*oracleGeth.SpendingLimit) oracleGeth.GasConfig {
+	cfg := oracleGeth.GasConfig{
+		GasLimit:              c.GasLimit,
+		EstimateGas:           c.EstimateGas,
+		GasMultiplier:         c.GasMultiplier,
+		PriorityFeePercentile: c.PriorityFeePercentile,
+		SpendingLimits:        limits,
+	}
+	if !c.MaxFee.IsZero() {
+		cfg.MaxFee = c.MaxFee.BigInt(gweiDecimals)
+	}
+	return cfg
+}
+
+// spendingLimit returns a new hourly spending limit or nil if the limit
+// is not set. If the SpendingLimitsPath is set, the state of the limit is
+// stored in the file with the given name in that directory.
+func (c *Spectre) spendingLimit(maxSpendPerHour decimal.Decimal, name string) (*oracleGeth.SpendingLimit, error) {
+	if maxSpendPerHour.IsZero() {
+		return nil, nil
+	}
+	limit := maxSpendPerHour.BigInt(etherDecimals)
+	if c.SpendingLimitsPath == "" {
+		return oracleGeth.NewSpendingLimit(limit, time.Hour), nil
+	}
+	return oracleGeth.NewPersistentSpendingLimit(limit, time.Hour, filepath.Join(c.SpendingLimitsPath, name+".json"))
+}
+
+// spendingLimits returns spending limits for each medianizer. Limits are
+// created once, so all components count costs in the same limits.
+func (c *Spectre) spendingLimits() (map[string][]*oracleGeth.SpendingLimit, error) {
+	if c.limits != nil {
+		return c.limits, nil
+	}
+	if c.SpendingLimitsPath != "" {
+		if err := os.MkdirAll(c.SpendingLimitsPath, 0700); err != nil {
+			return nil, err
+		}
+	}
+	// The global spending limit is shared by all medianizers:
+	globalLimit, err := c.spendingLimit(c.Gas.MaxSpendPerHour, "global")
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string][]*oracleGeth.SpendingLimit)
+	for name, pair := range c.Medianizers {
+		if globalLimit != nil {
+			limits[name] = append(limits[name], globalLimit)
+		}
+		if pair.Gas != nil {
+			l, err := c.spendingLimit(pair.Gas.MaxSpendPerHour, "pair-"+name)
+			if err != nil {
+				return nil, err
+			}
+			if l != nil {
+				limits[name] = append(limits[name], l)
+			}
+		}
+	}
+	c.limits = limits
+	return c.limits, nil
 }
 
 func (c *Spectre) ConfigureSpectre(d Dependencies) (*spectre.Spectre, error) {
 	cfg := spectre.Config{
 		Signer:    d.Signer,
 		Interval:  time.Second * time.Duration(c.Interval),
+		Workers:   c.Workers,
+		TxStatus:  d.TxStatus,
 		Datastore: d.Datastore,
 		Logger:    d.Logger,
 	}
+	if c.Coordination != nil {
+		cfg.Coordination = c.Coordination.coordination(d.Transport)
+	}
+	limits, err := c.spendingLimits()
+	if err != nil {
+		return nil, err
+	}
 	for name, pair := range c.Medianizers {
+		gas := c.Gas.override(pair.Gas)
 		cfg.Pairs = append(cfg.Pairs, &spectre.Pair{
 			AssetPair:        name,
 			OracleSpread:     pair.OracleSpread,
 			OracleExpiration: time.Second * time.Duration(pair.OracleExpiration),
 			PriceExpiration:  time.Second * time.Duration(pair.MsgExpiration),
-			Median:           oracleGeth.NewMedian(d.EthereumClient, ethereum.HexToAddress(pair.Contract)),
+			Interval:         time.Second * time.Duration(pair.Interval),
+			PriceSelection:   priceSelections[pair.PriceSelection],
+			Median: oracleGeth.NewMedianWithGas(
+				d.EthereumClient,
+				ethereum.HexToAddress(pair.Contract),
+				gas.gasConfig(limits[name]),
+			),
 		})
 	}
 	return spectreFactory(d.Context, cfg)
 }
 
+// Topics returns additional transport topics required by Spectre.
+func (c *Spectre) Topics() map[string]transport.Message {
+	if c.Coordination == nil || !c.Coordination.AnnounceIntents {
+		return nil
+	}
+	return map[string]transport.Message{messages.IntentMessageName: (*messages.Intent)(nil)}
+}
+
+// coordination returns the spectre.Coordination for the configuration.
+// The transport is used only if intents are announced.
+func (c *Coordination) coordination(t transport.Transport) *spectre.Coordination {
+	cfg := &spectre.Coordination{
+		SlotDuration: time.Second * time.Duration(c.SlotDuration),
+		GracePeriod:  time.Second * time.Duration(c.GracePeriod),
+	}
+	for _, r := range c.Relayers {
+		cfg.Relayers = append(cfg.Relayers, ethereum.HexToAddress(r))
+	}
+	if c.AnnounceIntents {
+		cfg.Transport = t
+	}
+	return cfg
+}
+
+// ConfigureTxManager returns a transaction manager which should be used as
+// the Ethereum client for Spectre, so update transactions are tracked.
+func (c *Spectre) ConfigureTxManager(d TxManagerDependencies) (*txmanager.Manager, error) {
+	cfg := txmanager.Config{
+		Client:          d.EthereumClient,
+		Signer:          d.Signer,
+		Nonces:          d.Nonces,
+		PendingTimeout:  time.Second * time.Duration(c.Transactions.PendingTimeout),
+		FeeBump:         c.Transactions.FeeBump,
+		MaxReplacements: c.Transactions.MaxReplacements,
+		Logger:          d.Logger,
+	}
+	// Replacements must not exceed the fee cap of the Oracle, which may be
+	// overridden for a medianizer. If medianizers with different caps use
+	// the same contract, the lowest cap is used:
+	var globalMaxFee *big.Int
+	if !c.Gas.MaxFee.IsZero() {
+		globalMaxFee = c.Gas.MaxFee.BigInt(gweiDecimals)
+	}
+	maxFees := make(map[ethereum.Address]*big.Int)
+	for _, pair := range c.Medianizers {
+		address := ethereum.HexToAddress(pair.Contract)
+		maxFee := c.Gas.override(pair.Gas).gasConfig(nil).MaxFee
+		if prev, ok := maxFees[address]; ok && (maxFee == nil || (prev != nil && prev.Cmp(maxFee) < 0)) {
+			maxFee = prev
+		}
+		maxFees[address] = maxFee
+	}
+	cfg.MaxFee = func(address ethereum.Address) *big.Int {
+		if maxFee, ok := maxFees[address]; ok {
+			return maxFee
+		}
+		return globalMaxFee
+	}
+	pairLimits, err := c.spendingLimits()
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[ethereum.Address][]txmanager.SpendingLimit)
+	for name, l := range pairLimits {
+		address := ethereum.HexToAddress(c.Medianizers[name].Contract)
+	next:
+		for _, sl := range l {
+			// The global limit must be counted only once, even if multiple
+			// medianizers use the same contract:
+			for _, e := range limits[address] {
+				if e == sl {
+					continue next
+				}
+			}
+			limits[address] = append(limits[address], sl)
+		}
+	}
+	cfg.SpendingLimits = func(address ethereum.Address) []txmanager.SpendingLimit {
+		return limits[address]
+	}
+	return txManagerFactory(d.Context, cfg)
+}
+
 func (c *Spectre) ConfigureDatastore(d DatastoreDependencies) (datastore.Datastore, error) {
 	cfg := datastoreMemory.Config{
-		Signer:    d.Signer,
-		Transport: d.Transport,
-		Pairs:     make(map[string]*datastoreMemory.Pair),
-		Logger:    d.Logger,
+		Signer:               d.Signer,
+		StarkOracleName:      d.StarkOracleName,
+		Transport:            d.Transport,
+		Pairs:                make(map[string]*datastoreMemory.Pair),
+		FeedsRefreshInterval: time.Second * time.Duration(c.FeedsRefreshInterval),
+		Logger:               d.Logger,
 	}
-	for name := range c.Medianizers {
-		cfg.Pairs[name] = &datastoreMemory.Pair{Feeds: d.Feeds}
+	for name, pair := range c.Medianizers {
+		p := &datastoreMemory.Pair{Feeds: d.Feeds}
+		if c.FeedsFromOracle {
+			p.FeedsSource = oracleGeth.NewMedian(d.EthereumClient, ethereum.HexToAddress(pair.Contract))
+		}
+		cfg.Pairs[name] = p
+	}
+	// Keep the list of feeders used by the transport to validate messages
+	// in sync with feeds fetched from Oracle contracts:
+	if t, ok := d.Transport.(feedersSetter); ok && c.FeedsFromOracle {
+		cfg.FeedsUpdated = t.SetFeeders
 	}
 	return datastoreFactory(d.Context, cfg)
 }
+
+// feedersSetter is implemented by transports that validate authors of
+// messages against a list of feeders which can be changed at runtime.
+type feedersSetter interface {
+	SetFeeders(feeders []ethereum.Address) error
+}
+
+const (
+	gweiDecimals  = 9
+	etherDecimals = 18
+)
+
+// priceSelections maps names of price selection strategies to their values.
+var priceSelections = map[string]spectre.PriceSelection{
+	"":                                     spectre.SelectClosestToMedian,
+	spectre.SelectClosestToMedian.String(): spectre.SelectClosestToMedian,
+	spectre.SelectFreshest.String():        spectre.SelectFreshest,
+}
+
+var pairRegexp = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// Validate implements the config.Validator interface.
+func (c *Spectre) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.Interval <= 0 {
+		errs = append(errs, config.ValidationError{Path: "interval", Msg: "must be greater than zero"})
+	}
+	if c.Workers < 0 {
+		errs = append(errs, config.ValidationError{Path: "workers", Msg: "must not be negative"})
+	}
+	if c.FeedsRefreshInterval < 0 {
+		errs = append(errs, config.ValidationError{Path: "feedsRefreshInterval", Msg: "must not be negative"})
+	}
+	for name := range c.Medianizers {
+		if !pairRegexp.MatchString(name) {
+			errs = append(errs, config.ValidationError{
+				Path: config.JoinPath("medianizers", config.KeyPath(name)),
+				Msg:  fmt.Sprintf("invalid pair name %q, pairs must be written as uppercase symbols without separators, e.g. BTCUSD", name),
+			})
+		}
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Medianizer) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if !ethereum.IsHexAddress(c.Contract) {
+		errs = append(errs, config.ValidationError{Path: "oracle", Msg: fmt.Sprintf("invalid Ethereum address %q", c.Contract)})
+	}
+	if c.OracleSpread <= 0 || c.OracleSpread > 100 {
+		errs = append(errs, config.ValidationError{Path: "oracleSpread", Msg: "must be greater than 0 and less than or equal to 100"})
+	}
+	if c.OracleExpiration <= 0 {
+		errs = append(errs, config.ValidationError{Path: "oracleExpiration", Msg: "must be greater than zero"})
+	}
+	if c.MsgExpiration <= 0 {
+		errs = append(errs, config.ValidationError{Path: "msgExpiration", Msg: "must be greater than zero"})
+	}
+	if c.Interval < 0 {
+		errs = append(errs, config.ValidationError{Path: "interval", Msg: "must not be negative"})
+	}
+	if _, ok := priceSelections[c.PriceSelection]; !ok {
+		errs = append(errs, config.ValidationError{
+			Path: "priceSelection",
+			Msg:  fmt.Sprintf("unknown price selection %q, must be either %q or %q", c.PriceSelection, spectre.SelectClosestToMedian, spectre.SelectFreshest),
+		})
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Gas) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.GasLimit > 0 && c.EstimateGas {
+		errs = append(errs, config.ValidationError{Path: "estimateGas", Msg: "must not be used together with gasLimit"})
+	}
+	if c.GasMultiplier < 0 {
+		errs = append(errs, config.ValidationError{Path: "gasMultiplier", Msg: "must not be negative"})
+	}
+	if c.PriorityFeePercentile < 0 || c.PriorityFeePercentile > 100 {
+		errs = append(errs, config.ValidationError{Path: "priorityFeePercentile", Msg: "must be between 0 and 100"})
+	}
+	if c.MaxFee.Sign() < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxFee", Msg: "must not be negative"})
+	}
+	if c.MaxSpendPerHour.Sign() < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxSpendPerHour", Msg: "must not be negative"})
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Transactions) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if c.PendingTimeout < 0 {
+		errs = append(errs, config.ValidationError{Path: "pendingTimeout", Msg: "must not be negative"})
+	}
+	if c.FeeBump != 0 && c.FeeBump < 0.1 {
+		errs = append(errs, config.ValidationError{Path: "feeBump", Msg: "must be at least 0.1, otherwise nodes reject replacements"})
+	}
+	if c.MaxReplacements < 0 {
+		errs = append(errs, config.ValidationError{Path: "maxReplacements", Msg: "must not be negative"})
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *Coordination) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if len(c.Relayers) == 0 {
+		errs = append(errs, config.ValidationError{Path: "relayers", Msg: "must not be empty"})
+	}
+	for i, r := range c.Relayers {
+		if !ethereum.IsHexAddress(r) {
+			errs = append(errs, config.ValidationError{
+				Path: config.JoinPath("relayers", config.IndexPath(i)),
+				Msg:  fmt.Sprintf("invalid Ethereum address %q", r),
+			})
+		}
+	}
+	if c.SlotDuration <= 0 {
+		errs = append(errs, config.ValidationError{Path: "slotDuration", Msg: "must be greater than zero"})
+	}
+	if c.GracePeriod < 0 {
+		errs = append(errs, config.ValidationError{Path: "gracePeriod", Msg: "must not be negative"})
+	}
+	return errs
+}
@@ -2,17 +2,25 @@ package spectre
 
 import (
 	"context"
+	"math/big"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	datastoreMemory "github.com/toknowwhy/theunit-oracle/pkg/datastore/memory"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	ethereumMocks "github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/txmanager"
 	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
 	"github.com/toknowwhy/theunit-oracle/pkg/spectre"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/local"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 )
 
 func TestSpectre_Configure(t *testing.T) {
@@ -32,12 +40,15 @@ func TestSpectre_Configure(t *testing.T) {
 
 	config := Spectre{
 		Interval: interval,
+		Workers:  2,
 		Medianizers: map[string]Medianizer{
 			"AAABBB": {
 				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
 				OracleSpread:     0.1,
 				OracleExpiration: 15500,
 				MsgExpiration:    1800,
+				Interval:         30,
+				PriceSelection:   "freshest",
 			},
 		},
 	}
@@ -47,8 +58,11 @@ func TestSpectre_Configure(t *testing.T) {
 		assert.Equal(t, signer, cfg.Signer)
 		assert.Equal(t, ds, cfg.Datastore)
 		assert.Equal(t, secToDuration(interval), cfg.Interval)
+		assert.Equal(t, 2, cfg.Workers)
 		assert.Equal(t, logger, cfg.Logger)
+		assert.Equal(t, secToDuration(30), cfg.Pairs[0].Interval)
 		assert.Equal(t, "AAABBB", cfg.Pairs[0].AssetPair)
+		assert.Equal(t, spectre.SelectFreshest, cfg.Pairs[0].PriceSelection)
 		assert.Equal(t, secToDuration(config.Medianizers["AAABBB"].OracleExpiration), cfg.Pairs[0].OracleExpiration)
 		assert.Equal(t, secToDuration(config.Medianizers["AAABBB"].MsgExpiration), cfg.Pairs[0].PriceExpiration)
 		assert.Equal(t, config.Medianizers["AAABBB"].OracleSpread, cfg.Pairs[0].OracleSpread)
@@ -71,3 +85,344 @@ func TestSpectre_Configure(t *testing.T) {
 func secToDuration(s int64) time.Duration {
 	return time.Duration(s) * time.Second
 }
+
+func TestSpectre_Validate(t *testing.T) {
+	config := Spectre{
+		Interval: 0,
+		Workers:  -1,
+		Medianizers: map[string]Medianizer{
+			"AAABBB": {
+				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+				OracleSpread:     0.1,
+				OracleExpiration: 15500,
+				MsgExpiration:    1800,
+			},
+			"aaa/bbb": {
+				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+				OracleSpread:     0.1,
+				OracleExpiration: 15500,
+				MsgExpiration:    1800,
+			},
+		},
+	}
+
+	errs := config.Validate()
+	require.Len(t, errs, 3)
+	assert.Equal(t, "interval", errs[0].Path)
+	assert.Equal(t, "workers", errs[1].Path)
+	assert.Equal(t, `medianizers["aaa/bbb"]`, errs[2].Path)
+}
+
+func TestMedianizer_Validate(t *testing.T) {
+	valid := Medianizer{
+		Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+		OracleSpread:     0.1,
+		OracleExpiration: 15500,
+		MsgExpiration:    1800,
+	}
+	assert.Empty(t, valid.Validate())
+
+	invalid := Medianizer{
+		Contract:         "0x123",
+		OracleSpread:     101,
+		OracleExpiration: 0,
+		MsgExpiration:    -1,
+		Interval:         -1,
+		PriceSelection:   "random",
+	}
+	var paths []string
+	for _, err := range invalid.Validate() {
+		paths = append(paths, err.Path)
+	}
+	assert.Equal(t, []string{"oracle", "oracleSpread", "oracleExpiration", "msgExpiration", "interval", "priceSelection"}, paths)
+}
+
+func TestSpectre_Configure_Gas(t *testing.T) {
+	prevSpectreFactory := spectreFactory
+	defer func() { spectreFactory = prevSpectreFactory }()
+
+	config := Spectre{
+		Interval: 10,
+		Gas: Gas{
+			EstimateGas:           true,
+			GasMultiplier:         1.5,
+			PriorityFeePercentile: 50,
+			MaxFee:                decimal.RequireFromString("100"),
+			MaxSpendPerHour:       decimal.RequireFromString("0.5"),
+		},
+		Medianizers: map[string]Medianizer{
+			"AAABBB": {
+				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+				OracleSpread:     0.1,
+				OracleExpiration: 15500,
+				MsgExpiration:    1800,
+			},
+			"XXXYYY": {
+				Contract:         "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+				OracleSpread:     0.1,
+				OracleExpiration: 15500,
+				MsgExpiration:    1800,
+				Gas: &Gas{
+					GasLimit:        300000,
+					MaxFee:          decimal.RequireFromString("50.5"),
+					MaxSpendPerHour: decimal.RequireFromString("0.1"),
+				},
+			},
+		},
+	}
+
+	spectreFactory = func(ctx context.Context, cfg spectre.Config) (*spectre.Spectre, error) {
+		return &spectre.Spectre{}, nil
+	}
+
+	_, err := config.ConfigureSpectre(Dependencies{
+		Context:        context.Background(),
+		EthereumClient: &ethereumMocks.Client{},
+		Logger:         null.New(),
+	})
+	require.NoError(t, err)
+
+	global := config.Gas.gasConfig(nil)
+	assert.Equal(t, uint64(0), global.GasLimit)
+	assert.True(t, global.EstimateGas)
+	assert.Equal(t, 1.5, global.GasMultiplier)
+	assert.Equal(t, float64(50), global.PriorityFeePercentile)
+	assert.Equal(t, big.NewInt(100e9), global.MaxFee)
+
+	pair := config.Gas.override(config.Medianizers["XXXYYY"].Gas).gasConfig(nil)
+	assert.Equal(t, uint64(300000), pair.GasLimit)
+	assert.False(t, pair.EstimateGas)
+	assert.Equal(t, float64(50), pair.PriorityFeePercentile)
+	assert.Equal(t, big.NewInt(50.5e9), pair.MaxFee)
+
+	limit, err := config.spendingLimit(config.Gas.MaxSpendPerHour, "global")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(0.5e18), limit.Limit())
+	limit, err = config.spendingLimit(decimal.Zero, "global")
+	require.NoError(t, err)
+	assert.Nil(t, limit)
+}
+
+func TestSpectre_spendingLimits_Persistent(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "limits")
+	newConfig := func() *Spectre {
+		return &Spectre{
+			SpendingLimitsPath: dir,
+			Gas:                Gas{MaxSpendPerHour: decimal.RequireFromString("0.5")},
+			Medianizers: map[string]Medianizer{
+				"AAABBB": {Gas: &Gas{MaxSpendPerHour: decimal.RequireFromString("0.1")}},
+			},
+		}
+	}
+
+	limits, err := newConfig().spendingLimits()
+	require.NoError(t, err)
+	require.Len(t, limits["AAABBB"], 2)
+	release, err := limits["AAABBB"][1].Reserve(time.Now(), big.NewInt(1e17))
+	require.NoError(t, err)
+	require.NotNil(t, release)
+
+	// Spends are restored after a restart:
+	limits, err = newConfig().spendingLimits()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(0), limits["AAABBB"][0].Spent())
+	assert.Equal(t, big.NewInt(1e17), limits["AAABBB"][1].Spent())
+}
+
+func TestGas_Validate(t *testing.T) {
+	invalid := Gas{
+		GasLimit:              1000,
+		EstimateGas:           true,
+		GasMultiplier:         -1,
+		PriorityFeePercentile: 101,
+		MaxFee:                decimal.RequireFromString("-1"),
+		MaxSpendPerHour:       decimal.RequireFromString("-1"),
+	}
+	var paths []string
+	for _, err := range invalid.Validate() {
+		paths = append(paths, err.Path)
+	}
+	assert.Equal(t, []string{"estimateGas", "gasMultiplier", "priorityFeePercentile", "maxFee", "maxSpendPerHour"}, paths)
+}
+
+func TestSpectre_ConfigureTxManager(t *testing.T) {
+	prevTxManagerFactory := txManagerFactory
+	defer func() { txManagerFactory = prevTxManagerFactory }()
+
+	signer := &ethereumMocks.Signer{}
+	ethClient := &ethereumMocks.Client{}
+	logger := null.New()
+
+	config := Spectre{
+		Gas: Gas{
+			MaxFee:          decimal.RequireFromString("100"),
+			MaxSpendPerHour: decimal.RequireFromString("0.5"),
+		},
+		Transactions: Transactions{
+			PendingTimeout:  120,
+			FeeBump:         0.2,
+			MaxReplacements: 5,
+		},
+		Medianizers: map[string]Medianizer{
+			"AAABBB": {
+				Contract: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f",
+				Gas:      &Gas{MaxSpendPerHour: decimal.RequireFromString("0.1")},
+			},
+			"XXXYYY": {
+				Contract: "0x1111111111111111111111111111111111111111",
+				Gas:      &Gas{MaxFee: decimal.RequireFromString("50")},
+			},
+		},
+	}
+
+	txManagerFactory = func(ctx context.Context, cfg txmanager.Config) (*txmanager.Manager, error) {
+		assert.NotNil(t, ctx)
+		assert.Equal(t, signer, cfg.Signer)
+		assert.Equal(t, ethClient, cfg.Client)
+		assert.Equal(t, 2*time.Minute, cfg.PendingTimeout)
+		assert.Equal(t, 0.2, cfg.FeeBump)
+		assert.Equal(t, 5, cfg.MaxReplacements)
+		// Fee caps overridden for a medianizer are used for its contract:
+		assert.Equal(t, big.NewInt(100e9), cfg.MaxFee(ethereum.HexToAddress("0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f")))
+		assert.Equal(t, big.NewInt(50e9), cfg.MaxFee(ethereum.HexToAddress("0x1111111111111111111111111111111111111111")))
+		assert.Equal(t, big.NewInt(100e9), cfg.MaxFee(ethereum.Address{}))
+		assert.Equal(t, logger, cfg.Logger)
+
+		// Replacements must be counted in the same limits as Oracle updates:
+		pairLimits, err := config.spendingLimits()
+		require.NoError(t, err)
+		limits := pairLimits["AAABBB"]
+		require.Len(t, limits, 2)
+		assert.Equal(
+			t,
+			[]txmanager.SpendingLimit{limits[0], limits[1]},
+			cfg.SpendingLimits(ethereum.HexToAddress("0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f")),
+		)
+		assert.Empty(t, cfg.SpendingLimits(ethereum.Address{}))
+		return &txmanager.Manager{}, nil
+	}
+
+	m, err := config.ConfigureTxManager(TxManagerDependencies{
+		Context:        context.Background(),
+		Signer:         signer,
+		EthereumClient: ethClient,
+		Logger:         logger,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, m)
+}
+
+func TestTransactions_Validate(t *testing.T) {
+	assert.Empty(t, (&Transactions{}).Validate())
+
+	invalid := Transactions{
+		PendingTimeout:  -1,
+		FeeBump:         0.05,
+		MaxReplacements: -1,
+	}
+	var paths []string
+	for _, err := range invalid.Validate() {
+		paths = append(paths, err.Path)
+	}
+	assert.Equal(t, []string{"pendingTimeout", "feeBump", "maxReplacements"}, paths)
+}
+
+func TestSpectre_Configure_Coordination(t *testing.T) {
+	prevSpectreFactory := spectreFactory
+	defer func() { spectreFactory = prevSpectreFactory }()
+
+	tra := local.New(context.Background(), 0, nil)
+	config := Spectre{
+		Coordination: &Coordination{
+			Relayers:        []string{"0x1111111111111111111111111111111111111111"},
+			SlotDuration:    30,
+			GracePeriod:     60,
+			AnnounceIntents: true,
+		},
+	}
+
+	spectreFactory = func(ctx context.Context, cfg spectre.Config) (*spectre.Spectre, error) {
+		require.NotNil(t, cfg.Coordination)
+		assert.Equal(t, []ethereum.Address{ethereum.HexToAddress("0x1111111111111111111111111111111111111111")}, cfg.Coordination.Relayers)
+		assert.Equal(t, 30*time.Second, cfg.Coordination.SlotDuration)
+		assert.Equal(t, time.Minute, cfg.Coordination.GracePeriod)
+		assert.Equal(t, tra, cfg.Coordination.Transport)
+		return &spectre.Spectre{}, nil
+	}
+
+	_, err := config.ConfigureSpectre(Dependencies{
+		Context:   context.Background(),
+		Transport: tra,
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, config.Topics(), messages.IntentMessageName)
+
+	// Without intents, the transport is not used:
+	config.Coordination.AnnounceIntents = false
+	assert.Empty(t, config.Topics())
+	assert.Nil(t, config.Coordination.coordination(tra).Transport)
+}
+
+func TestCoordination_Validate(t *testing.T) {
+	invalid := Coordination{
+		Relayers:     []string{"0x1111111111111111111111111111111111111111", "foo"},
+		SlotDuration: 0,
+		GracePeriod:  -1,
+	}
+	var paths []string
+	for _, err := range invalid.Validate() {
+		paths = append(paths, err.Path)
+	}
+	assert.Equal(t, []string{"relayers[1]", "slotDuration", "gracePeriod"}, paths)
+}
+
+func TestSpectre_ConfigureDatastore_FeedsFromOracle(t *testing.T) {
+	prevDatastoreFactory := datastoreFactory
+	defer func() { datastoreFactory = prevDatastoreFactory }()
+
+	feeds := []ethereum.Address{ethereum.HexToAddress("0x07a35a1d4b751a818d93aa38e615c0df23064881")}
+	config := Spectre{
+		Medianizers: map[string]Medianizer{
+			"AAABBB": {Contract: "0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f"},
+		},
+		FeedsFromOracle:      true,
+		FeedsRefreshInterval: 60,
+	}
+
+	datastoreFactory = func(ctx context.Context, cfg datastoreMemory.Config) (datastore.Datastore, error) {
+		assert.Equal(t, time.Minute, cfg.FeedsRefreshInterval)
+		require.Contains(t, cfg.Pairs, "AAABBB")
+		assert.Equal(t, feeds, cfg.Pairs["AAABBB"].Feeds)
+		require.NotNil(t, cfg.Pairs["AAABBB"].FeedsSource)
+		assert.Equal(t,
+			ethereum.HexToAddress("0xe0F30cb149fAADC7247E953746Be9BbBB6B5751f"),
+			cfg.Pairs["AAABBB"].FeedsSource.(oracle.Median).Address(),
+		)
+		// Feeds fetched from contracts are passed to the transport:
+		require.NotNil(t, cfg.FeedsUpdated)
+		require.NoError(t, cfg.FeedsUpdated(feeds))
+		return &datastoreMemory.Datastore{}, nil
+	}
+
+	tra := &testFeedersTransport{Local: local.New(context.Background(), 0, nil)}
+	_, err := config.ConfigureDatastore(DatastoreDependencies{
+		Context:        context.Background(),
+		Transport:      tra,
+		EthereumClient: &ethereumMocks.Client{},
+		Feeds:          feeds,
+		Logger:         null.New(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, feeds, tra.feeders)
+}
+
+type testFeedersTransport struct {
+	*local.Local
+	feeders []ethereum.Address
+}
+
+func (t *testFeedersTransport) SetFeeders(feeders []ethereum.Address) error {
+	t.feeders = feeders
+	return nil
+}
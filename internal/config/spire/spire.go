@@ -2,6 +2,11 @@ package spire
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	datastoreMemory "github.com/toknowwhy/theunit-oracle/pkg/datastore/memory"
@@ -54,6 +59,9 @@ type DatastoreDependencies struct {
 	Transport transport.Transport
 	Feeds     []ethereum.Address
 	Logger    log.Logger
+	// StarkOracleName is used to verify StarkWare signatures of prices. If
+	// empty, StarkWare signatures are not verified.
+	StarkOracleName string
 }
 
 func (c *Spire) ConfigureAgent(d AgentDependencies) (*spire.Agent, error) {
@@ -81,13 +89,41 @@ func (c *Spire) ConfigureClient(d ClientDependencies) (*spire.Client, error) {
 
 func (c *Spire) ConfigureDatastore(d DatastoreDependencies) (datastore.Datastore, error) {
 	cfg := datastoreMemory.Config{
-		Signer:    d.Signer,
-		Transport: d.Transport,
-		Pairs:     make(map[string]*datastoreMemory.Pair),
-		Logger:    d.Logger,
+		Signer:          d.Signer,
+		StarkOracleName: d.StarkOracleName,
+		Transport:       d.Transport,
+		Pairs:           make(map[string]*datastoreMemory.Pair),
+		Logger:          d.Logger,
 	}
 	for _, name := range c.Pairs {
 		cfg.Pairs[name] = &datastoreMemory.Pair{Feeds: d.Feeds}
 	}
 	return datastoreFactory(d.Context, cfg)
 }
+
+var pairRegexp = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// Validate implements the config.Validator interface.
+func (c *Spire) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	for i, pair := range c.Pairs {
+		if !pairRegexp.MatchString(pair) {
+			errs = append(errs, config.ValidationError{
+				Path: config.JoinPath("pairs", config.IndexPath(i)),
+				Msg:  fmt.Sprintf("invalid pair name %q, pairs must be written as uppercase symbols without separators, e.g. BTCUSD", pair),
+			})
+		}
+	}
+	return errs
+}
+
+// Validate implements the config.Validator interface.
+func (c *RPC) Validate() config.ValidationErrors {
+	if c.Address == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return config.ValidationErrors{{Path: "address", Msg: fmt.Sprintf("invalid address: %s", err)}}
+	}
+	return nil
+}
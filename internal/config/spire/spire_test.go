@@ -81,3 +81,17 @@ func TestSpire_ConfigureClient(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, c)
 }
+
+func TestSpire_Validate(t *testing.T) {
+	config := Spire{
+		RPC:   RPC{Address: "127.0.0.1:9101"},
+		Pairs: []string{"AAABBB", "aaabbb"},
+	}
+
+	errs := config.Validate()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "pairs[1]", errs[0].Path)
+
+	assert.Empty(t, config.RPC.Validate())
+	assert.Len(t, (&RPC{Address: "localhost"}).Validate(), 1)
+}
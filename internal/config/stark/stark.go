@@ -0,0 +1,74 @@
+package stark
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
+)
+
+// maxOracleNameLen is the maximum length of the oracle name supported by
+// StarkEx price messages.
+const maxOracleNameLen = 5
+
+type Stark struct {
+	// OracleName is the oracle name included in StarkWare signatures, e.g.
+	// "Maker". If empty, prices are not signed with StarkWare signatures
+	// and those signatures are not verified.
+	OracleName string `json:"oracleName"`
+	// PrivateKey is a hex encoded STARK private key used by Ghost to sign
+	// prices. It should be given as a secret reference, e.g.
+	// "env:STARK_PRIVATE_KEY", rather than written directly in the config
+	// file. If empty, prices are not signed with StarkWare signatures.
+	PrivateKey string `json:"privateKey" config:"secret"`
+}
+
+// ConfigureSigner returns a new stark.Signer instance, or nil if
+// the private key is not set.
+func (c *Stark) ConfigureSigner() (stark.Signer, error) {
+	if c.PrivateKey == "" {
+		return nil, nil
+	}
+	key, ok := parsePrivateKey(c.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid STARK private key")
+	}
+	return stark.NewSigner(key)
+}
+
+// Validate implements the config.Validator interface.
+func (c *Stark) Validate() config.ValidationErrors {
+	var errs config.ValidationErrors
+	if len(c.OracleName) > maxOracleNameLen {
+		errs = append(errs, config.ValidationError{
+			Path: "oracleName",
+			Msg:  fmt.Sprintf("must not be longer than %d bytes", maxOracleNameLen),
+		})
+	}
+	if c.PrivateKey != "" {
+		if c.OracleName == "" {
+			errs = append(errs, config.ValidationError{Path: "oracleName", Msg: "must be set if the private key is set"})
+		}
+		// The key itself is never included in the message, because it may
+		// be a secret:
+		if _, ok := parsePrivateKey(c.PrivateKey); !ok && !config.IsSecretReference(c.PrivateKey) {
+			errs = append(errs, config.ValidationError{Path: "privateKey", Msg: "must be a hex encoded STARK private key"})
+		}
+	}
+	return errs
+}
+
+func parsePrivateKey(s string) (*big.Int, bool) {
+	key, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return nil, false
+	}
+	if _, err := stark.NewSigner(key); err != nil {
+		return nil, false
+	}
+	return key, true
+}
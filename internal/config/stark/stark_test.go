@@ -0,0 +1,38 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStark_ConfigureSigner(t *testing.T) {
+	s, err := (&Stark{}).ConfigureSigner()
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = (&Stark{
+		OracleName: "Maker",
+		PrivateKey: "0x3c1e9550e66958296d11b60f8e8e7a7ad990d07fa65d5f7652c4a6c87d4e3cc",
+	}).ConfigureSigner()
+	require.NoError(t, err)
+	assert.Equal(t, "77a3b314db07c45076d11f62b6f9e748a39790441823307743cf00d6597ea43", s.PublicKey().Text(16))
+
+	_, err = (&Stark{OracleName: "Maker", PrivateKey: "xyz"}).ConfigureSigner()
+	assert.Error(t, err)
+}
+
+func TestStark_Validate(t *testing.T) {
+	assert.Empty(t, (&Stark{}).Validate())
+	assert.Empty(t, (&Stark{OracleName: "Maker"}).Validate())
+
+	errs := (&Stark{OracleName: "TooLong"}).Validate()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "oracleName", errs[0].Path)
+
+	errs = (&Stark{PrivateKey: "0"}).Validate()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "oracleName", errs[0].Path)
+	assert.Equal(t, "privateKey", errs[1].Path)
+}
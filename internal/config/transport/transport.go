@@ -10,6 +10,8 @@ import (
 
 	"github.com/libp2p/go-libp2p-core/crypto"
 
+	"github.com/toknowwhy/theunit-oracle/internal/config"
+
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
@@ -27,7 +29,7 @@ type Transport struct {
 }
 
 type P2P struct {
-	PrivKeySeed      string   `json:"privKeySeed"`
+	PrivKeySeed      string   `json:"privKeySeed" config:"secret"`
 	ListenAddrs      []string `json:"listenAddrs"`
 	BootstrapAddrs   []string `json:"bootstrapAddrs"`
 	DirectPeersAddrs []string `json:"directPeersAddrs"`
@@ -40,6 +42,12 @@ type Dependencies struct {
 	Signer  ethereum.Signer
 	Feeds   []ethereum.Address
 	Logger  log.Logger
+	// StarkOracleName is used to verify StarkWare signatures of prices. If
+	// empty, StarkWare signatures are not verified.
+	StarkOracleName string
+	// Topics is the list of additional topics to subscribe to, along with
+	// the price topic.
+	Topics map[string]transport.Message
 }
 
 type BootstrapDependencies struct {
@@ -52,10 +60,14 @@ func (c *Transport) Configure(d Dependencies) (transport.Transport, error) {
 	if err != nil {
 		return nil, err
 	}
+	topics := map[string]transport.Message{messages.PriceMessageName: (*messages.Price)(nil)}
+	for topic, msg := range d.Topics {
+		topics[topic] = msg
+	}
 	cfg := p2p.Config{
 		Mode:             p2p.ClientMode,
 		PeerPrivKey:      peerPrivKey,
-		Topics:           map[string]transport.Message{messages.PriceMessageName: (*messages.Price)(nil)},
+		Topics:           topics,
 		MessagePrivKey:   ethkey.NewPrivKey(d.Signer),
 		ListenAddrs:      c.P2P.ListenAddrs,
 		BootstrapAddrs:   c.P2P.BootstrapAddrs,
@@ -64,6 +76,7 @@ func (c *Transport) Configure(d Dependencies) (transport.Transport, error) {
 		FeedersAddrs:     d.Feeds,
 		Discovery:        !c.P2P.DisableDiscovery,
 		Signer:           d.Signer,
+		StarkOracleName:  d.StarkOracleName,
 		Logger:           d.Logger,
 		AppName:          "spire",
 		AppVersion:       "1",
@@ -116,3 +129,21 @@ func (c *Transport) generatePrivKey() (crypto.PrivKey, error) {
 	}
 	return privKey, nil
 }
+
+// Validate implements the config.Validator interface.
+func (c *P2P) Validate() config.ValidationErrors {
+	if len(c.PrivKeySeed) == 0 || config.IsSecretReference(c.PrivKeySeed) {
+		return nil
+	}
+	seed, err := hex.DecodeString(c.PrivKeySeed)
+	if err != nil {
+		return config.ValidationErrors{{Path: "privKeySeed", Msg: "must be a hex encoded value"}}
+	}
+	if len(seed) != ed25519.SeedSize {
+		return config.ValidationErrors{{
+			Path: "privKeySeed",
+			Msg:  fmt.Sprintf("must be %d bytes long, got %d bytes", ed25519.SeedSize, len(seed)),
+		}}
+	}
+	return nil
+}
@@ -145,3 +145,10 @@ func TestTransport_P2P_InvalidSeed(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestTransport_P2P_Validate(t *testing.T) {
+	assert.Empty(t, (&P2P{}).Validate())
+	assert.Empty(t, (&P2P{PrivKeySeed: "1cbfa1b2b6eb2b2dc1d1e7d3f3f4c1f2b6d0dc1b2f3e4d5c6b7a8f9e0d1c2b3a"}).Validate())
+	assert.Len(t, (&P2P{PrivKeySeed: "invalid"}).Validate(), 1)
+	assert.Len(t, (&P2P{PrivKeySeed: "abcd"}).Validate(), 1)
+}
@@ -0,0 +1,355 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SharedSections is a list of top-level config sections used by the
+// binaries in this repository. A single config file may be shared by
+// multiple binaries, so these sections are not reported as unknown fields
+// even if a binary does not use them.
+var SharedSections = []string{"gofer", "ethereum", "transport", "ghost", "spectre", "spire", "feeds", "stark"}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+var typeSelectorType = reflect.TypeOf((*TypeSelector)(nil)).Elem()
+
+// Validator is implemented by config structures which have to check values
+// that cannot be verified by the JSON decoder. Paths of returned errors are
+// relative to the validated structure.
+type Validator interface {
+	Validate() ValidationErrors
+}
+
+// TypeSelector may be implemented by types with a custom JSON unmarshaler
+// which accept multiple JSON representations. Values of types which
+// implement the json.Unmarshaler interface are not checked for unknown
+// fields and invalid types, unless they also implement this interface.
+// The SelectType method must return the type that describes the given raw
+// JSON value, it is called on a zero value.
+type TypeSelector interface {
+	SelectType(raw interface{}) reflect.Type
+}
+
+// ValidationError describes a single problem found in the config.
+type ValidationError struct {
+	// Path is a JSON path to the invalid value, e.g.
+	// spectre.medianizers.BTCUSD.oracleSpread.
+	Path string
+	Msg  string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// ValidationErrors is a list of all problems found in the config.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Prefix returns errors with paths prefixed with the given path.
+func (e ValidationErrors) Prefix(path string) ValidationErrors {
+	r := make(ValidationErrors, len(e))
+	for i, err := range e {
+		r[i] = ValidationError{Path: JoinPath(path, err.Path), Msg: err.Msg}
+	}
+	return r
+}
+
+// JoinPath joins JSON paths. Map keys which are not valid identifiers
+// should be formatted using the KeyPath function.
+func JoinPath(path, elem string) string {
+	switch {
+	case path == "":
+		return elem
+	case elem == "":
+		return path
+	case strings.HasPrefix(elem, "["):
+		return path + elem
+	}
+	return path + "." + elem
+}
+
+// KeyPath returns a path element for a map key.
+func KeyPath(key string) string {
+	if identifierRegexp.MatchString(key) {
+		return key
+	}
+	return fmt.Sprintf("[%q]", key)
+}
+
+// IndexPath returns a path element for an array index.
+func IndexPath(idx int) string {
+	return fmt.Sprintf("[%d]", idx)
+}
+
+// checkTypes verifies that the raw JSON value matches the type. It reports
+// unknown fields and values of invalid types.
+func checkTypes(raw interface{}, typ reflect.Type, path string, topLevel bool) ValidationErrors {
+	if raw == nil {
+		return nil
+	}
+	if typ.Implements(typeSelectorType) {
+		return checkTypes(raw, reflect.Zero(typ).Interface().(TypeSelector).SelectType(raw), path, topLevel)
+	}
+	if typ.Implements(jsonUnmarshalerType) || reflect.PtrTo(typ).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	var errs ValidationErrors
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return checkTypes(raw, typ.Elem(), path, topLevel)
+	case reflect.Interface:
+		return nil
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return ValidationErrors{typeError(path, "an object", raw)}
+		}
+		fields := jsonFields(typ)
+		for _, key := range sortedKeys(obj) {
+			field, ok := findField(fields, key)
+			if !ok {
+				if topLevel && isSharedSection(key) {
+					continue
+				}
+				errs = append(errs, unknownFieldError(JoinPath(path, KeyPath(key)), key, fields))
+				continue
+			}
+			errs = append(errs, checkTypes(obj[key], typ.Field(field.index).Type, JoinPath(path, KeyPath(key)), false)...)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return ValidationErrors{typeError(path, "an object", raw)}
+		}
+		for _, key := range sortedKeys(obj) {
+			errs = append(errs, checkTypes(obj[key], typ.Elem(), JoinPath(path, KeyPath(key)), false)...)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := raw.([]interface{})
+		if !ok {
+			return ValidationErrors{typeError(path, "an array", raw)}
+		}
+		for i, e := range arr {
+			errs = append(errs, checkTypes(e, typ.Elem(), JoinPath(path, IndexPath(i)), false)...)
+		}
+	case reflect.String:
+		if _, ok := raw.(string); !ok {
+			return ValidationErrors{typeError(path, "a string", raw)}
+		}
+	case reflect.Bool:
+		if _, ok := raw.(bool); !ok {
+			return ValidationErrors{typeError(path, "a boolean", raw)}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return ValidationErrors{typeError(path, "an integer", raw)}
+		}
+		if _, err := strconv.ParseInt(n.String(), 10, typ.Bits()); err != nil {
+			return ValidationErrors{{Path: path, Msg: fmt.Sprintf("expected an integer, got %s", n)}}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return ValidationErrors{typeError(path, "a non-negative integer", raw)}
+		}
+		if _, err := strconv.ParseUint(n.String(), 10, typ.Bits()); err != nil {
+			return ValidationErrors{{Path: path, Msg: fmt.Sprintf("expected a non-negative integer, got %s", n)}}
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := raw.(json.Number); !ok {
+			return ValidationErrors{typeError(path, "a number", raw)}
+		}
+	}
+	return errs
+}
+
+// checkValues calls the Validate method on all values which implement the
+// Validator interface.
+func checkValues(v reflect.Value, path string) ValidationErrors {
+	if !v.IsValid() {
+		return nil
+	}
+	var errs ValidationErrors
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		// Validate methods are usually defined on pointers, so an
+		// addressable copy of the value is needed:
+		if !v.CanAddr() {
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			v = c
+		}
+		if val, ok := v.Addr().Interface().(Validator); ok {
+			errs = append(errs, val.Validate().Prefix(path)...)
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			errs = append(errs, checkValues(v.Elem(), path)...)
+		}
+	case reflect.Struct:
+		for _, f := range jsonFields(v.Type()) {
+			errs = append(errs, checkValues(v.Field(f.index), JoinPath(path, KeyPath(f.name)))...)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, k := range keys {
+			errs = append(errs, checkValues(v.MapIndex(k), JoinPath(path, KeyPath(k.String())))...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, checkValues(v.Index(i), JoinPath(path, IndexPath(i)))...)
+		}
+	}
+	return errs
+}
+
+type jsonField struct {
+	name  string
+	index int
+}
+
+// jsonFields returns exported struct fields with their JSON names.
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tag = strings.Split(tag, ",")[0]
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, jsonField{name: name, index: i})
+	}
+	return fields
+}
+
+// findField finds a field for the JSON key. Like the encoding/json package,
+// it prefers an exact match but accepts a case-insensitive one.
+func findField(fields []jsonField, key string) (jsonField, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+func isSharedSection(key string) bool {
+	for _, s := range SharedSections {
+		if s == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeError(path, expected string, raw interface{}) ValidationError {
+	var got string
+	switch raw.(type) {
+	case map[string]interface{}:
+		got = "an object"
+	case []interface{}:
+		got = "an array"
+	case string:
+		got = "a string"
+	case bool:
+		got = "a boolean"
+	case json.Number:
+		got = "a number"
+	default:
+		got = fmt.Sprintf("%T", raw)
+	}
+	return ValidationError{Path: path, Msg: fmt.Sprintf("expected %s, got %s", expected, got)}
+}
+
+// unknownFieldError returns an error for an unknown field. If there is
+// a field with a similar name, it is suggested in the error message.
+func unknownFieldError(path, key string, fields []jsonField) ValidationError {
+	msg := "unknown field"
+	best, bestDist := "", 3
+	for _, f := range fields {
+		if d := levenshtein(strings.ToLower(key), strings.ToLower(f.name)); d < bestDist {
+			best, bestDist = f.name, d
+		}
+	}
+	if best != "" {
+		msg = fmt.Sprintf("unknown field, did you mean %q?", best)
+	}
+	return ValidationError{Path: path, Msg: msg}
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateTestConfig struct {
+	Section validateTestSection `json:"section"`
+}
+
+type validateTestSection struct {
+	Interval int                           `json:"interval"`
+	Enabled  bool                          `json:"enabled"`
+	Spread   float64                       `json:"spread"`
+	Pairs    []string                      `json:"pairs"`
+	Items    map[string]validateTestItem   `json:"items"`
+	Any      interface{}                   `json:"any"`
+	Ptr      *validateTestItem             `json:"ptr"`
+	Nested   map[string][]validateTestItem `json:"nested"`
+}
+
+type validateTestItem struct {
+	Address string `json:"address"`
+}
+
+func (i *validateTestItem) Validate() ValidationErrors {
+	if i.Address == "" {
+		return ValidationErrors{{Path: "address", Msg: "must not be empty"}}
+	}
+	return nil
+}
+
+func TestParse_Valid(t *testing.T) {
+	var cfg validateTestConfig
+	err := Parse(&cfg, []byte(`{
+		"section": {
+			"interval": 10,
+			"enabled": true,
+			"spread": 0.5,
+			"pairs": ["BTCUSD"],
+			"items": {"a": {"address": "x"}},
+			"any": [1, "a", {}],
+			"ptr": {"address": "y"}
+		},
+		"spire": {"unknown": "shared sections are not validated"}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, cfg.Section.Interval)
+	assert.Equal(t, "y", cfg.Section.Ptr.Address)
+}
+
+func TestParse_TypeErrors(t *testing.T) {
+	var cfg validateTestConfig
+	err := Parse(&cfg, []byte(`{
+		"section": {
+			"interval": 1.5,
+			"enabled": "true",
+			"spread": "0.5",
+			"pairs": "BTCUSD",
+			"items": {"BTC/USD": {"adress": "x"}},
+			"ptr": []
+		},
+		"unknown": 1
+	}`))
+	require.Error(t, err)
+
+	var vErr ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, ValidationErrors{
+		{Path: "section.enabled", Msg: "expected a boolean, got a string"},
+		{Path: "section.interval", Msg: "expected an integer, got 1.5"},
+		{Path: `section.items["BTC/USD"].adress`, Msg: `unknown field, did you mean "address"?`},
+		{Path: "section.pairs", Msg: "expected an array, got a string"},
+		{Path: "section.ptr", Msg: "expected an object, got an array"},
+		{Path: "section.spread", Msg: "expected a number, got a string"},
+		{Path: "unknown", Msg: "unknown field"},
+	}, vErr)
+}
+
+func TestParse_ValidatorErrors(t *testing.T) {
+	var cfg validateTestConfig
+	err := Parse(&cfg, []byte(`{
+		"section": {
+			"items": {"a": {"address": ""}},
+			"ptr": {},
+			"nested": {"b": [{"address": "x"}, {}]}
+		}
+	}`))
+	require.Error(t, err)
+
+	var vErr ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, ValidationErrors{
+		{Path: "section.items.a.address", Msg: "must not be empty"},
+		{Path: "section.ptr.address", Msg: "must not be empty"},
+		{Path: "section.nested.b[1].address", Msg: "must not be empty"},
+	}, vErr)
+}
+
+// validateTestNames accepts a list of names or a map of items.
+type validateTestNames map[string]validateTestItem
+
+func (n validateTestNames) SelectType(raw interface{}) reflect.Type {
+	if _, ok := raw.([]interface{}); ok {
+		return reflect.TypeOf([]string{})
+	}
+	return reflect.TypeOf(map[string]validateTestItem{})
+}
+
+func (n *validateTestNames) UnmarshalJSON(b []byte) error {
+	var names []string
+	if err := json.Unmarshal(b, &names); err == nil {
+		*n = validateTestNames{}
+		for _, name := range names {
+			(*n)[name] = validateTestItem{Address: name}
+		}
+		return nil
+	}
+	return json.Unmarshal(b, (*map[string]validateTestItem)(n))
+}
+
+func TestParse_TypeSelector(t *testing.T) {
+	var cfg struct {
+		Names validateTestNames `json:"names"`
+	}
+	require.NoError(t, Parse(&cfg, []byte(`{"names": ["a", "b"]}`)))
+	assert.Len(t, cfg.Names, 2)
+
+	require.NoError(t, Parse(&cfg, []byte(`{"names": {"a": {"address": "x"}}}`)))
+	assert.Equal(t, "x", cfg.Names["a"].Address)
+
+	err := Parse(&cfg, []byte(`{"names": {"a": {"adress": "x"}}}`))
+	var vErr ValidationErrors
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, ValidationErrors{
+		{Path: "names.a.adress", Msg: `unknown field, did you mean "address"?`},
+	}, vErr)
+
+	err = Parse(&cfg, []byte(`{"names": [1]}`))
+	require.ErrorAs(t, err, &vErr)
+	assert.Equal(t, "names[0]", vErr[0].Path)
+}
+
+func TestJoinPath(t *testing.T) {
+	assert.Equal(t, "a", JoinPath("", "a"))
+	assert.Equal(t, "a", JoinPath("a", ""))
+	assert.Equal(t, "a.b", JoinPath("a", "b"))
+	assert.Equal(t, "a[0]", JoinPath("a", IndexPath(0)))
+	assert.Equal(t, `a["B/C"]`, JoinPath("a", KeyPath("B/C")))
+	assert.Equal(t, "a.BTCUSD", JoinPath("a", KeyPath("BTCUSD")))
+}
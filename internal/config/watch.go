@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+)
+
+// Watch polls config files at the given interval and sends a notification
+// to the returned channel whenever their content changes. Files are loaded
+// the same way as by the ParseFiles function, so changes in included files
+// are also detected, but secrets are not resolved. Notifications are not
+// queued, if the previous one was not received yet, a new one is dropped.
+// The channel is closed when the context is cancelled.
+func Watch(ctx context.Context, paths []string, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last := fingerprint(paths)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				curr := fingerprint(paths)
+				if curr == last {
+					continue
+				}
+				last = curr
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// fingerprint returns a hash of the merged config files. If files cannot
+// be loaded, the hash of the error message is returned, so a notification
+// is also sent when a file becomes invalid.
+func fingerprint(paths []string) [sha256.Size]byte {
+	var merged interface{}
+	for _, path := range paths {
+		v, err := loadFile(path, nil)
+		if err != nil {
+			return sha256.Sum256([]byte(err.Error()))
+		}
+		merged = merge(merged, v)
+	}
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return sha256.Sum256([]byte(err.Error()))
+	}
+	return sha256.Sum256(b)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json":   `{"name":"base","nested":{"include":"nested.json"}}`,
+		"nested.json":   `{"a":1}`,
+		"unrelated.txt": `foo`,
+	})
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	ch := Watch(ctx, []string{filepath.Join(dir, "config.json")}, 10*time.Millisecond)
+
+	// Changes in unrelated files should be ignored:
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("bar"), 0600))
+	select {
+	case <-ch:
+		t.Fatal("unexpected notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Changes in included files should be detected:
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nested.json"), []byte(`{"a":2}`), 0600))
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("change was not detected")
+	}
+
+	// Invalid files should also trigger a notification:
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{`), 0600))
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("change was not detected")
+	}
+
+	ctxCancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
@@ -0,0 +1,157 @@
+package marshal
+
+import (
+	"bytes"
+	encodingCSV "encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
+)
+
+var csvRecordHeader = []string{"ts", "type", "origin", "path", "base", "quote", "price", "bid", "ask", "vol24h", "error"}
+
+var csvBacktestHeader = []string{
+	"model", "reference", "base", "quote", "ticks", "errors", "errorRate",
+	"compared", "maxDeviation", "meanDeviation", "trackingError",
+}
+
+type csvItem struct {
+	writer io.Writer
+	header []string
+	rows   [][]string
+	// raw is written as is, without the CSV encoding. It is used for
+	// errors.
+	raw []byte
+}
+
+type csv struct {
+	expand bool
+	items  []csvItem
+}
+
+func newCSV(expand bool) *csv {
+	return &csv{expand: expand}
+}
+
+// Write implements the Marshaller interface.
+func (c *csv) Write(writer io.Writer, item interface{}) error {
+	var i csvItem
+	switch typedItem := item.(type) {
+	case *gofer.Price:
+		i = c.handlePrice(typedItem)
+	case *history.Record:
+		i = c.handleRecord(typedItem)
+	case *backtest.Result:
+		i = c.handleBacktestResult(typedItem)
+	case error:
+		i = csvItem{raw: []byte(fmt.Sprintf("Error: %s", typedItem.Error()))}
+	default:
+		return fmt.Errorf("unsupported data type")
+	}
+
+	i.writer = writer
+	c.items = append(c.items, i)
+	return nil
+}
+
+// Flush implements the Marshaller interface. The header is written once for
+// every writer, before the first row.
+func (c *csv) Flush() error {
+	headers := map[io.Writer]bool{}
+	for _, i := range c.items {
+		if i.raw != nil {
+			if _, err := i.writer.Write(append(i.raw, '\n')); err != nil {
+				return err
+			}
+			continue
+		}
+		buf := &bytes.Buffer{}
+		w := encodingCSV.NewWriter(buf)
+		if !headers[i.writer] {
+			headers[i.writer] = true
+			if err := w.Write(i.header); err != nil {
+				return err
+			}
+		}
+		if err := w.WriteAll(i.rows); err != nil {
+			return err
+		}
+		if _, err := i.writer.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handlePrice uses the same columns as handleRecord. If the expand option
+// is enabled, all origin prices used to calculate the price are listed
+// after it.
+func (c *csv) handlePrice(price *gofer.Price) csvItem {
+	rows := [][]string{csvPriceRow(price)}
+	if c.expand {
+		for _, o := range originPrices(price) {
+			rows = append(rows, csvPriceRow(o))
+		}
+	}
+	return csvItem{header: csvRecordHeader, rows: rows}
+}
+
+func csvPriceRow(price *gofer.Price) []string {
+	return []string{
+		price.Time.In(time.UTC).Format(time.RFC3339Nano),
+		price.Type,
+		price.Parameters["origin"],
+		"",
+		price.Pair.Base,
+		price.Pair.Quote,
+		price.Price.String(),
+		price.Bid.String(),
+		price.Ask.String(),
+		price.Volume24h.String(),
+		strings.TrimSpace(price.Error),
+	}
+}
+
+func (*csv) handleRecord(record *history.Record) csvItem {
+	return csvItem{
+		header: csvRecordHeader,
+		rows: [][]string{{
+			record.Time.In(time.UTC).Format(time.RFC3339Nano),
+			record.Type,
+			record.Origin,
+			record.Path,
+			record.Pair.Base,
+			record.Pair.Quote,
+			record.Price.String(),
+			record.Bid.String(),
+			record.Ask.String(),
+			record.Volume24h.String(),
+			record.Error,
+		}},
+	}
+}
+
+func (*csv) handleBacktestResult(result *backtest.Result) csvItem {
+	return csvItem{
+		header: csvBacktestHeader,
+		rows: [][]string{{
+			result.Model,
+			result.Reference,
+			result.Pair.Base,
+			result.Pair.Quote,
+			strconv.Itoa(result.Ticks),
+			strconv.Itoa(result.Errors),
+			strconv.FormatFloat(result.ErrorRate, 'g', -1, 64),
+			strconv.Itoa(result.Compared),
+			strconv.FormatFloat(result.MaxDeviation, 'g', -1, 64),
+			strconv.FormatFloat(result.MeanDeviation, 'g', -1, 64),
+			strconv.FormatFloat(result.TrackingError, 'g', -1, 64),
+		}},
+	}
+}
@@ -0,0 +1,98 @@
+package marshal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+func TestCSV_Records(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	e := &bytes.Buffer{}
+	m := newCSV(false)
+
+	for _, r := range testutil.Records(gofer.Pair{Base: "A", Quote: "B"}) {
+		err = m.Write(b, r)
+		assert.NoError(t, err)
+	}
+	err = m.Write(e, errors.New("something"))
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+ts,type,origin,path,base,quote,price,bid,ask,vol24h,error
+1970-01-01T00:00:10Z,origin,a,,A,B,10,9,11,100,
+1970-01-01T00:00:20Z,aggregator,,,A,B,10.5,0,0,0,something
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+	assert.Equal(t, "Error: something\n", e.String())
+}
+
+func TestCSV_Prices(t *testing.T) {
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	tests := []struct {
+		expand   bool
+		expected string
+	}{
+		{
+			expand: false,
+			expected: `
+ts,type,origin,path,base,quote,price,bid,ask,vol24h,error
+1970-01-01T00:00:10Z,aggregator,,,A,B,10,10,10,0,
+`[1:],
+		},
+		{
+			expand: true,
+			expected: `
+ts,type,origin,path,base,quote,price,bid,ask,vol24h,error
+1970-01-01T00:00:10Z,aggregator,,,A,B,10,10,10,0,
+1970-01-01T00:00:10Z,origin,a,,A,B,10,10,10,10,
+1970-01-01T00:00:20Z,origin,b,,A,B,20,20,20,20,something
+`[1:],
+		},
+	}
+	for _, tt := range tests {
+		b := &bytes.Buffer{}
+		m := newCSV(tt.expand)
+
+		err := m.Write(b, testutil.Prices(ab)[ab])
+		assert.NoError(t, err)
+		err = m.Flush()
+		assert.NoError(t, err)
+
+		assert.Equal(t, tt.expected, b.String())
+	}
+}
+
+func TestCSV_Unsupported(t *testing.T) {
+	m := newCSV(false)
+	assert.Error(t, m.Write(&bytes.Buffer{}, struct{}{}))
+}
+
+func TestCSV_BacktestResult(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newCSV(false)
+
+	err = m.Write(b, testutil.BacktestResult(gofer.Pair{Base: "A", Quote: "B"}))
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+model,reference,base,quote,ticks,errors,errorRate,compared,maxDeviation,meanDeviation,trackingError
+a,b,A,B,4,1,0.25,3,0.05,0.025,0.03
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
@@ -0,0 +1,121 @@
+package marshal
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+// diagramNode is a single node of a diagram.
+type diagramNode struct {
+	id    string
+	typ   string
+	label []string
+}
+
+// diagramEdge connects a parent node with its child.
+type diagramEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+// diagram is a flat representation of price models used by the dot and
+// mermaid marshallers. Identical sub-models, like models referenced by
+// other models, are represented by a single node.
+type diagram struct {
+	nodes []diagramNode
+	edges []diagramEdge
+	ids   map[string]string
+	seen  map[diagramEdge]bool
+}
+
+func newDiagram() *diagram {
+	return &diagram{
+		ids:  map[string]string{},
+		seen: map[diagramEdge]bool{},
+	}
+}
+
+// add adds a model with all of its sub-models to the diagram and returns
+// the ID of the model's node.
+func (d *diagram) add(model *gofer.Model) string {
+	key := diagramKey(model)
+	if id, ok := d.ids[key]; ok {
+		return id
+	}
+	id := fmt.Sprintf("n%d", len(d.nodes))
+	d.ids[key] = id
+	d.nodes = append(d.nodes, diagramNode{id: id, typ: model.Type, label: diagramLabel(model)})
+	for _, m := range model.Models {
+		edge := diagramEdge{from: id, to: d.add(m)}
+		if ttl, ok := m.Parameters["ttl"]; ok {
+			edge.label = "ttl: " + ttl + "s"
+		}
+		if !d.seen[edge] {
+			d.seen[edge] = true
+			d.edges = append(d.edges, edge)
+		}
+	}
+	return id
+}
+
+// diagramKey returns a string which uniquely identifies the model together
+// with its sub-models.
+func diagramKey(model *gofer.Model) string {
+	var keys []string
+	for k := range model.Parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	b.WriteString(model.Type)
+	b.WriteString("(")
+	b.WriteString(model.Pair.String())
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf(",%s=%s", k, model.Parameters[k]))
+	}
+	b.WriteString(")[")
+	for i, m := range model.Models {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(diagramKey(m))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func diagramLabel(model *gofer.Model) []string {
+	var label []string
+	if origin, ok := model.Parameters["origin"]; ok {
+		label = append(label, origin)
+	} else {
+		label = append(label, model.Type)
+	}
+	return append(label, model.Pair.String())
+}
+
+type diagramItem struct {
+	writer io.Writer
+	model  *gofer.Model
+	err    error
+}
+
+// diagramItems groups items by writers, preserving the order in which
+// writers were used.
+func diagramItems(items []diagramItem) ([]io.Writer, map[io.Writer][]diagramItem) {
+	var writers []io.Writer
+	grouped := map[io.Writer][]diagramItem{}
+	for _, i := range items {
+		if _, ok := grouped[i.writer]; !ok {
+			writers = append(writers, i.writer)
+		}
+		grouped[i.writer] = append(grouped[i.writer], i)
+	}
+	return writers, grouped
+}
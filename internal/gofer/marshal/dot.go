@@ -0,0 +1,87 @@
+package marshal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+var dotShapes = map[string]string{
+	"median":   "box",
+	"indirect": "diamond",
+	"origin":   "ellipse",
+}
+
+// dot renders price models as a graph in the Graphviz DOT language. All
+// models written to the same writer are rendered as a single graph.
+type dot struct {
+	items []diagramItem
+}
+
+func newDot() *dot {
+	return &dot{}
+}
+
+// Write implements the Marshaller interface.
+func (d *dot) Write(writer io.Writer, item interface{}) error {
+	switch typedItem := item.(type) {
+	case *gofer.Model:
+		d.items = append(d.items, diagramItem{writer: writer, model: typedItem})
+	case error:
+		d.items = append(d.items, diagramItem{writer: writer, err: typedItem})
+	default:
+		return fmt.Errorf("unsupported data type")
+	}
+	return nil
+}
+
+// Flush implements the Marshaller interface.
+func (d *dot) Flush() error {
+	writers, items := diagramItems(d.items)
+	for _, w := range writers {
+		buf := bytes.Buffer{}
+		dia := newDiagram()
+		for _, i := range items[w] {
+			if i.err != nil {
+				buf.WriteString(fmt.Sprintf("Error: %s\n", i.err.Error()))
+				continue
+			}
+			dia.add(i.model)
+		}
+		if len(dia.nodes) > 0 {
+			buf.WriteString("digraph {\n")
+			for _, n := range dia.nodes {
+				shape, ok := dotShapes[n.typ]
+				if !ok {
+					shape = "plaintext"
+				}
+				buf.WriteString(fmt.Sprintf("  %s [label=%s, shape=%s];\n", n.id, dotQuote(n.label...), shape))
+			}
+			for _, e := range dia.edges {
+				if e.label != "" {
+					buf.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", e.from, e.to, dotQuote(e.label)))
+				} else {
+					buf.WriteString(fmt.Sprintf("  %s -> %s;\n", e.from, e.to))
+				}
+			}
+			buf.WriteString("}\n")
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dotQuote returns a quoted DOT string, lines are separated with a new line.
+func dotQuote(lines ...string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	quoted := make([]string, len(lines))
+	for i, l := range lines {
+		quoted[i] = r.Replace(l)
+	}
+	return `"` + strings.Join(quoted, `\n`) + `"`
+}
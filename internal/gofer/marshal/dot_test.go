@@ -0,0 +1,104 @@
+package marshal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+func TestDot_Models(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newDot()
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	ns := testutil.Models(ab)
+
+	err = m.Write(b, ns[ab])
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+digraph {
+  n0 [label="median\nA/B", shape=box];
+  n1 [label="a\nA/B", shape=ellipse];
+  n2 [label="indirect\nA/B", shape=diamond];
+  n3 [label="median\nA/B", shape=box];
+  n4 [label="b\nA/B", shape=ellipse];
+  n0 -> n1 [label="ttl: 0s"];
+  n2 -> n1 [label="ttl: 0s"];
+  n0 -> n2;
+  n3 -> n1 [label="ttl: 0s"];
+  n3 -> n4 [label="ttl: 0s"];
+  n0 -> n3;
+}
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestDot_SharedModels(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newDot()
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	cd := gofer.Pair{Base: "C", Quote: "D"}
+	abModel := &gofer.Model{
+		Type:       "median",
+		Parameters: map[string]string{},
+		Pair:       ab,
+		Models: []*gofer.Model{
+			{Type: "origin", Parameters: map[string]string{"origin": "x", "ttl": "60"}, Pair: ab},
+		},
+	}
+	cdModel := &gofer.Model{
+		Type:       "median",
+		Parameters: map[string]string{},
+		Pair:       cd,
+		Models: []*gofer.Model{
+			{Type: "origin", Parameters: map[string]string{"origin": "x", "ttl": "60"}, Pair: cd},
+			// Reference to the A/B model:
+			abModel,
+		},
+	}
+
+	err = m.Write(b, abModel)
+	assert.NoError(t, err)
+	err = m.Write(b, cdModel)
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+digraph {
+  n0 [label="median\nA/B", shape=box];
+  n1 [label="x\nA/B", shape=ellipse];
+  n2 [label="median\nC/D", shape=box];
+  n3 [label="x\nC/D", shape=ellipse];
+  n0 -> n1 [label="ttl: 60s"];
+  n2 -> n3 [label="ttl: 60s"];
+  n2 -> n0;
+}
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestDot_Error(t *testing.T) {
+	b := &bytes.Buffer{}
+	m := newDot()
+
+	assert.NoError(t, m.Write(b, errors.New("something")))
+	assert.Error(t, m.Write(b, &gofer.Price{}))
+	assert.NoError(t, m.Flush())
+	assert.Equal(t, "Error: something\n", b.String())
+}
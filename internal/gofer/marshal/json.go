@@ -6,7 +6,10 @@ import (
 	"io"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
 )
 
 type jsonItem struct {
@@ -33,6 +36,10 @@ func (j *json) Write(writer io.Writer, item interface{}) error {
 		i = j.handlePrice(typedItem)
 	case *gofer.Model:
 		i = j.handleModel(typedItem)
+	case *history.Record:
+		i = j.handleRecord(typedItem)
+	case *backtest.Result:
+		i = j.handleBacktestResult(typedItem)
 	case error:
 		i = j.handleError(typedItem)
 	default:
@@ -91,6 +98,38 @@ func (*json) handleModel(node *gofer.Model) interface{} {
 	return node.Pair.String()
 }
 
+func (*json) handleRecord(record *history.Record) interface{} {
+	return jsonRecord{
+		Type:      record.Type,
+		Origin:    record.Origin,
+		Path:      record.Path,
+		Base:      record.Pair.Base,
+		Quote:     record.Pair.Quote,
+		Price:     record.Price,
+		Bid:       record.Bid,
+		Ask:       record.Ask,
+		Volume24h: record.Volume24h,
+		Timestamp: record.Time.In(time.UTC),
+		Error:     record.Error,
+	}
+}
+
+func (*json) handleBacktestResult(result *backtest.Result) interface{} {
+	return jsonBacktestResult{
+		Model:         result.Model,
+		Reference:     result.Reference,
+		Base:          result.Pair.Base,
+		Quote:         result.Pair.Quote,
+		Ticks:         result.Ticks,
+		Errors:        result.Errors,
+		ErrorRate:     result.ErrorRate,
+		Compared:      result.Compared,
+		MaxDeviation:  result.MaxDeviation,
+		MeanDeviation: result.MeanDeviation,
+		TrackingError: result.TrackingError,
+	}
+}
+
 func (*json) handleError(err error) interface{} {
 	return struct {
 		Error string `json:"error"`
@@ -101,16 +140,44 @@ type jsonPrice struct {
 	Type       string            `json:"type"`
 	Base       string            `json:"base"`
 	Quote      string            `json:"quote"`
-	Price      float64           `json:"price"`
-	Bid        float64           `json:"bid"`
-	Ask        float64           `json:"ask"`
-	Volume24h  float64           `json:"vol24h"`
+	Price      decimal.Decimal   `json:"price"`
+	Bid        decimal.Decimal   `json:"bid"`
+	Ask        decimal.Decimal   `json:"ask"`
+	Volume24h  decimal.Decimal   `json:"vol24h"`
 	Timestamp  time.Time         `json:"ts"`
 	Parameters map[string]string `json:"params,omitempty"`
 	Prices     []jsonPrice       `json:"prices,omitempty"`
 	Error      string            `json:"error,omitempty"`
 }
 
+type jsonRecord struct {
+	Type      string          `json:"type"`
+	Origin    string          `json:"origin,omitempty"`
+	Path      string          `json:"path,omitempty"`
+	Base      string          `json:"base"`
+	Quote     string          `json:"quote"`
+	Price     decimal.Decimal `json:"price"`
+	Bid       decimal.Decimal `json:"bid"`
+	Ask       decimal.Decimal `json:"ask"`
+	Volume24h decimal.Decimal `json:"vol24h"`
+	Timestamp time.Time       `json:"ts"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type jsonBacktestResult struct {
+	Model         string  `json:"model"`
+	Reference     string  `json:"reference"`
+	Base          string  `json:"base"`
+	Quote         string  `json:"quote"`
+	Ticks         int     `json:"ticks"`
+	Errors        int     `json:"errors"`
+	ErrorRate     float64 `json:"errorRate"`
+	Compared      int     `json:"compared"`
+	MaxDeviation  float64 `json:"maxDeviation"`
+	MeanDeviation float64 `json:"meanDeviation"`
+	TrackingError float64 `json:"trackingError"`
+}
+
 func jsonPriceFromGoferPrice(t *gofer.Price) jsonPrice {
 	var prices []jsonPrice
 	for _, c := range t.Prices {
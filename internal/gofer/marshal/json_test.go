@@ -178,3 +178,30 @@ func TestJSON_Prices(t *testing.T) {
 
 	assert.JSONEq(t, expected, b.String())
 }
+
+func TestNDJSON_Records(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newJSON(true)
+
+	for _, r := range testutil.Records(gofer.Pair{Base: "A", Quote: "B"}) {
+		err = m.Write(b, r)
+		assert.NoError(t, err)
+	}
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	result := bytes.Split(b.Bytes(), []byte("\n"))
+
+	assert.JSONEq(
+		t,
+		`{"type":"origin","origin":"a","base":"A","quote":"B","price":10,"bid":9,"ask":11,"vol24h":100,"ts":"1970-01-01T00:00:10Z"}`,
+		string(result[0]),
+	)
+	assert.JSONEq(
+		t,
+		`{"type":"aggregator","base":"A","quote":"B","price":10.5,"bid":0,"ask":0,"vol24h":0,"ts":"1970-01-01T00:00:20Z","error":"something"}`,
+		string(result[1]),
+	)
+}
@@ -14,8 +14,19 @@ const (
 	JSON
 	NDJSON
 	Trace
+	CSV
+	Dot
+	Mermaid
+	Table
 )
 
+// Options contains optional settings for marshallers.
+type Options struct {
+	// Expand enables listing all origin prices used to calculate a price
+	// in the table and csv formats.
+	Expand bool
+}
+
 // Marshaller is the interface which must be implemented by different
 // marshallers used to format output for the CLI.
 type Marshaller interface {
@@ -31,6 +42,11 @@ type Marshal struct {
 
 // NewMarshal returns new Marshal instance.
 func NewMarshal(format FormatType) (*Marshal, error) {
+	return NewMarshalWithOptions(format, Options{})
+}
+
+// NewMarshalWithOptions returns new Marshal instance with given options.
+func NewMarshalWithOptions(format FormatType, opts Options) (*Marshal, error) {
 	switch format {
 	case Plain:
 		return &Marshal{marshaller: newPlain()}, nil
@@ -40,6 +56,14 @@ func NewMarshal(format FormatType) (*Marshal, error) {
 		return &Marshal{marshaller: newJSON(true)}, nil
 	case Trace:
 		return &Marshal{marshaller: newTrace()}, nil
+	case CSV:
+		return &Marshal{marshaller: newCSV(opts.Expand)}, nil
+	case Dot:
+		return &Marshal{marshaller: newDot()}, nil
+	case Mermaid:
+		return &Marshal{marshaller: newMermaid()}, nil
+	case Table:
+		return &Marshal{marshaller: newTable(opts.Expand)}, nil
 	}
 
 	return nil, fmt.Errorf("unsupported format")
@@ -0,0 +1,87 @@
+package marshal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+// mermaidShapes contains opening and closing brackets for node types.
+var mermaidShapes = map[string][2]string{
+	"median":   {"[", "]"},
+	"indirect": {"{", "}"},
+	"origin":   {"([", "])"},
+}
+
+// mermaid renders price models as a Mermaid flowchart. All models written
+// to the same writer are rendered as a single flowchart.
+type mermaid struct {
+	items []diagramItem
+}
+
+func newMermaid() *mermaid {
+	return &mermaid{}
+}
+
+// Write implements the Marshaller interface.
+func (m *mermaid) Write(writer io.Writer, item interface{}) error {
+	switch typedItem := item.(type) {
+	case *gofer.Model:
+		m.items = append(m.items, diagramItem{writer: writer, model: typedItem})
+	case error:
+		m.items = append(m.items, diagramItem{writer: writer, err: typedItem})
+	default:
+		return fmt.Errorf("unsupported data type")
+	}
+	return nil
+}
+
+// Flush implements the Marshaller interface.
+func (m *mermaid) Flush() error {
+	writers, items := diagramItems(m.items)
+	for _, w := range writers {
+		buf := bytes.Buffer{}
+		dia := newDiagram()
+		for _, i := range items[w] {
+			if i.err != nil {
+				buf.WriteString(fmt.Sprintf("Error: %s\n", i.err.Error()))
+				continue
+			}
+			dia.add(i.model)
+		}
+		if len(dia.nodes) > 0 {
+			buf.WriteString("graph TD\n")
+			for _, n := range dia.nodes {
+				shape, ok := mermaidShapes[n.typ]
+				if !ok {
+					shape = [2]string{"(", ")"}
+				}
+				buf.WriteString(fmt.Sprintf("  %s%s%s%s\n", n.id, shape[0], mermaidQuote(n.label...), shape[1]))
+			}
+			for _, e := range dia.edges {
+				if e.label != "" {
+					buf.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", e.from, mermaidQuote(e.label), e.to))
+				} else {
+					buf.WriteString(fmt.Sprintf("  %s --> %s\n", e.from, e.to))
+				}
+			}
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mermaidQuote returns a quoted Mermaid string, lines are separated with
+// a line break.
+func mermaidQuote(lines ...string) string {
+	quoted := make([]string, len(lines))
+	for i, l := range lines {
+		quoted[i] = strings.ReplaceAll(l, `"`, "#quot;")
+	}
+	return `"` + strings.Join(quoted, "<br/>") + `"`
+}
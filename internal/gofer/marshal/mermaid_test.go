@@ -0,0 +1,43 @@
+package marshal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+func TestMermaid_Models(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newMermaid()
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	ns := testutil.Models(ab)
+
+	err = m.Write(b, ns[ab])
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+graph TD
+  n0["median<br/>A/B"]
+  n1(["a<br/>A/B"])
+  n2{"indirect<br/>A/B"}
+  n3["median<br/>A/B"]
+  n4(["b<br/>A/B"])
+  n0 -->|"ttl: 0s"| n1
+  n2 -->|"ttl: 0s"| n1
+  n0 --> n2
+  n3 -->|"ttl: 0s"| n1
+  n3 -->|"ttl: 0s"| n4
+  n0 --> n3
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
@@ -3,9 +3,13 @@ package marshal
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
 )
 
 type plainItem struct {
@@ -29,6 +33,10 @@ func (p *plain) Write(writer io.Writer, item interface{}) error {
 		i = p.handlePrice(typedItem)
 	case *gofer.Model:
 		i = p.handleModel(typedItem)
+	case *history.Record:
+		i = p.handleRecord(typedItem)
+	case *backtest.Result:
+		i = p.handleBacktestResult(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
@@ -59,9 +67,41 @@ func (*plain) handlePrice(price *gofer.Price) []byte {
 	if price.Error != "" {
 		return []byte(fmt.Sprintf("%s - %s", price.Pair, strings.TrimSpace(price.Error)))
 	}
-	return []byte(fmt.Sprintf("%s %f", price.Pair, price.Price))
+	return []byte(fmt.Sprintf("%s %s", price.Pair, price.Price))
 }
 
 func (*plain) handleModel(node *gofer.Model) []byte {
 	return []byte(node.Pair.String())
 }
+
+func (*plain) handleRecord(record *history.Record) []byte {
+	source := record.Type
+	if record.Origin != "" {
+		source = record.Origin
+	}
+	if record.Path != "" {
+		source = record.Type + ":" + record.Path
+	}
+	ts := record.Time.In(time.UTC).Format(time.RFC3339Nano)
+	if record.Error != "" {
+		return []byte(fmt.Sprintf("%s %s %s - %s", ts, source, record.Pair, strings.TrimSpace(record.Error)))
+	}
+	return []byte(fmt.Sprintf("%s %s %s %s", ts, source, record.Pair, record.Price))
+}
+
+func (*plain) handleBacktestResult(result *backtest.Result) []byte {
+	return []byte(fmt.Sprintf(
+		"%s %s errors:%d/%d maxDeviation:%s trackingError:%s",
+		result.Model,
+		result.Pair,
+		result.Errors,
+		result.Ticks,
+		formatPercent(result.MaxDeviation),
+		formatPercent(result.TrackingError),
+	))
+}
+
+// formatPercent formats a ratio as a percentage.
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v*100, 'f', 4, 64) + "%"
+}
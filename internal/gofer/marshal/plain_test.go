@@ -57,9 +57,44 @@ func TestPlain_Prices(t *testing.T) {
 	assert.NoError(t, err)
 
 	expected := `
-A/B 10.000000
+A/B 10
 C/D - something
 `[1:]
 
 	assert.Equal(t, expected, b.String())
 }
+
+func TestPlain_Records(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newPlain()
+
+	for _, r := range testutil.Records(gofer.Pair{Base: "A", Quote: "B"}) {
+		err = m.Write(b, r)
+		assert.NoError(t, err)
+	}
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+1970-01-01T00:00:10Z a A/B 10
+1970-01-01T00:00:20Z aggregator A/B - something
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestPlain_BacktestResult(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newPlain()
+
+	err = m.Write(b, testutil.BacktestResult(gofer.Pair{Base: "A", Quote: "B"}))
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a A/B errors:1/4 maxDeviation:5.0000% trackingError:3.0000%\n", b.String())
+}
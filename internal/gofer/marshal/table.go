@@ -0,0 +1,228 @@
+package marshal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+// tableMaxFractionDigits is the maximum number of fractional digits of
+// numbers shown in the table.
+const tableMaxFractionDigits = 18
+
+var (
+	tablePriceHeader = []string{"PAIR", "PRICE", "BID", "ASK", "VOLUME", "AGE", "SOURCES", "ERROR"}
+	tableModelHeader = []string{"PAIR", "METHOD", "SOURCES"}
+)
+
+type tableItem struct {
+	writer io.Writer
+	header []string
+	rows   [][]string
+	// raw is written as is, outside the table. It is used for errors.
+	raw []byte
+}
+
+// table renders prices and models as a column-aligned table. All items
+// written to the same writer are rendered as a single table, errors are
+// written below the table.
+type table struct {
+	expand bool
+	now    func() time.Time
+	items  []tableItem
+}
+
+func newTable(expand bool) *table {
+	return &table{expand: expand, now: time.Now}
+}
+
+// Write implements the Marshaller interface.
+func (t *table) Write(writer io.Writer, item interface{}) error {
+	var i tableItem
+	switch typedItem := item.(type) {
+	case *gofer.Price:
+		i = t.handlePrice(typedItem)
+	case *gofer.Model:
+		i = t.handleModel(typedItem)
+	case error:
+		i = tableItem{raw: []byte(fmt.Sprintf("Error: %s\n", typedItem.Error()))}
+	default:
+		return fmt.Errorf("unsupported data type")
+	}
+
+	i.writer = writer
+	t.items = append(t.items, i)
+	return nil
+}
+
+// Flush implements the Marshaller interface.
+func (t *table) Flush() error {
+	type tableData struct {
+		header []string
+		rows   [][]string
+	}
+	var writers []io.Writer
+	seen := map[io.Writer]bool{}
+	tables := map[io.Writer]*tableData{}
+	raws := map[io.Writer][]byte{}
+	for _, i := range t.items {
+		if !seen[i.writer] {
+			seen[i.writer] = true
+			writers = append(writers, i.writer)
+		}
+		if i.raw != nil {
+			raws[i.writer] = append(raws[i.writer], i.raw...)
+			continue
+		}
+		if tables[i.writer] == nil {
+			tables[i.writer] = &tableData{header: i.header}
+		}
+		tables[i.writer].rows = append(tables[i.writer].rows, i.rows...)
+	}
+	for _, w := range writers {
+		buf := &bytes.Buffer{}
+		if tbl := tables[w]; tbl != nil {
+			tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, strings.Join(tbl.header, "\t"))
+			for _, r := range tbl.rows {
+				_, _ = fmt.Fprintln(tw, strings.Join(r, "\t"))
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+		}
+		buf.Write(raws[w])
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *table) handlePrice(price *gofer.Price) tableItem {
+	origins := originPrices(price)
+	valid := 0
+	for _, o := range origins {
+		if o.Error == "" {
+			valid++
+		}
+	}
+	rows := [][]string{{
+		price.Pair.String(),
+		tableDecimal(price.Price),
+		tableDecimal(price.Bid),
+		tableDecimal(price.Ask),
+		tableDecimal(price.Volume24h),
+		t.age(price.Time),
+		fmt.Sprintf("%d/%d", valid, len(origins)),
+		tableError(price.Error),
+	}}
+	if t.expand {
+		for i, o := range origins {
+			prefix := "├─"
+			if i == len(origins)-1 {
+				prefix = "└─"
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf(" %s %s %s", prefix, o.Parameters["origin"], o.Pair),
+				tableDecimal(o.Price),
+				tableDecimal(o.Bid),
+				tableDecimal(o.Ask),
+				tableDecimal(o.Volume24h),
+				t.age(o.Time),
+				"",
+				tableError(o.Error),
+			})
+		}
+	}
+	return tableItem{header: tablePriceHeader, rows: rows}
+}
+
+func (*table) handleModel(model *gofer.Model) tableItem {
+	return tableItem{
+		header: tableModelHeader,
+		rows: [][]string{{
+			model.Pair.String(),
+			model.Type,
+			strconv.Itoa(len(originModels(model))),
+		}},
+	}
+}
+
+func (t *table) age(ts time.Time) string {
+	if ts.IsZero() {
+		return "-"
+	}
+	return t.now().Sub(ts).Round(time.Second).String()
+}
+
+// originPrices returns all unique origin prices used to calculate the price.
+func originPrices(price *gofer.Price) []*gofer.Price {
+	var prices []*gofer.Price
+	seen := map[string]bool{}
+	var walk func(p *gofer.Price)
+	walk = func(p *gofer.Price) {
+		for _, c := range p.Prices {
+			if c.Type == "origin" {
+				k := c.Parameters["origin"] + " " + c.Pair.String()
+				if !seen[k] {
+					seen[k] = true
+					prices = append(prices, c)
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(price)
+	return prices
+}
+
+// originModels returns all unique origin models used by the model.
+func originModels(model *gofer.Model) []*gofer.Model {
+	var models []*gofer.Model
+	seen := map[string]bool{}
+	var walk func(m *gofer.Model)
+	walk = func(m *gofer.Model) {
+		for _, c := range m.Models {
+			if c.Type == "origin" {
+				k := c.Parameters["origin"] + " " + c.Pair.String()
+				if !seen[k] {
+					seen[k] = true
+					models = append(models, c)
+				}
+			}
+			walk(c)
+		}
+	}
+	walk(model)
+	return models
+}
+
+// tableDecimal formats a number for the table. Numbers are rounded to
+// tableMaxFractionDigits fractional digits.
+func tableDecimal(d decimal.Decimal) string {
+	s := d.StringFixed(tableMaxFractionDigits)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// tableError returns the first line of the error message.
+func tableError(err string) string {
+	err = strings.TrimSpace(err)
+	if i := strings.IndexByte(err, '\n'); i >= 0 {
+		err = err[:i] + " (...)"
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package marshal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+func TestTable_Prices(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	e := &bytes.Buffer{}
+	m := newTable(false)
+	m.now = func() time.Time { return time.Unix(70, 0) }
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	cd := gofer.Pair{Base: "C", Quote: "D"}
+	ns := testutil.Prices(ab, cd)
+	ns[cd].Price = decimal.NewFromInt(1).Div(decimal.NewFromInt(3))
+	ns[cd].Error = "something\nmore details"
+
+	err = m.Write(b, ns[ab])
+	assert.NoError(t, err)
+	err = m.Write(b, ns[cd])
+	assert.NoError(t, err)
+	err = m.Write(e, errors.New("failed"))
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+PAIR  PRICE                 BID  ASK  VOLUME  AGE   SOURCES  ERROR
+A/B   10                    10   10   0       1m0s  1/2      
+C/D   0.333333333333333333  10   10   0       1m0s  1/2      something (...)
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+	assert.Equal(t, "Error: failed\n", e.String())
+}
+
+func TestTable_PricesExpand(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newTable(true)
+	m.now = func() time.Time { return time.Unix(70, 0) }
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	ns := testutil.Prices(ab)
+
+	err = m.Write(b, ns[ab])
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+PAIR       PRICE  BID  ASK  VOLUME  AGE   SOURCES  ERROR
+A/B        10     10   10   0       1m0s  1/2      
+ ├─ a A/B  10     10   10   10      1m0s           
+ └─ b A/B  20     20   20   20      50s            something
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
+
+func TestTable_Models(t *testing.T) {
+	var err error
+	b := &bytes.Buffer{}
+	m := newTable(false)
+
+	ab := gofer.Pair{Base: "A", Quote: "B"}
+	ns := testutil.Models(ab)
+
+	err = m.Write(b, ns[ab])
+	assert.NoError(t, err)
+
+	err = m.Flush()
+	assert.NoError(t, err)
+
+	expected := `
+PAIR  METHOD  SOURCES
+A/B   median  2
+`[1:]
+
+	assert.Equal(t, expected, b.String())
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
 )
 
 func Gofer(ps ...gofer.Pair) gofer.Gofer {
@@ -31,10 +34,10 @@ func Gofer(ps ...gofer.Pair) gofer.Gofer {
 		_ = on1.Ingest(nodes.OriginPrice{
 			PairPrice: nodes.PairPrice{
 				Pair:      p,
-				Price:     10,
-				Bid:       10,
-				Ask:       10,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(10),
+				Ask:       decimal.NewFromInt(10),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			Origin: "a",
@@ -44,10 +47,10 @@ func Gofer(ps ...gofer.Pair) gofer.Gofer {
 		_ = on2.Ingest(nodes.OriginPrice{
 			PairPrice: nodes.PairPrice{
 				Pair:      p,
-				Price:     20,
-				Bid:       20,
-				Ask:       20,
-				Volume24h: 20,
+				Price:     decimal.NewFromInt(20),
+				Bid:       decimal.NewFromInt(20),
+				Ask:       decimal.NewFromInt(20),
+				Volume24h: decimal.NewFromInt(20),
 				Time:      time.Unix(20, 0),
 			},
 			Origin: "b",
@@ -77,3 +80,40 @@ func Prices(ps ...gofer.Pair) map[gofer.Pair]*gofer.Price {
 	}
 	return ts
 }
+
+func Records(p gofer.Pair) []*history.Record {
+	return []*history.Record{
+		{
+			Type:      history.OriginType,
+			Origin:    "a",
+			Pair:      p,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(9),
+			Ask:       decimal.NewFromInt(11),
+			Volume24h: decimal.NewFromInt(100),
+			Time:      time.Unix(10, 0),
+		},
+		{
+			Type:  history.AggregatorType,
+			Pair:  p,
+			Price: decimal.RequireFromString("10.5"),
+			Time:  time.Unix(20, 0),
+			Error: "something",
+		},
+	}
+}
+
+func BacktestResult(p gofer.Pair) *backtest.Result {
+	return &backtest.Result{
+		Model:         "a",
+		Reference:     "b",
+		Pair:          p,
+		Ticks:         4,
+		Errors:        1,
+		ErrorRate:     0.25,
+		Compared:      3,
+		MaxDeviation:  0.05,
+		MeanDeviation: 0.025,
+		TrackingError: 0.03,
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/backtest"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
 )
 
 type traceItem struct {
@@ -33,6 +35,10 @@ func (t *trace) Write(writer io.Writer, item interface{}) error {
 		i = t.handlePrice(typedItem)
 	case *gofer.Model:
 		i = t.handleModel(typedItem)
+	case *history.Record:
+		i = t.handleRecord(typedItem)
+	case *backtest.Result:
+		i = t.handleBacktestResult(typedItem)
 	case error:
 		i = []byte(fmt.Sprintf("Error: %s", typedItem.Error()))
 	default:
@@ -118,6 +124,53 @@ func (t *trace) handleModel(node *gofer.Model) []byte {
 	return buf.Bytes()
 }
 
+func (*trace) handleRecord(record *history.Record) []byte {
+	var rErr error
+	if record.Error != "" {
+		rErr = errors.New(record.Error)
+	}
+	params := []param{
+		{key: "pair", value: record.Pair.String()},
+		{key: "price", value: record.Price},
+		{key: "bid", value: record.Bid},
+		{key: "ask", value: record.Ask},
+		{key: "vol24h", value: record.Volume24h},
+		{key: "timestamp", value: record.Time.In(time.UTC).Format(time.RFC3339Nano)},
+	}
+	if record.Origin != "" {
+		params = append(params, param{key: "origin", value: record.Origin})
+	}
+	if record.Path != "" {
+		params = append(params, param{key: "path", value: record.Path})
+	}
+	buf := bytes.Buffer{}
+	buf.Write(renderNode(record.Type, params, rErr))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func (*trace) handleBacktestResult(result *backtest.Result) []byte {
+	buf := bytes.Buffer{}
+	buf.Write(renderNode(
+		"backtest",
+		[]param{
+			{key: "model", value: result.Model},
+			{key: "reference", value: result.Reference},
+			{key: "pair", value: result.Pair.String()},
+			{key: "ticks", value: result.Ticks},
+			{key: "errors", value: result.Errors},
+			{key: "errorRate", value: formatPercent(result.ErrorRate)},
+			{key: "compared", value: result.Compared},
+			{key: "maxDeviation", value: formatPercent(result.MaxDeviation)},
+			{key: "meanDeviation", value: formatPercent(result.MeanDeviation)},
+			{key: "trackingError", value: formatPercent(result.TrackingError)},
+		},
+		nil,
+	))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
 // param is used to work with lists of sorted key/value pairs.
 type param struct {
 	key   string
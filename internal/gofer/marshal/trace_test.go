@@ -29,12 +29,12 @@ func TestTrace_Graph(t *testing.T) {
 	expected := `
 Graph for A/B:
 ───median(pair:A/B)
-   ├──origin(origin:a, pair:A/B)
+   ├──origin(origin:a, pair:A/B, ttl:0)
    ├──indirect(pair:A/B)
-   │  └──origin(origin:a, pair:A/B)
+   │  └──origin(origin:a, pair:A/B, ttl:0)
    └──median(pair:A/B)
-      ├──origin(origin:a, pair:A/B)
-      └──origin(origin:b, pair:A/B)
+      ├──origin(origin:a, pair:A/B, ttl:0)
+      └──origin(origin:b, pair:A/B, ttl:0)
 `[1:]
 
 	assert.Equal(t, expected, b.String())
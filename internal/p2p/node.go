@@ -59,6 +59,7 @@ type Node struct {
 	messageHandlerSet     *sets.MessageHandlerSet
 	subs                  map[string]*Subscription
 	tsLog                 tsLogger
+	msgRateLimiter        *rateLimiter
 	disablePubSub         bool
 	closed                bool
 
@@ -75,6 +75,20 @@ func (p *rateLimiter) allow(id peer.ID, msgSize int) bool {
 	return prl.limiter.AllowN(prl.lastMsg, msgSize)
 }
 
+// setLimit changes the rate limit for all peers, including the ones
+// already seen.
+func (p *rateLimiter) setLimit(bytesPerSecond float64, burstSize int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bytesPerSecond = bytesPerSecond
+	p.burstSize = burstSize
+	p.gcTTL = time.Second * time.Duration(float64(burstSize)/bytesPerSecond)
+	for _, pl := range p.peerLimiters {
+		pl.limiter.SetLimit(rate.Limit(bytesPerSecond))
+		pl.limiter.SetBurst(burstSize)
+	}
+}
+
 // gc removes inactive peers.
 func (p *rateLimiter) gc() {
 	p.mu.Lock()
@@ -101,6 +115,7 @@ func RateLimiter(cfg RateLimiterConfig) Options {
 		relayRL := newRateLimiter(cfg.RelayBytesPerSecond, cfg.RelayBurstSize)
 		// Rate limiter for message authors:
 		msgRL := newRateLimiter(cfg.BytesPerSecond, cfg.BurstSize)
+		n.msgRateLimiter = msgRL
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
 			if n.Host().ID() == id {
 				return pubsub.ValidationAccept
@@ -145,3 +160,13 @@ func RateLimiter(cfg RateLimiterConfig) Options {
 		return nil
 	}
 }
+
+// SetMessageRateLimit changes the BytesPerSecond and BurstSize values of
+// the RateLimiter option while the node is running. It does nothing if
+// the RateLimiter option is not used.
+func (n *Node) SetMessageRateLimit(bytesPerSecond float64, burstSize int) {
+	if n.msgRateLimiter == nil {
+		return
+	}
+	n.msgRateLimiter.setLimit(bytesPerSecond, burstSize)
+}
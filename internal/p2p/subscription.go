@@ -74,6 +74,12 @@ func (s *Subscription) Publish(message transport.Message) error {
 	return s.topic.Publish(s.ctx, b)
 }
 
+// SetScoreParams changes peer score parameters for the topic. The PeerScoring
+// option must be used for peer scoring to be enabled.
+func (s *Subscription) SetScoreParams(sp *pubsub.TopicScoreParams) error {
+	return s.topic.SetScoreParams(sp)
+}
+
 func (s *Subscription) Next() chan transport.ReceivedMessage {
 	return s.msgCh
 }
@@ -3,8 +3,10 @@ package memory
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
@@ -15,7 +17,16 @@ import (
 
 const LoggerTag = "DATASTORE"
 
+// DefaultFeedsRefreshInterval is the default interval at which feeds of
+// pairs which use a FeedsSource are refreshed.
+const DefaultFeedsRefreshInterval = 10 * time.Minute
+
+// DefaultFeedsRefreshTimeout is the default timeout for a single refresh
+// of feeds.
+const DefaultFeedsRefreshTimeout = 30 * time.Second
+
 var errInvalidSignature = errors.New("received price has an invalid signature")
+var errInvalidStarkSignature = errors.New("received price has an invalid StarkWare signature")
 var errInvalidPrice = errors.New("received price is invalid")
 var errUnknownPair = errors.New("received pair is not configured")
 var errUnknownFeeder = errors.New("feeder is not allowed to send prices")
@@ -27,22 +38,47 @@ type Datastore struct {
 	doneCh chan struct{}
 
 	signer     ethereum.Signer
+	starkName  string
 	transport  transport.Transport
 	pairs      map[string]*Pair
 	priceStore *PriceStore
 	log        log.Logger
+
+	// feedsMu guards feeds. It is separate from mu, because mu is held by
+	// the collector loop.
+	feedsMu         sync.RWMutex
+	feeds           map[string][]ethereum.Address
+	refreshInterval time.Duration
+	refreshTimeout  time.Duration
+	feedsUpdated    func(feeds []ethereum.Address) error
 }
 
 type Config struct {
 	// Signer is an instance of the ethereum.Signer which will be used to
 	// verify price signatures.
 	Signer ethereum.Signer
+	// StarkOracleName is the oracle name used to verify StarkWare signatures.
+	// If empty, StarkWare signatures are not verified.
+	StarkOracleName string
 	// Transport is a implementation of transport used to fetch prices from
 	// feeders.
 	Transport transport.Transport
 	// Pairs is the list supported pairs by the datastore with their
 	// configuration.
 	Pairs map[string]*Pair
+	// FeedsRefreshInterval describes how often feeds of pairs which use
+	// a FeedsSource are refreshed. If zero, the DefaultFeedsRefreshInterval
+	// is used.
+	FeedsRefreshInterval time.Duration
+	// FeedsRefreshTimeout limits the time of a single refresh of feeds,
+	// including the one done on start. If zero,
+	// the DefaultFeedsRefreshTimeout is used.
+	FeedsRefreshTimeout time.Duration
+	// FeedsUpdated, if not nil, is called after feeds are refreshed with
+	// the list of feeds allowed to send prices for any pair. It may be used
+	// to keep other feed lists, e.g. the one used by the transport to
+	// validate messages, in sync with the datastore.
+	FeedsUpdated func(feeds []ethereum.Address) error
 	// Logger is a current logger interface used by the Datastore.
 	// The Logger is required to monitor asynchronous processes.
 	Logger log.Logger
@@ -50,22 +86,49 @@ type Config struct {
 
 type Pair struct {
 	// Feeds is the list of Ethereum addresses from which prices will be
-	// accepted.
+	// accepted. If FeedsSource is set, the list is used only until feeds are
+	// fetched from the source for the first time.
 	Feeds []ethereum.Address
+	// FeedsSource provides the list of feeds allowed to send prices, e.g.
+	// feeds lifted in the Oracle contract. If nil, the Feeds list is used.
+	FeedsSource FeedsSource
+}
+
+// FeedsSource provides the list of allowed feeds. It is implemented by
+// the oracle.Median.
+type FeedsSource interface {
+	Feeds(ctx context.Context) ([]ethereum.Address, error)
 }
 
 func NewDatastore(ctx context.Context, cfg Config) (*Datastore, error) {
 	if ctx == nil {
 		return nil, errors.New("context must not be nil")
 	}
+	refreshInterval := cfg.FeedsRefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultFeedsRefreshInterval
+	}
+	refreshTimeout := cfg.FeedsRefreshTimeout
+	if refreshTimeout <= 0 {
+		refreshTimeout = DefaultFeedsRefreshTimeout
+	}
+	feeds := make(map[string][]ethereum.Address)
+	for name, pair := range cfg.Pairs {
+		feeds[name] = pair.Feeds
+	}
 	return &Datastore{
-		ctx:        ctx,
-		doneCh:     make(chan struct{}),
-		signer:     cfg.Signer,
-		transport:  cfg.Transport,
-		pairs:      cfg.Pairs,
-		priceStore: NewPriceStore(),
-		log:        cfg.Logger.WithField("tag", LoggerTag),
+		ctx:             ctx,
+		doneCh:          make(chan struct{}),
+		signer:          cfg.Signer,
+		starkName:       cfg.StarkOracleName,
+		transport:       cfg.Transport,
+		pairs:           cfg.Pairs,
+		priceStore:      NewPriceStore(),
+		log:             cfg.Logger.WithField("tag", LoggerTag),
+		feeds:           feeds,
+		refreshInterval: refreshInterval,
+		refreshTimeout:  refreshTimeout,
+		feedsUpdated:    cfg.FeedsUpdated,
 	}, nil
 }
 
@@ -74,6 +137,7 @@ func (c *Datastore) Start() error {
 	c.log.Info("Starting")
 
 	go c.contextCancelHandler()
+	c.feedsRefreshLoop()
 	return c.collectorLoop()
 }
 
@@ -87,6 +151,54 @@ func (c *Datastore) Prices() datastore.PriceStore {
 	return c.priceStore
 }
 
+// RefreshFeeds fetches the lists of allowed feeds for pairs which use
+// a FeedsSource. Prices already collected from feeds which are no longer
+// allowed are removed. If feeds for a pair could not be fetched, the
+// previous list is kept and the first error is returned after all pairs
+// are refreshed.
+func (c *Datastore) RefreshFeeds(ctx context.Context) error {
+	var firstErr error
+	for name, pair := range c.pairs {
+		if pair.FeedsSource == nil {
+			continue
+		}
+		feeds, err := pair.FeedsSource.Feeds(ctx)
+		if err != nil {
+			c.log.
+				WithError(err).
+				WithField("assetPair", name).
+				Warn("Unable to refresh feeds")
+			if firstErr == nil {
+				firstErr = fmt.Errorf("unable to refresh feeds for %s pair: %w", name, err)
+			}
+			continue
+		}
+		c.feedsMu.Lock()
+		c.feeds[name] = feeds
+		c.feedsMu.Unlock()
+		for fp := range c.priceStore.All() {
+			if fp.AssetPair == name && !c.isFeedAllowed(name, fp.Feeder) {
+				c.priceStore.remove(fp)
+			}
+		}
+		c.log.
+			WithField("assetPair", name).
+			WithField("feeds", len(feeds)).
+			Debug("Feeds refreshed")
+	}
+	if c.feedsUpdated != nil {
+		if err := c.feedsUpdated(c.allFeeds()); err != nil {
+			c.log.
+				WithError(err).
+				Warn("Unable to update feeds")
+			if firstErr == nil {
+				firstErr = fmt.Errorf("unable to update feeds: %w", err)
+			}
+		}
+	}
+	return firstErr
+}
+
 // collectPrice adds a price from a feeder which may be used to update
 // Oracle contract. The price will be added only if a feeder is
 // allowed to send prices.
@@ -104,6 +216,11 @@ func (c *Datastore) collectPrice(msg *messages.Price) error {
 	if msg.Price.Val.Cmp(big.NewInt(0)) <= 0 {
 		return errInvalidPrice
 	}
+	if c.starkName != "" && msg.Price.HasStarkSignature() {
+		if err := msg.Price.StarkVerify(c.starkName); err != nil {
+			return errInvalidStarkSignature
+		}
+	}
 
 	c.priceStore.Add(*from, msg)
 
@@ -155,8 +272,68 @@ func (c *Datastore) collectorLoop() error {
 	return nil
 }
 
+// feedsRefreshLoop refreshes feeds immediately and then periodically until
+// the context is cancelled. It does nothing if no pair uses a FeedsSource.
+// If the first refresh fails, the configured feeds are used until the next
+// successful one.
+func (c *Datastore) feedsRefreshLoop() {
+	refresh := false
+	for _, pair := range c.pairs {
+		if pair.FeedsSource != nil {
+			refresh = true
+			break
+		}
+	}
+	if !refresh {
+		return
+	}
+	if err := c.refreshFeeds(); err != nil {
+		c.log.
+			WithError(err).
+			Warn("Unable to fetch feeds, configured feeds will be used until the next refresh")
+	}
+	go func() {
+		ticker := time.NewTicker(c.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				_ = c.refreshFeeds()
+			}
+		}
+	}()
+}
+
+// refreshFeeds calls RefreshFeeds with the refresh timeout.
+func (c *Datastore) refreshFeeds() error {
+	ctx, ctxCancel := context.WithTimeout(c.ctx, c.refreshTimeout)
+	defer ctxCancel()
+	return c.RefreshFeeds(ctx)
+}
+
+// allFeeds returns the list of feeds allowed to send prices for any pair.
+func (c *Datastore) allFeeds() []ethereum.Address {
+	c.feedsMu.RLock()
+	defer c.feedsMu.RUnlock()
+	var feeds []ethereum.Address
+	seen := make(map[ethereum.Address]bool)
+	for _, addrs := range c.feeds {
+		for _, addr := range addrs {
+			if !seen[addr] {
+				seen[addr] = true
+				feeds = append(feeds, addr)
+			}
+		}
+	}
+	return feeds
+}
+
 func (c *Datastore) isFeedAllowed(assetPair string, address ethereum.Address) bool {
-	for _, a := range c.pairs[assetPair].Feeds {
+	c.feedsMu.RLock()
+	defer c.feedsMu.RUnlock()
+	for _, a := range c.feeds[assetPair] {
 		if a == address {
 			return true
 		}
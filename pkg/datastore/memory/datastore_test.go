@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"math/big"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
 	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
 	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/local"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
@@ -72,3 +75,146 @@ func toOraclePrices(ps []*messages.Price) []*oracle.Price {
 	}
 	return r
 }
+
+func TestDatastore_collectPrice_Stark(t *testing.T) {
+	sig := &mocks.Signer{}
+	ds, err := NewDatastore(context.Background(), Config{
+		Signer:          sig,
+		StarkOracleName: "Test",
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1}},
+		},
+		Logger: null.New(),
+	})
+	require.NoError(t, err)
+	sig.On("Recover", mock.Anything, mock.Anything).Return(&testutil.Address1, nil)
+
+	ss, err := stark.NewSigner(big.NewInt(42))
+	require.NoError(t, err)
+
+	// Valid StarkWare signature:
+	valid := &oracle.Price{Wat: "AAABBB", Age: time.Unix(100, 0)}
+	valid.SetFloat64Price(10)
+	require.NoError(t, valid.StarkSign(ss, "Test"))
+	assert.NoError(t, ds.collectPrice(&messages.Price{Price: valid}))
+
+	// Price without StarkWare signature:
+	unsigned := &oracle.Price{Wat: "AAABBB", Age: time.Unix(100, 0)}
+	unsigned.SetFloat64Price(10)
+	assert.NoError(t, ds.collectPrice(&messages.Price{Price: unsigned}))
+
+	// Invalid StarkWare signature:
+	assert.ErrorIs(t, ds.collectPrice(testutil.PriceAAABBB1), errInvalidStarkSignature)
+}
+
+type testFeedsSource struct {
+	feeds []ethereum.Address
+	err   error
+	// block makes Feeds wait until the context is cancelled.
+	block bool
+}
+
+func (s *testFeedsSource) Feeds(ctx context.Context) ([]ethereum.Address, error) {
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return s.feeds, s.err
+}
+
+func TestDatastore_RefreshFeeds(t *testing.T) {
+	sig := &mocks.Signer{}
+	src := &testFeedsSource{feeds: []ethereum.Address{testutil.Address1}}
+	ds, err := NewDatastore(context.Background(), Config{
+		Signer: sig,
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1, testutil.Address2}, FeedsSource: src},
+			"XXXYYY": {Feeds: []ethereum.Address{testutil.Address1, testutil.Address2}},
+		},
+		Logger: null.New(),
+	})
+	require.NoError(t, err)
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+	sig.On("Recover", testutil.PriceAAABBB2.Price.Signature(), mock.Anything).Return(&testutil.Address2, nil)
+	sig.On("Recover", testutil.PriceXXXYYY2.Price.Signature(), mock.Anything).Return(&testutil.Address2, nil)
+
+	// Before feeds are fetched, the static list is used:
+	require.NoError(t, ds.collectPrice(testutil.PriceAAABBB1))
+	require.NoError(t, ds.collectPrice(testutil.PriceAAABBB2))
+	require.NoError(t, ds.collectPrice(testutil.PriceXXXYYY2))
+
+	// Prices from dropped feeds are removed and no longer accepted, other
+	// pairs are not affected:
+	require.NoError(t, ds.RefreshFeeds(context.Background()))
+	assert.Equal(t, []*messages.Price{testutil.PriceAAABBB1}, ds.Prices().AssetPair("AAABBB"))
+	assert.Equal(t, []*messages.Price{testutil.PriceXXXYYY2}, ds.Prices().AssetPair("XXXYYY"))
+	assert.ErrorIs(t, ds.collectPrice(testutil.PriceAAABBB2), errUnknownFeeder)
+
+	// Lifted feeds are accepted:
+	src.feeds = []ethereum.Address{testutil.Address1, testutil.Address2}
+	require.NoError(t, ds.RefreshFeeds(context.Background()))
+	assert.NoError(t, ds.collectPrice(testutil.PriceAAABBB2))
+
+	// If feeds could not be fetched, the previous list is kept:
+	src.err = errors.New("err")
+	assert.Error(t, ds.RefreshFeeds(context.Background()))
+	assert.NoError(t, ds.collectPrice(testutil.PriceAAABBB2))
+}
+
+func TestDatastore_RefreshFeeds_FeedsUpdated(t *testing.T) {
+	var updated []ethereum.Address
+	src := &testFeedsSource{feeds: []ethereum.Address{testutil.Address2}}
+	ds, err := NewDatastore(context.Background(), Config{
+		Signer: &mocks.Signer{},
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1}, FeedsSource: src},
+			"XXXYYY": {Feeds: []ethereum.Address{testutil.Address1}},
+		},
+		FeedsUpdated: func(feeds []ethereum.Address) error {
+			updated = feeds
+			return nil
+		},
+		Logger: null.New(),
+	})
+	require.NoError(t, err)
+
+	// The callback receives feeds of all pairs:
+	require.NoError(t, ds.RefreshFeeds(context.Background()))
+	assert.ElementsMatch(t, []ethereum.Address{testutil.Address1, testutil.Address2}, updated)
+
+	// Feeds dropped from all pairs are removed from the list:
+	src.feeds = []ethereum.Address{testutil.Address1}
+	require.NoError(t, ds.RefreshFeeds(context.Background()))
+	assert.Equal(t, []ethereum.Address{testutil.Address1}, updated)
+}
+
+func TestDatastore_Start_FeedsRefreshTimeout(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	sig := &mocks.Signer{}
+	tra := local.New(ctx, 0, map[string]transport.Message{messages.PriceMessageName: (*messages.Price)(nil)})
+	ds, err := NewDatastore(ctx, Config{
+		Signer:    sig,
+		Transport: tra,
+		Pairs: map[string]*Pair{
+			"AAABBB": {Feeds: []ethereum.Address{testutil.Address1}, FeedsSource: &testFeedsSource{block: true}},
+		},
+		FeedsRefreshTimeout: 10 * time.Millisecond,
+		Logger:              null.New(),
+	})
+	require.NoError(t, err)
+	sig.On("Recover", testutil.PriceAAABBB1.Price.Signature(), mock.Anything).Return(&testutil.Address1, nil)
+
+	// A source which does not respond must not block the start and
+	// the configured feeds are used instead:
+	done := make(chan error)
+	go func() { done <- ds.Start() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("start blocked by the feeds refresh")
+	}
+	assert.NoError(t, ds.collectPrice(testutil.PriceAAABBB1))
+}
@@ -39,6 +39,14 @@ func (p *PriceStore) Add(from ethereum.Address, msg *messages.Price) {
 	p.prices[fp] = msg
 }
 
+// remove removes the price sent by a feeder for an asset pair.
+func (p *PriceStore) remove(fp datastore.FeederPrice) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.prices, fp)
+}
+
 // All implements the datastore.PriceStore interface.
 func (p *PriceStore) All() map[datastore.FeederPrice]*messages.Price {
 	p.mu.Lock()
@@ -0,0 +1,308 @@
+// Package decimal provides an arbitrary-precision number type used to carry
+// prices from origins to signed oracle messages without the rounding errors
+// of float64 arithmetic.
+package decimal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxExponent limits the exponent accepted by NewFromString. Larger
+// exponents are never used for prices and would allow to create numbers
+// that are very expensive to compute.
+const maxExponent = 1000
+
+// MaxFractionDigits is the maximum number of fractional digits used by the
+// String method for numbers which do not have a finite decimal representation
+// (e.g. 1/3).
+const MaxFractionDigits = 36
+
+var ErrInvalidNumber = errors.New("invalid number")
+
+// Decimal represents an arbitrary-precision number. Numbers parsed from
+// decimal strings are represented exactly and all arithmetic operations
+// (addition, subtraction, multiplication and division) are exact, so
+// the same inputs always give the same result, regardless of the order
+// of magnitude of the values.
+//
+// The zero value is ready to use and represents the number zero. Decimal
+// values are immutable and are stored in a canonical form, so they can be
+// compared using the == operator and used as map keys.
+type Decimal struct {
+	// v is the number in the form returned by big.Rat.RatString, or an
+	// empty string for zero.
+	v string
+}
+
+// Zero is the Decimal with the value of zero.
+var Zero = Decimal{}
+
+// NewFromInt returns a new Decimal for the given integer.
+func NewFromInt(i int64) Decimal {
+	return fromRat(new(big.Rat).SetInt64(i))
+}
+
+// NewFromBigInt returns a new Decimal with the value of i * 10^exp.
+func NewFromBigInt(i *big.Int, exp int) Decimal {
+	if i == nil {
+		return Zero
+	}
+	r := new(big.Rat).SetInt(i)
+	if exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(exp)))
+	}
+	if exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-exp)))
+	}
+	return fromRat(r)
+}
+
+// NewFromFloat64 returns a new Decimal for the given float64. The shortest
+// decimal representation which uniquely identifies the float is used, so
+// the number 0.1 is converted to exactly 1/10. NaN and infinities are
+// converted to zero.
+func NewFromFloat64(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Zero
+	}
+	d, _ := NewFromString(strconv.FormatFloat(f, 'g', -1, 64))
+	return d
+}
+
+// NewFromString parses a number in a decimal notation, optionally with
+// an exponent, e.g. "1.23", "-0.0001" or "1e-18".
+func NewFromString(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/xXpP_") {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return Zero, fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	return fromRat(r), nil
+}
+
+// RequireFromString works like NewFromString but panics if the string
+// cannot be parsed. It is intended for use in tests and constants.
+func RequireFromString(s string) Decimal {
+	d, err := NewFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Add returns d + x.
+func (d Decimal) Add(x Decimal) Decimal {
+	return fromRat(new(big.Rat).Add(d.rat(), x.rat()))
+}
+
+// Sub returns d - x.
+func (d Decimal) Sub(x Decimal) Decimal {
+	return fromRat(new(big.Rat).Sub(d.rat(), x.rat()))
+}
+
+// Mul returns d * x.
+func (d Decimal) Mul(x Decimal) Decimal {
+	return fromRat(new(big.Rat).Mul(d.rat(), x.rat()))
+}
+
+// Div returns d / x. Div panics if x is zero.
+func (d Decimal) Div(x Decimal) Decimal {
+	return fromRat(new(big.Rat).Quo(d.rat(), x.rat()))
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return fromRat(new(big.Rat).Neg(d.rat()))
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return fromRat(new(big.Rat).Abs(d.rat()))
+}
+
+// Cmp compares d and x and returns -1 if d < x, 0 if d == x and +1 if d > x.
+func (d Decimal) Cmp(x Decimal) int {
+	return d.rat().Cmp(x.rat())
+}
+
+// Equal returns true if d == x.
+func (d Decimal) Equal(x Decimal) bool {
+	return d.v == x.v
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and +1 if d > 0.
+func (d Decimal) Sign() int {
+	if d.v == "" {
+		return 0
+	}
+	if d.v[0] == '-' {
+		return -1
+	}
+	return 1
+}
+
+// IsZero returns true if d == 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// IsPositive returns true if d > 0.
+func (d Decimal) IsPositive() bool {
+	return d.Sign() > 0
+}
+
+// Float64 returns the nearest float64 value for d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// BigInt returns d * 10^decimals truncated toward zero. It is used to convert
+// prices to the fixed-point representation used by smart contracts.
+func (d Decimal) BigInt(decimals int) *big.Int {
+	r := new(big.Rat).Mul(d.rat(), new(big.Rat).SetInt(pow10(decimals)))
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// Rat returns the value as a big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return d.rat()
+}
+
+// StringFixed returns d rounded to the given number of fractional digits.
+func (d Decimal) StringFixed(places int) string {
+	return d.rat().FloatString(places)
+}
+
+// String returns d in a decimal notation. If d has a finite decimal
+// representation, the returned string represents it exactly, otherwise it is
+// rounded to MaxFractionDigits fractional digits.
+func (d Decimal) String() string {
+	places := fractionDigits(d.rat().Denom())
+	if places < 0 || places > MaxFractionDigits {
+		return trimZeros(d.rat().FloatString(MaxFractionDigits))
+	}
+	return d.rat().FloatString(places)
+}
+
+// MarshalJSON implements the json.Marshaler interface. The value is encoded
+// as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Just like for
+// float64 values, only JSON numbers are accepted and the null value is
+// ignored.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	var n json.Number
+	if len(b) == 0 || b[0] == '"' {
+		return fmt.Errorf("%w: %s", ErrInvalidNumber, b)
+	}
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	v, err := NewFromString(n.String())
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (d Decimal) GobEncode() ([]byte, error) {
+	return []byte(d.v), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Decimal) GobDecode(b []byte) error {
+	if len(b) == 0 {
+		*d = Zero
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(string(b))
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidNumber, b)
+	}
+	*d = fromRat(r)
+	return nil
+}
+
+func fromRat(r *big.Rat) Decimal {
+	if r.Sign() == 0 {
+		return Zero
+	}
+	return Decimal{v: r.RatString()}
+}
+
+func (d Decimal) rat() *big.Rat {
+	r := new(big.Rat)
+	if d.v != "" {
+		// The value is always in a valid form, because it is created
+		// by the fromRat function.
+		r.SetString(d.v)
+	}
+	return r
+}
+
+// fractionDigits returns the number of fractional digits required to
+// represent a fraction with the given denominator exactly. If the fraction
+// does not have a finite decimal representation, -1 is returned.
+func fractionDigits(denom *big.Int) int {
+	var twos, fives int
+	n := new(big.Int).Set(denom)
+	m := new(big.Int)
+	for n.Bit(0) == 0 && n.Sign() > 0 {
+		n.Rsh(n, 1)
+		twos++
+	}
+	five := big.NewInt(5)
+	for {
+		q, r := new(big.Int).QuoRem(n, five, m)
+		if r.Sign() != 0 {
+			break
+		}
+		n = q
+		fives++
+	}
+	if n.Cmp(big.NewInt(1)) != 0 {
+		return -1
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
+
+func trimZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
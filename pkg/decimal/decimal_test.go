@@ -0,0 +1,136 @@
+package decimal
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromString(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "0", want: "0"},
+		{in: "1.10", want: "1.1"},
+		{in: "-0.5", want: "-0.5"},
+		{in: "1e-18", want: "0.000000000000000001"},
+		{in: "1.5E3", want: "1500"},
+		{in: "0.000000002713742016", want: "0.000000002713742016"},
+		{in: "98765432109876543210.123456789", want: "98765432109876543210.123456789"},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1/3", wantErr: true},
+		{in: "0x10", wantErr: true},
+		{in: "1e1000000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := NewFromString(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidNumber)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d.String())
+		})
+	}
+}
+
+func TestNewFromFloat64(t *testing.T) {
+	assert.Equal(t, "0.1", NewFromFloat64(0.1).String())
+	assert.Equal(t, "0.000000002713742016", NewFromFloat64(0.000000002713742016).String())
+	assert.Equal(t, Zero, NewFromFloat64(0))
+}
+
+func TestNewFromBigInt(t *testing.T) {
+	assert.Equal(t, "1.5", NewFromBigInt(big.NewInt(15), -1).String())
+	assert.Equal(t, "1500", NewFromBigInt(big.NewInt(15), 2).String())
+	assert.Equal(t, Zero, NewFromBigInt(nil, 0))
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := RequireFromString("0.1")
+	b := RequireFromString("0.2")
+
+	assert.Equal(t, RequireFromString("0.3"), a.Add(b))
+	assert.Equal(t, RequireFromString("-0.1"), a.Sub(b))
+	assert.Equal(t, RequireFromString("0.02"), a.Mul(b))
+	assert.Equal(t, RequireFromString("0.5"), a.Div(b))
+	assert.Equal(t, RequireFromString("-0.1"), a.Neg())
+	assert.Equal(t, a, a.Neg().Abs())
+
+	// Division is exact, so multiplying the result back gives the original
+	// value:
+	three := NewFromInt(3)
+	assert.Equal(t, NewFromInt(1), NewFromInt(1).Div(three).Mul(three))
+
+	// Zero values are usable:
+	var z Decimal
+	assert.Equal(t, a, z.Add(a))
+	assert.True(t, z.IsZero())
+	assert.Equal(t, Zero, a.Sub(a))
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	a := RequireFromString("0.000000000000000001")
+	b := RequireFromString("0.000000000000000002")
+
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(a))
+	assert.True(t, a.Equal(RequireFromString("1e-18")))
+	assert.True(t, a == RequireFromString("1e-18"))
+	assert.Equal(t, 1, a.Sign())
+	assert.Equal(t, -1, a.Neg().Sign())
+	assert.Equal(t, 0, Zero.Sign())
+	assert.True(t, a.IsPositive())
+	assert.False(t, a.Neg().IsPositive())
+}
+
+func TestDecimal_String(t *testing.T) {
+	assert.Equal(t, "0", Zero.String())
+	assert.Equal(t, "0.333333333333333333333333333333333333", NewFromInt(1).Div(NewFromInt(3)).String())
+	assert.Equal(t, "0.25", NewFromInt(1).Div(NewFromInt(4)).String())
+	assert.Equal(t, "0.33", NewFromInt(1).Div(NewFromInt(3)).StringFixed(2))
+}
+
+func TestDecimal_BigInt(t *testing.T) {
+	assert.Equal(t, "1", RequireFromString("0.000000000000000001").BigInt(18).String())
+	assert.Equal(t, "333333333333333333", NewFromInt(1).Div(NewFromInt(3)).BigInt(18).String())
+	assert.Equal(t, "-1", RequireFromString("-1.9").BigInt(0).String())
+	assert.Equal(t, "0", Zero.BigInt(18).String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"a":0.000000002713742016,"b":null}`), &v))
+	assert.Equal(t, RequireFromString("0.000000002713742016"), v.A)
+	assert.Equal(t, Zero, v.B)
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":0.000000002713742016,"b":0}`, string(b))
+
+	// Strings are not accepted, just like for float64 values:
+	assert.Error(t, json.Unmarshal([]byte(`{"a":"1"}`), &v))
+}
+
+func TestDecimal_Gob(t *testing.T) {
+	in := []Decimal{Zero, RequireFromString("-1.5"), NewFromInt(1).Div(NewFromInt(3))}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gob.NewEncoder(buf).Encode(in))
+
+	var out []Decimal
+	require.NoError(t, gob.NewDecoder(buf).Decode(&out))
+	assert.Equal(t, in, out)
+}
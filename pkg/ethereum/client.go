@@ -8,9 +8,9 @@ import (
 type Transaction struct {
 	// Address is the contract's address.
 	Address Address
-	// Nonce is the transaction nonce. If zero, the nonce will be filled
+	// Nonce is the transaction nonce. If nil, the nonce will be filled
 	// automatically.
-	Nonce uint64
+	Nonce *uint64
 	// PriorityFee is the maximum tip value. If nil, the suggested gas tip value
 	// will be used.
 	PriorityFee *big.Int
@@ -29,6 +29,41 @@ type Transaction struct {
 	SignedTx interface{}
 }
 
+// Fees contains fee values per gas unit used in EIP-1559 transactions.
+type Fees struct {
+	// BaseFee is the base fee of the next block.
+	BaseFee *big.Int
+	// PriorityFee is the suggested priority fee.
+	PriorityFee *big.Int
+}
+
+// FeeHistory contains the fee history of a range of blocks, as returned
+// by the eth_feeHistory RPC method.
+type FeeHistory struct {
+	// OldestBlock is the number of the first block in the range.
+	OldestBlock *big.Int
+	// Reward contains priority fees at requested percentiles for each block.
+	Reward [][]*big.Int
+	// BaseFee contains base fees for each block and the block after the
+	// newest one.
+	BaseFee []*big.Int
+	// GasUsedRatio contains the gas used ratio for each block.
+	GasUsedRatio []float64
+}
+
+// Receipt contains the result of a mined transaction.
+type Receipt struct {
+	// TxHash is the transaction hash.
+	TxHash Hash
+	// BlockNumber is the number of the block in which the transaction
+	// was included.
+	BlockNumber *big.Int
+	// Success is false if the transaction was reverted.
+	Success bool
+	// GasUsed is the amount of gas used by the transaction.
+	GasUsed uint64
+}
+
 type Call struct {
 	// Address is the contract's address.
 	Address Address
@@ -49,4 +84,20 @@ type Client interface {
 	// SendTransaction injects a signed transaction into the pending pool
 	// for execution.
 	SendTransaction(ctx context.Context, transaction *Transaction) (*Hash, error)
+	// EstimateGas returns the amount of gas needed to execute the call.
+	EstimateGas(ctx context.Context, call Call) (uint64, error)
+	// SuggestFees returns the base fee of the next block and a priority fee
+	// which is the given percentile of priority fees paid in recent
+	// blocks. If the percentile is zero, the priority fee suggested by
+	// the node is used.
+	SuggestFees(ctx context.Context, percentile float64) (*Fees, error)
+	// TransactionReceipt returns the receipt of a mined transaction. If
+	// the transaction is not mined yet, nil is returned.
+	TransactionReceipt(ctx context.Context, hash Hash) (*Receipt, error)
+	// Nonce returns the number of transactions sent from the address which
+	// are included in the latest block.
+	Nonce(ctx context.Context, address Address) (uint64, error)
+	// PendingNonce returns the nonce of the next transaction sent from
+	// the address, including transactions in the pending pool.
+	PendingNonce(ctx context.Context, address Address) (uint64, error)
 }
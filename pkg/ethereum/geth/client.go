@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"sort"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -37,8 +38,13 @@ var multiCallContracts = map[uint64]common.Address{
 	xdaiChainID:    common.HexToAddress("0xb5b692a88bdfc81ca69dcb1d924f59f0413a602a"),
 }
 
+// feeHistoryBlocks is the number of recent blocks used to suggest
+// a priority fee.
+const feeHistoryBlocks = 10
+
 var ErrMulticallNotSupported = errors.New("multicall is not supported on current chain")
 var ErrInvalidSignedTxType = errors.New("unable to send transaction, SignedTx field have invalid type")
+var ErrEmptyFeeHistory = errors.New("unable to suggest fees, fee history is empty")
 
 // ErrRevert may be returned by Client.Call method in case of EVM revert.
 type ErrRevert struct {
@@ -64,12 +70,16 @@ type EthClient interface {
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	NetworkID(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, percentiles []float64) (*pkgEthereum.FeeHistory, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 }
 
 // Client implements the ethereum.Client interface.
 type Client struct {
 	ethClient EthClient
 	signer    pkgEthereum.Signer
+	nonces    *NonceManager
 }
 
 // NewClient returns a new Client instance.
@@ -77,9 +87,17 @@ func NewClient(ethClient EthClient, signer pkgEthereum.Signer) *Client {
 	return &Client{
 		ethClient: ethClient,
 		signer:    signer,
+		nonces:    NewNonceManager(ethClient, 0),
 	}
 }
 
+// NonceManager returns the NonceManager used to fill nonces of sent
+// transactions. Other components which send transactions on behalf of
+// the same account should use it to obtain nonces.
+func (e *Client) NonceManager() *NonceManager {
+	return e.nonces
+}
+
 // Call implements the ethereum.Client interface.
 func (e *Client) Call(ctx context.Context, call pkgEthereum.Call) ([]byte, error) {
 	addr := common.Address{}
@@ -153,6 +171,92 @@ func (e *Client) Storage(ctx context.Context, address pkgEthereum.Address, key p
 	return e.ethClient.StorageAt(ctx, address, key, nil)
 }
 
+// EstimateGas implements the ethereum.Client interface.
+func (e *Client) EstimateGas(ctx context.Context, call pkgEthereum.Call) (uint64, error) {
+	addr := common.Address{}
+	if e.signer != nil {
+		addr = e.signer.Address()
+	}
+
+	gas, err := e.ethClient.EstimateGas(ctx, ethereum.CallMsg{
+		From: addr,
+		To:   &call.Address,
+		Data: call.Data,
+	})
+	if err := isRevertErr(err); err != nil {
+		return 0, err
+	}
+	return gas, err
+}
+
+// SuggestFees implements the ethereum.Client interface.
+func (e *Client) SuggestFees(ctx context.Context, percentile float64) (*pkgEthereum.Fees, error) {
+	var percentiles []float64
+	if percentile > 0 {
+		percentiles = []float64{percentile}
+	}
+	history, err := e.ethClient.FeeHistory(ctx, feeHistoryBlocks, nil, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, ErrEmptyFeeHistory
+	}
+
+	// The last base fee in the history is the base fee of the next block:
+	fees := &pkgEthereum.Fees{BaseFee: history.BaseFee[len(history.BaseFee)-1]}
+
+	// Use the median of rewards at the given percentile from recent blocks:
+	if percentile > 0 {
+		var rewards []*big.Int
+		for _, r := range history.Reward {
+			if len(r) > 0 && r[0] != nil {
+				rewards = append(rewards, r[0])
+			}
+		}
+		if len(rewards) > 0 {
+			sort.Slice(rewards, func(i, j int) bool {
+				return rewards[i].Cmp(rewards[j]) < 0
+			})
+			fees.PriorityFee = rewards[len(rewards)/2]
+		}
+	}
+	if fees.PriorityFee == nil {
+		fees.PriorityFee, err = e.ethClient.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fees, nil
+}
+
+// TransactionReceipt implements the ethereum.Client interface.
+func (e *Client) TransactionReceipt(ctx context.Context, hash pkgEthereum.Hash) (*pkgEthereum.Receipt, error) {
+	receipt, err := e.ethClient.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pkgEthereum.Receipt{
+		TxHash:      receipt.TxHash,
+		BlockNumber: receipt.BlockNumber,
+		Success:     receipt.Status == types.ReceiptStatusSuccessful,
+		GasUsed:     receipt.GasUsed,
+	}, nil
+}
+
+// Nonce implements the ethereum.Client interface.
+func (e *Client) Nonce(ctx context.Context, address pkgEthereum.Address) (uint64, error) {
+	return e.ethClient.NonceAt(ctx, address, nil)
+}
+
+// PendingNonce implements the ethereum.Client interface.
+func (e *Client) PendingNonce(ctx context.Context, address pkgEthereum.Address) (uint64, error) {
+	return e.ethClient.PendingNonceAt(ctx, address)
+}
+
 // SendTransaction implements the ethereum.Client interface.
 func (e *Client) SendTransaction(ctx context.Context, transaction *pkgEthereum.Transaction) (*pkgEthereum.Hash, error) {
 	var err error
@@ -172,21 +276,33 @@ func (e *Client) SendTransaction(ctx context.Context, transaction *pkgEthereum.T
 	copy(tx.Data, transaction.Data)
 
 	// Fill optional values if necessary:
-	if tx.Nonce == 0 {
-		tx.Nonce, err = e.ethClient.PendingNonceAt(ctx, e.signer.Address())
+	localNonce := false
+	if tx.Nonce == nil {
+		var nonce uint64
+		nonce, err = e.nonces.Next(ctx, e.signer.Address())
 		if err != nil {
 			return nil, err
 		}
+		tx.Nonce = &nonce
+		localNonce = true
 	}
+	// If the transaction could not be prepared, the nonce must be released,
+	// otherwise there would be a gap in nonces:
+	sending := false
+	defer func() {
+		if err != nil && localNonce && !sending {
+			e.nonces.Release(e.signer.Address(), *tx.Nonce)
+		}
+	}()
 	if tx.PriorityFee == nil {
-		suggestedGasTipPrice, err := e.ethClient.SuggestGasTipCap(ctx)
+		tx.PriorityFee, err = e.ethClient.SuggestGasTipCap(ctx)
 		if err != nil {
 			return nil, err
 		}
-		tx.PriorityFee = suggestedGasTipPrice
 	}
 	if tx.MaxFee == nil {
-		suggestedGasPrice, err := e.ethClient.SuggestGasPrice(ctx)
+		var suggestedGasPrice *big.Int
+		suggestedGasPrice, err = e.ethClient.SuggestGasPrice(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -206,11 +322,21 @@ func (e *Client) SendTransaction(ctx context.Context, transaction *pkgEthereum.T
 	}
 
 	// Send transaction:
-	if stx, ok := tx.SignedTx.(*types.Transaction); ok {
-		hash := stx.Hash()
-		return &hash, e.ethClient.SendTransaction(ctx, stx)
+	stx, ok := tx.SignedTx.(*types.Transaction)
+	if !ok {
+		err = ErrInvalidSignedTxType
+		return nil, err
+	}
+	sending = true
+	if err = e.ethClient.SendTransaction(ctx, stx); err != nil {
+		// It is not known whether the transaction reached the node or
+		// whether the nonce was outdated, so the nonce is synchronized with
+		// the node again:
+		e.nonces.Reset(e.signer.Address())
+		return nil, err
 	}
-	return nil, ErrInvalidSignedTxType
+	hash := stx.Hash()
+	return &hash, nil
 }
 
 func isRevertResp(resp []byte) error {
@@ -175,9 +175,10 @@ func TestClient_SendTransaction(t *testing.T) {
 		mock.Anything,
 	).Return(nil)
 
+	nonce := uint64(10)
 	tx := &pkgEthereum.Transaction{
 		Address:     clientContractAddress,
-		Nonce:       10,
+		Nonce:       &nonce,
 		PriorityFee: big.NewInt(50),
 		MaxFee:      big.NewInt(100),
 		GasLimit:    big.NewInt(1000),
@@ -251,3 +252,90 @@ func TestClient_SendTransaction_Minimal(t *testing.T) {
 	assert.Equal(t, uint64(1000), stx.Gas())
 	assert.Equal(t, big.NewInt(mainnetChainID), stx.ChainId())
 }
+
+func TestClient_SuggestFees(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, nil)
+
+	ethClient.On(
+		"FeeHistory",
+		mock.Anything,
+		uint64(feeHistoryBlocks),
+		(*big.Int)(nil),
+		[]float64{50},
+	).Return(&pkgEthereum.FeeHistory{
+		Reward:  [][]*big.Int{{big.NewInt(3)}, {big.NewInt(1)}, {big.NewInt(2)}},
+		BaseFee: []*big.Int{big.NewInt(100), big.NewInt(110), big.NewInt(120), big.NewInt(130)},
+	}, nil)
+
+	fees, err := client.SuggestFees(context.Background(), 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(130), fees.BaseFee)
+	assert.Equal(t, big.NewInt(2), fees.PriorityFee)
+}
+
+func TestClient_SuggestFees_NodeTip(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, nil)
+
+	ethClient.On(
+		"FeeHistory",
+		mock.Anything,
+		uint64(feeHistoryBlocks),
+		(*big.Int)(nil),
+		([]float64)(nil),
+	).Return(&pkgEthereum.FeeHistory{
+		BaseFee: []*big.Int{big.NewInt(100), big.NewInt(110)},
+	}, nil)
+	ethClient.On(
+		"SuggestGasTipCap",
+		mock.Anything,
+	).Return(big.NewInt(5), nil)
+
+	fees, err := client.SuggestFees(context.Background(), 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(110), fees.BaseFee)
+	assert.Equal(t, big.NewInt(5), fees.PriorityFee)
+}
+
+func TestClient_EstimateGas(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, NewSigner(account))
+
+	ethClient.On(
+		"EstimateGas",
+		mock.Anything,
+		ethereum.CallMsg{From: clientAddress, To: &clientContractAddress, Data: clientCallData},
+	).Return(uint64(21000), nil)
+
+	gas, err := client.EstimateGas(context.Background(), pkgEthereum.Call{Address: clientContractAddress, Data: clientCallData})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(21000), gas)
+}
+
+func TestClient_TransactionReceipt(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, nil)
+
+	minedHash := common.Hash{1}
+	pendingHash := common.Hash{2}
+	ethClient.On("TransactionReceipt", mock.Anything, minedHash).Return(&types.Receipt{
+		TxHash:      minedHash,
+		BlockNumber: big.NewInt(42),
+		Status:      types.ReceiptStatusFailed,
+		GasUsed:     21000,
+	}, nil)
+	ethClient.On("TransactionReceipt", mock.Anything, pendingHash).Return((*types.Receipt)(nil), ethereum.NotFound)
+
+	receipt, err := client.TransactionReceipt(context.Background(), minedHash)
+	assert.NoError(t, err)
+	assert.Equal(t, &pkgEthereum.Receipt{TxHash: minedHash, BlockNumber: big.NewInt(42), Success: false, GasUsed: 21000}, receipt)
+
+	receipt, err = client.TransactionReceipt(context.Background(), pendingHash)
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+}
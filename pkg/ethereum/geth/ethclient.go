@@ -0,0 +1,70 @@
+package geth
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+)
+
+// RPCEthClient implements the EthClient interface. It embeds
+// the ethclient.Client and adds methods which are missing there.
+type RPCEthClient struct {
+	*ethclient.Client
+	rpc *rpc.Client
+}
+
+// NewRPCEthClient returns a new RPCEthClient instance.
+func NewRPCEthClient(rpcClient *rpc.Client) *RPCEthClient {
+	return &RPCEthClient{
+		Client: ethclient.NewClient(rpcClient),
+		rpc:    rpcClient,
+	}
+}
+
+// FeeHistory returns the fee history of blockCount blocks up to and
+// including lastBlock. If lastBlock is nil, the latest block is used.
+func (c *RPCEthClient) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	percentiles []float64,
+) (*pkgEthereum.FeeHistory, error) {
+
+	var res struct {
+		OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+		Reward       [][]*hexutil.Big `json:"reward"`
+		BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+		GasUsedRatio []float64        `json:"gasUsedRatio"`
+	}
+	block := "latest"
+	if lastBlock != nil {
+		block = hexutil.EncodeBig(lastBlock)
+	}
+	if percentiles == nil {
+		percentiles = []float64{}
+	}
+	err := c.rpc.CallContext(ctx, &res, "eth_feeHistory", hexutil.Uint64(blockCount), block, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	h := &pkgEthereum.FeeHistory{GasUsedRatio: res.GasUsedRatio}
+	if res.OldestBlock != nil {
+		h.OldestBlock = res.OldestBlock.ToInt()
+	}
+	for _, r := range res.Reward {
+		var rewards []*big.Int
+		for _, v := range r {
+			rewards = append(rewards, v.ToInt())
+		}
+		h.Reward = append(h.Reward, rewards)
+	}
+	for _, v := range res.BaseFee {
+		h.BaseFee = append(h.BaseFee, v.ToInt())
+	}
+	return h, nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/mock"
+
+	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
 type EthClient struct {
@@ -53,3 +55,18 @@ func (e *EthClient) NetworkID(ctx context.Context) (*big.Int, error) {
 	args := e.Called(ctx)
 	return args.Get(0).(*big.Int), args.Error(1)
 }
+
+func (e *EthClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	args := e.Called(ctx, call)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (e *EthClient) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, percentiles []float64) (*pkgEthereum.FeeHistory, error) {
+	args := e.Called(ctx, blockCount, lastBlock, percentiles)
+	return args.Get(0).(*pkgEthereum.FeeHistory), args.Error(1)
+}
+
+func (e *EthClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	args := e.Called(ctx, txHash)
+	return args.Get(0).(*types.Receipt), args.Error(1)
+}
@@ -0,0 +1,97 @@
+package geth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultNonceResyncAfter is the default time after which the NonceManager
+// fetches the nonce from the node again if no nonces were handed out.
+const DefaultNonceResyncAfter = time.Minute
+
+// NonceManager hands out sequential nonces for transactions without asking
+// the node for every transaction, so transactions sent concurrently never
+// get the same nonce. The nonce is synchronized with the node when it is
+// used for the first time, after an error reported using the Release or
+// Reset methods, and after it was not used for some time. The last case
+// resolves gaps left by transactions which never reached the node and
+// takes into account transactions sent by other processes.
+//
+// NonceManager is safe for concurrent use.
+type NonceManager struct {
+	mu sync.Mutex
+
+	client      EthClient
+	resyncAfter time.Duration
+	accounts    map[common.Address]*nonceState
+}
+
+type nonceState struct {
+	next     uint64
+	lastUsed time.Time
+}
+
+// NewNonceManager returns a new NonceManager instance. If resyncAfter is
+// zero, the DefaultNonceResyncAfter is used.
+func NewNonceManager(client EthClient, resyncAfter time.Duration) *NonceManager {
+	if resyncAfter == 0 {
+		resyncAfter = DefaultNonceResyncAfter
+	}
+	return &NonceManager{
+		client:      client,
+		resyncAfter: resyncAfter,
+		accounts:    make(map[common.Address]*nonceState),
+	}
+}
+
+// Next returns the next nonce for the given address.
+func (n *NonceManager) Next(ctx context.Context, address common.Address) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	s, ok := n.accounts[address]
+	if !ok || now.Sub(s.lastUsed) >= n.resyncAfter {
+		pending, err := n.client.PendingNonceAt(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+		s = &nonceState{next: pending}
+		n.accounts[address] = s
+	}
+	nonce := s.next
+	s.next++
+	s.lastUsed = now
+	return nonce, nil
+}
+
+// Release must be called when a transaction with a nonce returned by
+// the Next method could not be sent. If it was the last handed out nonce,
+// it is reused for the next transaction, otherwise the nonce will be
+// synchronized with the node.
+func (n *NonceManager) Release(address common.Address, nonce uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	s, ok := n.accounts[address]
+	if !ok {
+		return
+	}
+	if s.next == nonce+1 {
+		s.next = nonce
+		return
+	}
+	delete(n.accounts, address)
+}
+
+// Reset forces the nonce for the given address to be synchronized with
+// the node on the next call to the Next method.
+func (n *NonceManager) Reset(address common.Address) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.accounts, address)
+}
@@ -0,0 +1,161 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/geth/mocks"
+)
+
+func TestNonceManager_Next_Concurrent(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	nm := NewNonceManager(ethClient, 0)
+
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(10, nil).Once()
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	nonces := make(map[uint64]bool)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := nm.Next(context.Background(), clientAddress)
+			assert.NoError(t, err)
+			mu.Lock()
+			nonces[n] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Every nonce must be unique and sequential:
+	require.Len(t, nonces, 20)
+	for i := uint64(10); i < 30; i++ {
+		assert.True(t, nonces[i])
+	}
+	ethClient.AssertNumberOfCalls(t, "PendingNonceAt", 1)
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	nm := NewNonceManager(ethClient, 0)
+	ctx := context.Background()
+
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(10, nil).Once()
+	n1, _ := nm.Next(ctx, clientAddress)
+	n2, _ := nm.Next(ctx, clientAddress)
+
+	// The last nonce is reused:
+	nm.Release(clientAddress, n2)
+	n3, _ := nm.Next(ctx, clientAddress)
+	assert.Equal(t, uint64(10), n1)
+	assert.Equal(t, uint64(11), n3)
+
+	// A gap is created, so the nonce is fetched again:
+	nm.Release(clientAddress, n1)
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(10, nil).Once()
+	n4, _ := nm.Next(ctx, clientAddress)
+	assert.Equal(t, uint64(10), n4)
+	ethClient.AssertNumberOfCalls(t, "PendingNonceAt", 2)
+}
+
+func TestNonceManager_Resync(t *testing.T) {
+	ethClient := &mocks.EthClient{}
+	nm := NewNonceManager(ethClient, time.Minute)
+	ctx := context.Background()
+
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(10, nil).Once()
+	n, _ := nm.Next(ctx, clientAddress)
+	assert.Equal(t, uint64(10), n)
+
+	// The nonce was not used for a long time, and other transactions were
+	// sent in the meantime:
+	nm.accounts[clientAddress].lastUsed = time.Now().Add(-2 * time.Minute)
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(15, nil).Once()
+	n, _ = nm.Next(ctx, clientAddress)
+	assert.Equal(t, uint64(15), n)
+
+	// After reset, the nonce is fetched again:
+	nm.Reset(clientAddress)
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(16, nil).Once()
+	n, _ = nm.Next(ctx, clientAddress)
+	assert.Equal(t, uint64(16), n)
+}
+
+func TestClient_SendTransaction_NonceReset(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, NewSigner(account))
+	ctx := context.Background()
+
+	tx := &pkgEthereum.Transaction{
+		Address:     clientContractAddress,
+		PriorityFee: big.NewInt(50),
+		MaxFee:      big.NewInt(100),
+		GasLimit:    big.NewInt(1000),
+		ChainID:     big.NewInt(mainnetChainID),
+		Data:        clientCallData,
+	}
+
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(10, nil).Once()
+	ethClient.On("SendTransaction", mock.Anything, mock.Anything).Return(nil).Twice()
+	_, err := client.SendTransaction(ctx, tx)
+	require.NoError(t, err)
+	_, err = client.SendTransaction(ctx, tx)
+	require.NoError(t, err)
+
+	// After an error, the nonce is synchronized with the node:
+	ethClient.On("SendTransaction", mock.Anything, mock.Anything).Return(errors.New("nonce too low")).Once()
+	_, err = client.SendTransaction(ctx, tx)
+	require.Error(t, err)
+
+	ethClient.On("PendingNonceAt", mock.Anything, clientAddress).Return(20, nil).Once()
+	ethClient.On("SendTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err = client.SendTransaction(ctx, tx)
+	require.NoError(t, err)
+
+	var nonces []uint64
+	for _, c := range ethClient.Calls {
+		if c.Method == "SendTransaction" {
+			nonces = append(nonces, c.Arguments.Get(1).(*types.Transaction).Nonce())
+		}
+	}
+	assert.Equal(t, []uint64{10, 11, 12, 20}, nonces)
+}
+
+func TestClient_SendTransaction_ZeroNonce(t *testing.T) {
+	account, _ := NewAccount("./testdata/keystore", "test123", clientAddress)
+	ethClient := &mocks.EthClient{}
+	client := NewClient(ethClient, NewSigner(account))
+
+	// An explicitly set zero nonce must not be replaced by a nonce from
+	// the nonce manager:
+	nonce := uint64(0)
+	tx := &pkgEthereum.Transaction{
+		Address:     clientContractAddress,
+		Nonce:       &nonce,
+		PriorityFee: big.NewInt(50),
+		MaxFee:      big.NewInt(100),
+		GasLimit:    big.NewInt(1000),
+		ChainID:     big.NewInt(mainnetChainID),
+		Data:        clientCallData,
+	}
+
+	ethClient.On("SendTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err := client.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+
+	ethClient.AssertNotCalled(t, "PendingNonceAt", mock.Anything, mock.Anything)
+	assert.Equal(t, uint64(0), ethClient.Calls[0].Arguments.Get(1).(*types.Transaction).Nonce())
+}
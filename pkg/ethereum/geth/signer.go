@@ -11,6 +11,7 @@ import (
 )
 
 var ErrInvalidSignature = errors.New("invalid Ethereum signature (V is not 27 or 28)")
+var ErrMissingNonce = errors.New("unable to sign transaction, nonce is not set")
 
 type Signer struct {
 	account *Account
@@ -35,9 +36,12 @@ func (s *Signer) Address() ethereum.Address {
 
 // SignTransaction implements the ethereum.Signer interface.
 func (s *Signer) SignTransaction(transaction *ethereum.Transaction) error {
+	if transaction.Nonce == nil {
+		return ErrMissingNonce
+	}
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:    nil,
-		Nonce:      transaction.Nonce,
+		Nonce:      *transaction.Nonce,
 		GasTipCap:  transaction.PriorityFee,
 		GasFeeCap:  transaction.MaxFee,
 		Gas:        transaction.GasLimit.Uint64(),
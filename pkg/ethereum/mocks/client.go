@@ -31,3 +31,28 @@ func (c *Client) SendTransaction(ctx context.Context, transaction *ethereum.Tran
 	args := c.Called(ctx, transaction)
 	return args.Get(0).(*ethereum.Hash), args.Error(1)
 }
+
+func (c *Client) EstimateGas(ctx context.Context, call ethereum.Call) (uint64, error) {
+	args := c.Called(ctx, call)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (c *Client) SuggestFees(ctx context.Context, percentile float64) (*ethereum.Fees, error) {
+	args := c.Called(ctx, percentile)
+	return args.Get(0).(*ethereum.Fees), args.Error(1)
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, hash ethereum.Hash) (*ethereum.Receipt, error) {
+	args := c.Called(ctx, hash)
+	return args.Get(0).(*ethereum.Receipt), args.Error(1)
+}
+
+func (c *Client) Nonce(ctx context.Context, address ethereum.Address) (uint64, error) {
+	args := c.Called(ctx, address)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (c *Client) PendingNonce(ctx context.Context, address ethereum.Address) (uint64, error) {
+	args := c.Called(ctx, address)
+	return args.Get(0).(uint64), args.Error(1)
+}
@@ -0,0 +1,565 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/log"
+)
+
+const LoggerTag = "TXMANAGER"
+
+const (
+	// DefaultInterval is the default interval between checks of pending
+	// transactions.
+	DefaultInterval = 15 * time.Second
+	// DefaultPendingTimeout is the default time after which a pending
+	// transaction is replaced.
+	DefaultPendingTimeout = 3 * time.Minute
+	// DefaultFeeBump is the default fraction by which fees are increased
+	// when a transaction is replaced. Nodes usually require at least 10%.
+	DefaultFeeBump = 0.125
+	// DefaultMaxReplacements is the default maximum number of times
+	// a transaction is replaced.
+	DefaultMaxReplacements = 3
+)
+
+// retention is the time for which resolved transactions are remembered.
+const retention = time.Hour
+
+// Status is the status of a transaction sent by the Manager.
+type Status int
+
+const (
+	// StatusUnknown is returned for transactions which are not tracked.
+	StatusUnknown Status = iota
+	// StatusPending means that the transaction is not mined yet.
+	StatusPending
+	// StatusMined means that the transaction was mined successfully.
+	StatusMined
+	// StatusReverted means that the transaction was mined but reverted.
+	StatusReverted
+	// StatusDropped means that the transaction will never be mined, because
+	// its nonce was used by another transaction. Transactions which are
+	// still pending after the maximum number of replacements stay pending.
+	StatusDropped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusMined:
+		return "mined"
+	case StatusReverted:
+		return "reverted"
+	case StatusDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats contains counters of transactions handled by the Manager.
+type Stats struct {
+	Sent     uint64
+	Replaced uint64
+	Mined    uint64
+	Reverted uint64
+	Dropped  uint64
+}
+
+// NonceSource hands out nonces for new transactions. It is implemented by
+// the geth.NonceManager.
+type NonceSource interface {
+	// Next returns the next nonce for the address.
+	Next(ctx context.Context, address ethereum.Address) (uint64, error)
+	// Release returns a nonce of a transaction which was not sent.
+	Release(address ethereum.Address, nonce uint64)
+	// Reset forces the nonce to be synchronized with the node.
+	Reset(address ethereum.Address)
+}
+
+// SpendingLimit limits the amount of wei spent on fees. It is implemented
+// by the geth.SpendingLimit.
+type SpendingLimit interface {
+	// Reserve counts the cost of a transaction. If the limit would be
+	// exceeded, an error is returned. The returned function removes
+	// the cost if the transaction was not sent.
+	Reserve(now time.Time, cost *big.Int) (func(), error)
+}
+
+// Config is the configuration for the Manager.
+type Config struct {
+	// Client is used to send and check transactions.
+	Client ethereum.Client
+	// Signer is used to obtain the address from which transactions are sent.
+	Signer ethereum.Signer
+	// Nonces is used to obtain nonces for new transactions. It should be
+	// shared with other components sending transactions from the same
+	// account. If nil, the pending nonce is fetched from the node.
+	Nonces NonceSource
+	// Interval is the interval between checks of pending transactions. If
+	// zero, the DefaultInterval is used.
+	Interval time.Duration
+	// PendingTimeout is the time after which a pending transaction is
+	// replaced by a transaction with the same nonce and higher fees. If
+	// zero, the DefaultPendingTimeout is used.
+	PendingTimeout time.Duration
+	// FeeBump is the fraction by which fees are increased in a replacement
+	// transaction. If zero, the DefaultFeeBump is used.
+	FeeBump float64
+	// MaxReplacements is the maximum number of times a transaction is
+	// replaced. After that, the transaction is tracked until it is mined or
+	// its nonce is used. If zero, the DefaultMaxReplacements is used.
+	MaxReplacements int
+	// MaxFee returns the cap for the fee per gas in wei used in replacement
+	// transactions sent to the given address. If nil, or if the function
+	// returns nil, fees are not capped.
+	MaxFee func(address ethereum.Address) *big.Int
+	// SpendingLimits returns spending limits for transactions sent to
+	// the given address. The additional cost of a replacement transaction,
+	// which is the gas limit multiplied by the increase of the max fee, is
+	// counted in these limits. A transaction is not replaced if any of
+	// the limits would be exceeded. If nil, limits are not checked.
+	SpendingLimits func(address ethereum.Address) []SpendingLimit
+	// Logger is a current logger interface used by the Manager.
+	Logger log.Logger
+}
+
+// Manager implements the ethereum.Client interface. Transactions sent using
+// the Manager are tracked until they are mined. Transactions which are
+// pending for too long are replaced by transactions with the same nonce and
+// higher fees.
+type Manager struct {
+	ethereum.Client
+
+	ctx    context.Context
+	doneCh chan struct{}
+
+	signer          ethereum.Signer
+	nonces          NonceSource
+	interval        time.Duration
+	pendingTimeout  time.Duration
+	feeBump         float64
+	maxReplacements int
+	maxFee          func(address ethereum.Address) *big.Int
+	spendingLimits  func(address ethereum.Address) []SpendingLimit
+	log             log.Logger
+
+	// sendMu serializes sending of transactions, so if the nonce is obtained
+	// from the node, it includes previously sent transactions.
+	sendMu sync.Mutex
+
+	mu    sync.Mutex
+	txs   map[ethereum.Hash]*trackedTx
+	stats Stats
+}
+
+// trackedTx is a transaction sent by the Manager. It is stored in the txs
+// map under hashes of all its replacements.
+type trackedTx struct {
+	tx           *ethereum.Transaction
+	hashes       []ethereum.Hash
+	sentAt       time.Time
+	replacements int
+	// exhausted is set when the maximum number of replacements is reached.
+	exhausted  bool
+	status     Status
+	resolvedAt time.Time
+}
+
+// NewManager returns a new Manager instance.
+func NewManager(ctx context.Context, cfg Config) (*Manager, error) {
+	if ctx == nil {
+		return nil, errors.New("context must not be nil")
+	}
+	if cfg.Client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if cfg.Signer == nil {
+		return nil, errors.New("signer must not be nil")
+	}
+	if cfg.FeeBump < 0 {
+		return nil, errors.New("fee bump must not be negative")
+	}
+	m := &Manager{
+		Client:          cfg.Client,
+		ctx:             ctx,
+		doneCh:          make(chan struct{}),
+		signer:          cfg.Signer,
+		nonces:          cfg.Nonces,
+		interval:        cfg.Interval,
+		pendingTimeout:  cfg.PendingTimeout,
+		feeBump:         cfg.FeeBump,
+		maxReplacements: cfg.MaxReplacements,
+		maxFee:          cfg.MaxFee,
+		spendingLimits:  cfg.SpendingLimits,
+		txs:             make(map[ethereum.Hash]*trackedTx),
+		log:             cfg.Logger.WithField("tag", LoggerTag),
+	}
+	if m.interval == 0 {
+		m.interval = DefaultInterval
+	}
+	if m.pendingTimeout == 0 {
+		m.pendingTimeout = DefaultPendingTimeout
+	}
+	if m.feeBump == 0 {
+		m.feeBump = DefaultFeeBump
+	}
+	if m.maxReplacements == 0 {
+		m.maxReplacements = DefaultMaxReplacements
+	}
+	return m, nil
+}
+
+// Start starts checking pending transactions.
+func (m *Manager) Start() error {
+	m.log.Info("Starting")
+	go m.checkLoop()
+	return nil
+}
+
+// Wait waits until the context is canceled.
+func (m *Manager) Wait() {
+	<-m.doneCh
+}
+
+// SendTransaction implements the ethereum.Client interface. Missing nonce
+// and fees are filled before the transaction is sent, so the transaction
+// can be replaced later.
+func (m *Manager) SendTransaction(ctx context.Context, transaction *ethereum.Transaction) (*ethereum.Hash, error) {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+
+	var err error
+	tx := copyTransaction(transaction)
+	localNonce := false
+	if tx.Nonce == nil {
+		nonce, err := m.nextNonce(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tx.Nonce = &nonce
+		localNonce = true
+	}
+	if tx.PriorityFee == nil || tx.MaxFee == nil {
+		fees, err := m.Client.SuggestFees(ctx, 0)
+		if err != nil {
+			if localNonce && m.nonces != nil {
+				m.nonces.Release(m.signer.Address(), *tx.Nonce)
+			}
+			return nil, err
+		}
+		if tx.PriorityFee == nil {
+			tx.PriorityFee = fees.PriorityFee
+		}
+		if tx.MaxFee == nil {
+			tx.MaxFee = new(big.Int).Add(new(big.Int).Mul(fees.BaseFee, big.NewInt(2)), tx.PriorityFee)
+		}
+	}
+	hash, err := m.Client.SendTransaction(ctx, tx)
+	if err != nil {
+		if localNonce && m.nonces != nil {
+			m.nonces.Reset(m.signer.Address())
+		}
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.txs[*hash] = &trackedTx{
+		tx:     tx,
+		hashes: []ethereum.Hash{*hash},
+		sentAt: time.Now(),
+		status: StatusPending,
+	}
+	m.stats.Sent++
+	return hash, nil
+}
+
+// nextNonce returns the nonce for a new transaction.
+func (m *Manager) nextNonce(ctx context.Context) (uint64, error) {
+	if m.nonces != nil {
+		return m.nonces.Next(ctx, m.signer.Address())
+	}
+	return m.Client.PendingNonce(ctx, m.signer.Address())
+}
+
+// Status returns the status of a transaction sent by the Manager. The hash
+// may be the hash of the original transaction or any of its replacements.
+func (m *Manager) Status(hash ethereum.Hash) Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.txs[hash]; ok {
+		return t.status
+	}
+	return StatusUnknown
+}
+
+// Stats returns counters of handled transactions.
+func (m *Manager) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// checkLoop periodically checks pending transactions until the context
+// is canceled.
+func (m *Manager) checkLoop() {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case t := <-ticker.C:
+			m.check(t)
+		}
+	}
+}
+
+// check checks all pending transactions and replaces the ones which are
+// pending for too long.
+func (m *Manager) check(now time.Time) {
+	for _, t := range m.pending(now) {
+		m.checkTx(now, t)
+	}
+}
+
+// pending returns pending transactions and forgets about transactions
+// resolved more than the retention time ago.
+func (m *Manager) pending(now time.Time) []*trackedTx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []*trackedTx
+	seen := make(map[*trackedTx]bool)
+	for h, t := range m.txs {
+		if t.status != StatusPending && now.Sub(t.resolvedAt) > retention {
+			delete(m.txs, h)
+			continue
+		}
+		if t.status == StatusPending && !seen[t] {
+			seen[t] = true
+			pending = append(pending, t)
+		}
+	}
+	return pending
+}
+
+func (m *Manager) checkTx(now time.Time, t *trackedTx) {
+	if m.checkReceipts(now, t) {
+		return
+	}
+
+	// If the nonce was used, but none of our transactions were mined, then
+	// the nonce was used by another transaction. Receipts are checked
+	// again, because one of transactions might be mined in the meantime:
+	nonce, err := m.Client.Nonce(m.ctx, m.signer.Address())
+	if err != nil {
+		m.log.WithError(err).WithFields(m.fields(t)).Warn("Unable to fetch nonce")
+		return
+	}
+	if nonce > *t.tx.Nonce {
+		if !m.checkReceipts(now, t) {
+			m.resolve(now, t, StatusDropped, log.Fields{"reason": "nonce was used by another transaction"})
+		}
+		return
+	}
+
+	if now.Sub(t.sentAt) < m.pendingTimeout {
+		return
+	}
+	if t.replacements >= m.maxReplacements {
+		// The transaction may still be mined and its nonce blocks later
+		// transactions, so it stays pending:
+		if !t.exhausted {
+			t.exhausted = true
+			m.log.WithFields(m.fields(t)).Warn("Maximum number of replacements reached, waiting for the transaction to be mined")
+		}
+		return
+	}
+	m.replace(now, t)
+}
+
+// checkReceipts checks if any of sent transactions was mined. It returns
+// true if the transaction is resolved or if receipts could not be fetched.
+func (m *Manager) checkReceipts(now time.Time, t *trackedTx) bool {
+	for _, h := range t.hashes {
+		receipt, err := m.Client.TransactionReceipt(m.ctx, h)
+		if err != nil {
+			m.log.WithError(err).WithFields(m.fields(t)).Warn("Unable to fetch transaction receipt")
+			return true
+		}
+		if receipt == nil {
+			continue
+		}
+		fields := log.Fields{"minedTx": h.String(), "block": receipt.BlockNumber.String()}
+		if receipt.Success {
+			m.resolve(now, t, StatusMined, fields)
+		} else {
+			m.resolve(now, t, StatusReverted, fields)
+		}
+		return true
+	}
+	return false
+}
+
+// replace sends a transaction with the same nonce and increased fees.
+func (m *Manager) replace(now time.Time, t *trackedTx) {
+	fees, err := m.Client.SuggestFees(m.ctx, 0)
+	if err != nil {
+		m.log.WithError(err).WithFields(m.fields(t)).Warn("Unable to replace transaction")
+		return
+	}
+
+	// Fees of the replacement must be higher than the bumped fees of the
+	// previous transaction, otherwise it will be rejected by nodes:
+	tx := copyTransaction(t.tx)
+	tx.SignedTx = nil
+	tx.PriorityFee = maxInt(bump(t.tx.PriorityFee, m.feeBump), fees.PriorityFee)
+	tx.MaxFee = maxInt(
+		bump(t.tx.MaxFee, m.feeBump),
+		new(big.Int).Add(new(big.Int).Mul(fees.BaseFee, big.NewInt(2)), tx.PriorityFee),
+	)
+	if maxFee := m.feeCap(tx.Address); maxFee != nil && tx.MaxFee.Cmp(maxFee) > 0 {
+		if bump(t.tx.MaxFee, m.feeBump).Cmp(maxFee) > 0 {
+			m.log.WithFields(m.fields(t)).Warn("Unable to replace transaction, fees would exceed the cap")
+			return
+		}
+		tx.MaxFee = new(big.Int).Set(maxFee)
+	}
+	if tx.PriorityFee.Cmp(tx.MaxFee) > 0 {
+		tx.PriorityFee = new(big.Int).Set(tx.MaxFee)
+	}
+
+	release, err := m.reserve(now, t.tx, tx)
+	if err != nil {
+		m.log.WithError(err).WithFields(m.fields(t)).Warn("Unable to replace transaction, spending limit would be exceeded")
+		return
+	}
+	hash, err := m.Client.SendTransaction(m.ctx, tx)
+	if err != nil {
+		release()
+		m.log.WithError(err).WithFields(m.fields(t)).Warn("Unable to replace transaction")
+		return
+	}
+
+	m.mu.Lock()
+	t.tx = tx
+	t.hashes = append(t.hashes, *hash)
+	t.sentAt = now
+	t.replacements++
+	m.txs[*hash] = t
+	m.stats.Replaced++
+	m.mu.Unlock()
+
+	m.log.WithFields(m.fields(t)).Warn("Transaction replaced")
+}
+
+// feeCap returns the cap for the fee per gas for transactions sent to
+// the given address or nil if fees are not capped.
+func (m *Manager) feeCap(address ethereum.Address) *big.Int {
+	if m.maxFee == nil {
+		return nil
+	}
+	return m.maxFee(address)
+}
+
+// reserve counts the additional cost of the replacement transaction in
+// spending limits. The returned function removes the cost from all limits
+// if the replacement was not sent.
+func (m *Manager) reserve(now time.Time, prev, next *ethereum.Transaction) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	if m.spendingLimits == nil || next.GasLimit == nil {
+		return release, nil
+	}
+	extra := new(big.Int).Mul(next.GasLimit, new(big.Int).Sub(next.MaxFee, prev.MaxFee))
+	if extra.Sign() <= 0 {
+		return release, nil
+	}
+	for _, l := range m.spendingLimits(next.Address) {
+		r, err := l.Reserve(now, extra)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
+
+// resolve sets the final status of a transaction.
+func (m *Manager) resolve(now time.Time, t *trackedTx, status Status, fields log.Fields) {
+	m.mu.Lock()
+	t.status = status
+	t.resolvedAt = now
+	switch status {
+	case StatusMined:
+		m.stats.Mined++
+	case StatusReverted:
+		m.stats.Reverted++
+	case StatusDropped:
+		m.stats.Dropped++
+	}
+	m.mu.Unlock()
+
+	l := m.log.WithFields(m.fields(t)).WithFields(fields).WithField("status", status.String())
+	if status == StatusMined {
+		l.Info("Transaction mined")
+	} else {
+		l.Warn("Transaction failed")
+	}
+}
+
+func (m *Manager) fields(t *trackedTx) log.Fields {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return log.Fields{
+		"tx":           t.hashes[0].String(),
+		"lastTx":       t.hashes[len(t.hashes)-1].String(),
+		"nonce":        *t.tx.Nonce,
+		"replacements": t.replacements,
+		"maxFee":       t.tx.MaxFee.String(),
+		"priorityFee":  t.tx.PriorityFee.String(),
+	}
+}
+
+func copyTransaction(tx *ethereum.Transaction) *ethereum.Transaction {
+	cpy := *tx
+	if tx.Nonce != nil {
+		nonce := *tx.Nonce
+		cpy.Nonce = &nonce
+	}
+	cpy.Data = make([]byte, len(tx.Data))
+	copy(cpy.Data, tx.Data)
+	return &cpy
+}
+
+// bump returns x increased by the given fraction, rounded up.
+func bump(x *big.Int, fraction float64) *big.Int {
+	r := decimal.NewFromBigInt(x, 0).Mul(decimal.NewFromFloat64(1 + fraction)).Rat()
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+func maxInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,262 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+)
+
+var (
+	testAddress = ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	testHash1   = ethereum.Hash{1}
+	testHash2   = ethereum.Hash{2}
+)
+
+func newTestManager(t *testing.T) (*Manager, *mocks.Client) {
+	c := &mocks.Client{}
+	s := &mocks.Signer{}
+	s.On("Address").Return(testAddress)
+	m, err := NewManager(context.Background(), Config{
+		Client:          c,
+		Signer:          s,
+		PendingTimeout:  time.Minute,
+		MaxReplacements: 1,
+		Logger:          null.New(),
+	})
+	require.NoError(t, err)
+	return m, c
+}
+
+func sendTestTx(t *testing.T, m *Manager, c *mocks.Client) {
+	c.On("PendingNonce", mock.Anything, testAddress).Return(uint64(7), nil).Once()
+	c.On("SuggestFees", mock.Anything, float64(0)).Return(&ethereum.Fees{BaseFee: big.NewInt(100), PriorityFee: big.NewInt(10)}, nil).Once()
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&testHash1, nil).Once()
+
+	hash, err := m.SendTransaction(context.Background(), &ethereum.Transaction{
+		Address:  testAddress,
+		GasLimit: big.NewInt(100000),
+		Data:     []byte{1, 2, 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, testHash1, *hash)
+}
+
+func TestManager_SendTransaction(t *testing.T) {
+	m, c := newTestManager(t)
+	sendTestTx(t, m, c)
+
+	tx := c.Calls[2].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, uint64(7), *tx.Nonce)
+	assert.Equal(t, big.NewInt(10), tx.PriorityFee)
+	assert.Equal(t, big.NewInt(210), tx.MaxFee)
+	assert.Equal(t, StatusPending, m.Status(testHash1))
+	assert.Equal(t, StatusUnknown, m.Status(testHash2))
+	assert.Equal(t, uint64(1), m.Stats().Sent)
+}
+
+func TestManager_check_Mined(t *testing.T) {
+	m, c := newTestManager(t)
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, testHash1).Return(&ethereum.Receipt{
+		TxHash:      testHash1,
+		BlockNumber: big.NewInt(42),
+		Success:     true,
+	}, nil)
+	m.check(time.Now())
+
+	assert.Equal(t, StatusMined, m.Status(testHash1))
+	assert.Equal(t, uint64(1), m.Stats().Mined)
+}
+
+func TestManager_check_Reverted(t *testing.T) {
+	m, c := newTestManager(t)
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, testHash1).Return(&ethereum.Receipt{
+		TxHash:      testHash1,
+		BlockNumber: big.NewInt(42),
+		Success:     false,
+	}, nil)
+	m.check(time.Now())
+
+	assert.Equal(t, StatusReverted, m.Status(testHash1))
+	assert.Equal(t, uint64(1), m.Stats().Reverted)
+}
+
+func TestManager_check_NonceUsed(t *testing.T) {
+	m, c := newTestManager(t)
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, testHash1).Return((*ethereum.Receipt)(nil), nil)
+	c.On("Nonce", mock.Anything, testAddress).Return(uint64(8), nil)
+	m.check(time.Now())
+
+	assert.Equal(t, StatusDropped, m.Status(testHash1))
+	assert.Equal(t, uint64(1), m.Stats().Dropped)
+}
+
+func TestManager_check_Replace(t *testing.T) {
+	m, c := newTestManager(t)
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, mock.Anything).Return((*ethereum.Receipt)(nil), nil)
+	c.On("Nonce", mock.Anything, testAddress).Return(uint64(7), nil)
+
+	// Transaction is not pending long enough to be replaced:
+	m.check(time.Now())
+	assert.Equal(t, StatusPending, m.Status(testHash1))
+	c.AssertNumberOfCalls(t, "SendTransaction", 1)
+
+	// Transaction is replaced with the same nonce and higher fees:
+	c.On("SuggestFees", mock.Anything, float64(0)).Return(&ethereum.Fees{BaseFee: big.NewInt(100), PriorityFee: big.NewInt(10)}, nil).Once()
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&testHash2, nil).Once()
+	m.check(time.Now().Add(2 * time.Minute))
+
+	tx := c.Calls[len(c.Calls)-1].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, uint64(7), *tx.Nonce)
+	assert.Equal(t, big.NewInt(12), tx.PriorityFee)
+	assert.Equal(t, big.NewInt(237), tx.MaxFee)
+	assert.Equal(t, []byte{1, 2, 3}, tx.Data)
+	assert.Equal(t, StatusPending, m.Status(testHash1))
+	assert.Equal(t, StatusPending, m.Status(testHash2))
+	assert.Equal(t, uint64(1), m.Stats().Replaced)
+
+	// The maximum number of replacements is reached, the transaction is no
+	// longer replaced, but it stays pending because its nonce is not used:
+	m.check(time.Now().Add(4 * time.Minute))
+	assert.Equal(t, StatusPending, m.Status(testHash1))
+	assert.Equal(t, StatusPending, m.Status(testHash2))
+	assert.Equal(t, uint64(0), m.Stats().Dropped)
+	c.AssertNumberOfCalls(t, "SendTransaction", 2)
+}
+
+func TestManager_check_ReplaceMaxFee(t *testing.T) {
+	m, c := newTestManager(t)
+	m.maxFee = func(address ethereum.Address) *big.Int {
+		if address == testAddress {
+			return big.NewInt(240)
+		}
+		return nil
+	}
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, mock.Anything).Return((*ethereum.Receipt)(nil), nil)
+	c.On("Nonce", mock.Anything, testAddress).Return(uint64(7), nil)
+	c.On("SuggestFees", mock.Anything, float64(0)).Return(&ethereum.Fees{BaseFee: big.NewInt(150), PriorityFee: big.NewInt(10)}, nil).Once()
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&testHash2, nil).Once()
+	m.check(time.Now().Add(2 * time.Minute))
+
+	// The max fee is capped by the cap for the transaction address:
+	tx := c.Calls[len(c.Calls)-1].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, big.NewInt(240), tx.MaxFee)
+}
+
+type testLimit struct {
+	limit    *big.Int
+	reserved *big.Int
+}
+
+func (l *testLimit) Reserve(_ time.Time, cost *big.Int) (func(), error) {
+	if new(big.Int).Add(l.reserved, cost).Cmp(l.limit) > 0 {
+		return nil, errors.New("limit exceeded")
+	}
+	l.reserved.Add(l.reserved, cost)
+	return func() { l.reserved.Sub(l.reserved, cost) }, nil
+}
+
+func TestManager_check_ReplaceSpendingLimit(t *testing.T) {
+	m, c := newTestManager(t)
+	m.maxReplacements = 3
+	// The gas limit is 100000, so the first replacement, which increases
+	// the max fee from 210 to 237, costs 2700000 wei more:
+	limit := &testLimit{limit: big.NewInt(3000000), reserved: big.NewInt(0)}
+	m.spendingLimits = func(address ethereum.Address) []SpendingLimit {
+		assert.Equal(t, testAddress, address)
+		return []SpendingLimit{limit}
+	}
+	sendTestTx(t, m, c)
+
+	c.On("TransactionReceipt", mock.Anything, mock.Anything).Return((*ethereum.Receipt)(nil), nil)
+	c.On("Nonce", mock.Anything, testAddress).Return(uint64(7), nil)
+	c.On("SuggestFees", mock.Anything, float64(0)).Return(&ethereum.Fees{BaseFee: big.NewInt(100), PriorityFee: big.NewInt(10)}, nil)
+
+	// The first replacement fits within the limit:
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&testHash2, nil).Once()
+	m.check(time.Now().Add(4 * time.Minute))
+	c.AssertNumberOfCalls(t, "SendTransaction", 2)
+	assert.Equal(t, big.NewInt(2700000), limit.reserved)
+	assert.Equal(t, uint64(1), m.Stats().Replaced)
+
+	// The next one would exceed it, so the transaction is not replaced:
+	m.check(time.Now().Add(8 * time.Minute))
+	c.AssertNumberOfCalls(t, "SendTransaction", 2)
+	assert.Equal(t, big.NewInt(2700000), limit.reserved)
+	assert.Equal(t, uint64(1), m.Stats().Replaced)
+	assert.Equal(t, StatusPending, m.Status(testHash1))
+}
+
+func TestBump(t *testing.T) {
+	assert.Equal(t, big.NewInt(113), bump(big.NewInt(100), 0.125))
+	assert.Equal(t, big.NewInt(112), bump(big.NewInt(100), 0.12))
+	assert.Equal(t, big.NewInt(0), bump(big.NewInt(0), 0.125))
+}
+
+type testNonces struct {
+	next     uint64
+	released []uint64
+	resets   int
+}
+
+func (n *testNonces) Next(context.Context, ethereum.Address) (uint64, error) {
+	n.next++
+	return n.next - 1, nil
+}
+
+func (n *testNonces) Release(_ ethereum.Address, nonce uint64) {
+	n.released = append(n.released, nonce)
+}
+
+func (n *testNonces) Reset(ethereum.Address) {
+	n.resets++
+}
+
+func TestManager_SendTransaction_Nonces(t *testing.T) {
+	m, c := newTestManager(t)
+	nonces := &testNonces{}
+	m.nonces = nonces
+
+	tx := &ethereum.Transaction{
+		Address:     testAddress,
+		PriorityFee: big.NewInt(1),
+		MaxFee:      big.NewInt(2),
+		GasLimit:    big.NewInt(100000),
+	}
+
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&testHash1, nil).Once()
+	_, err := m.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	// The first nonce of a fresh account is zero. It must be passed as set,
+	// so the client does not allocate another one:
+	sent := c.Calls[0].Arguments.Get(1).(*ethereum.Transaction)
+	require.NotNil(t, sent.Nonce)
+	assert.Equal(t, uint64(0), *sent.Nonce)
+	assert.Nil(t, tx.Nonce)
+
+	// The nonce is synchronized again after an error:
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return((*ethereum.Hash)(nil), errors.New("error")).Once()
+	_, err = m.SendTransaction(context.Background(), tx)
+	require.Error(t, err)
+	assert.Equal(t, 1, nonces.resets)
+	c.AssertNotCalled(t, "PendingNonce", mock.Anything, mock.Anything)
+}
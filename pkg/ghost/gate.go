@@ -0,0 +1,124 @@
+package ghost
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+// Names of quality gates.
+const (
+	GateMinSources      = "minSources"
+	GateMaxSourceSpread = "maxSourceSpread"
+	GateMaxSourceAge    = "maxSourceAge"
+	GateMaxJump         = "maxJump"
+)
+
+// ErrGateRejected is returned when a price does not pass a quality gate.
+type ErrGateRejected struct {
+	Gate string
+	Msg  string
+}
+
+func (e ErrGateRejected) Error() string {
+	return fmt.Sprintf("price rejected by the %s gate: %s", e.Gate, e.Msg)
+}
+
+// Gates describes quality requirements which a price must meet before it
+// is signed. Gates are checked against origin prices found in the price
+// trace. Gates with zero values are disabled.
+type Gates struct {
+	// MinSources is the minimum number of distinct origin prices without
+	// errors used to calculate the price.
+	MinSources int
+	// MaxSourceSpread is the maximum spread, in percent, between the lowest
+	// and the highest origin price of the same pair.
+	MaxSourceSpread float64
+	// MaxSourceAge is the maximum age of the oldest origin price.
+	MaxSourceAge time.Duration
+	// MaxJump is the maximum spread, in percent, between the previous price
+	// fetched for the pair, whether it was broadcast or not, and the new
+	// price. A sustained price move is rejected only once.
+	MaxJump float64
+}
+
+// check returns an ErrGateRejected error if the price does not pass any of
+// the gates. The last argument is the previous price fetched for the pair,
+// it is zero if no price was fetched yet.
+func (g Gates) check(tick *gofer.Price, last decimal.Decimal, now time.Time) error {
+	sources := originPrices(tick)
+	if g.MinSources > 0 && len(sources) < g.MinSources {
+		return ErrGateRejected{
+			Gate: GateMinSources,
+			Msg:  fmt.Sprintf("%d sources used, at least %d required", len(sources), g.MinSources),
+		}
+	}
+	if g.MaxSourceSpread > 0 {
+		lowest := map[gofer.Pair]decimal.Decimal{}
+		highest := map[gofer.Pair]decimal.Decimal{}
+		for _, s := range sources {
+			if l, ok := lowest[s.Pair]; !ok || s.Price.Cmp(l) < 0 {
+				lowest[s.Pair] = s.Price
+			}
+			if h, ok := highest[s.Pair]; !ok || s.Price.Cmp(h) > 0 {
+				highest[s.Pair] = s.Price
+			}
+		}
+		for pair, l := range lowest {
+			if spread := calcSpread(l, highest[pair]); spread > g.MaxSourceSpread {
+				return ErrGateRejected{
+					Gate: GateMaxSourceSpread,
+					Msg:  fmt.Sprintf("spread between %s sources is %.2f%%, at most %.2f%% allowed", pair, spread, g.MaxSourceSpread),
+				}
+			}
+		}
+	}
+	if g.MaxSourceAge > 0 {
+		for _, s := range sources {
+			if age := now.Sub(s.Time); age > g.MaxSourceAge {
+				return ErrGateRejected{
+					Gate: GateMaxSourceAge,
+					Msg:  fmt.Sprintf("%s price from %s is %s old, at most %s allowed", s.Pair, s.Parameters["origin"], age, g.MaxSourceAge),
+				}
+			}
+		}
+	}
+	if g.MaxJump > 0 && !last.IsZero() {
+		if jump := calcSpread(last, tick.Price); jump > g.MaxJump {
+			return ErrGateRejected{
+				Gate: GateMaxJump,
+				Msg:  fmt.Sprintf("price changed by %.2f%% since the previous check, at most %.2f%% allowed", jump, g.MaxJump),
+			}
+		}
+	}
+	return nil
+}
+
+// originPrices returns distinct origin prices without errors from
+// the price trace.
+func originPrices(tick *gofer.Price) []*gofer.Price {
+	type originKey struct {
+		origin string
+		pair   gofer.Pair
+	}
+	var prices []*gofer.Price
+	seen := map[originKey]bool{}
+	var walk func(p *gofer.Price)
+	walk = func(p *gofer.Price) {
+		if p.Type == "origin" {
+			k := originKey{origin: p.Parameters["origin"], pair: p.Pair}
+			if p.Error == "" && !seen[k] {
+				seen[k] = true
+				prices = append(prices, p)
+			}
+			return
+		}
+		for _, c := range p.Prices {
+			walk(c)
+		}
+	}
+	walk(tick)
+	return prices
+}
@@ -0,0 +1,72 @@
+package ghost
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+func testOriginPrice(origin string, price float64, t time.Time) *gofer.Price {
+	return &gofer.Price{
+		Type:       "origin",
+		Parameters: map[string]string{"origin": origin},
+		Pair:       testABPair,
+		Price:      decimal.NewFromFloat64(price),
+		Time:       t,
+	}
+}
+
+func testTrace(now time.Time) *gofer.Price {
+	p := testPrice(testABPair, 100)
+	p.Prices = []*gofer.Price{
+		testOriginPrice("a", 99, now.Add(-10*time.Second)),
+		testOriginPrice("b", 101, now.Add(-time.Minute)),
+		{
+			Type: "aggregator",
+			Pair: testABPair,
+			Prices: []*gofer.Price{
+				// Duplicated origin:
+				testOriginPrice("a", 99, now.Add(-10*time.Second)),
+				// Origin with an error:
+				{Type: "origin", Parameters: map[string]string{"origin": "c"}, Pair: testABPair, Error: "failed"},
+			},
+		},
+	}
+	return p
+}
+
+func TestGates_check(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		gates Gates
+		last  decimal.Decimal
+		gate  string
+	}{
+		{gates: Gates{}, gate: ""},
+		{gates: Gates{MinSources: 2}, gate: ""},
+		{gates: Gates{MinSources: 3}, gate: GateMinSources},
+		{gates: Gates{MaxSourceSpread: 3}, gate: ""},
+		{gates: Gates{MaxSourceSpread: 2}, gate: GateMaxSourceSpread},
+		{gates: Gates{MaxSourceAge: 2 * time.Minute}, gate: ""},
+		{gates: Gates{MaxSourceAge: 30 * time.Second}, gate: GateMaxSourceAge},
+		{gates: Gates{MaxJump: 5}, gate: ""},
+		{gates: Gates{MaxJump: 5}, last: decimal.NewFromInt(97), gate: ""},
+		{gates: Gates{MaxJump: 5}, last: decimal.NewFromInt(90), gate: GateMaxJump},
+	}
+	for n, tt := range tests {
+		err := tt.gates.check(testTrace(now), tt.last, now)
+		if tt.gate == "" {
+			assert.NoError(t, err, "test #%d", n)
+			continue
+		}
+		var gErr ErrGateRejected
+		if assert.True(t, errors.As(err, &gErr), "test #%d", n) {
+			assert.Equal(t, tt.gate, gErr.Gate, "test #%d", n)
+		}
+	}
+}
@@ -4,20 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/gofer/marshal"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ghost/journal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 )
 
 const LoggerTag = "GHOST"
 
+// DefaultSignWorkers is the default number of prices signed concurrently.
+// Signing may use a lot of memory, depending on the KDF used by the key
+// store, so the number is kept low.
+const DefaultSignWorkers = 4
+
 type ErrUnableToFindAsset struct {
 	AssetName string
 }
@@ -27,16 +37,24 @@ func (e ErrUnableToFindAsset) Error() string {
 }
 
 type Ghost struct {
-	ctx    context.Context
-	doneCh chan struct{}
+	ctx      context.Context
+	doneCh   chan struct{}
+	updateCh chan struct{}
+
+	gofer       gofer.Gofer
+	signer      ethereum.Signer
+	starkSigner stark.Signer
+	starkOracle string
+	transport   transport.Transport
+	interval    time.Duration
+	workers     int
+	configPairs []*Pair
+	journal     journal.Journal
+	log         log.Logger
 
-	gofer      gofer.Gofer
-	signer     ethereum.Signer
-	transport  transport.Transport
-	interval   time.Duration
-	pairs      []string
-	goferPairs map[gofer.Pair]string
-	log        log.Logger
+	mu         sync.RWMutex
+	pairs      map[string]*pairState
+	rejections map[string]uint64
 }
 
 type Config struct {
@@ -50,28 +68,87 @@ type Config struct {
 	// relayers.
 	Transport transport.Transport
 	// Interval describes how often we should send prices to the network.
+	// It is used for pairs which do not define their own interval.
 	Interval time.Duration
 	// Logger is a current logger interface used by the Ghost. The Logger
 	// helps to monitor asynchronous processes.
 	Logger log.Logger
-	// Pairs is a list supported pairs.
-	Pairs []string
+	// Pairs is the list of supported pairs with their configuration.
+	Pairs []*Pair
+	// SignWorkers is the maximum number of prices signed concurrently.
+	// If zero, the DefaultSignWorkers value is used.
+	SignWorkers int
+	// StarkSigner, if not nil, is used to additionally sign prices with
+	// the StarkWare signature, so they can be used by StarkEx and Starknet
+	// based protocols.
+	StarkSigner stark.Signer
+	// StarkOracleName is the oracle name included in the StarkWare
+	// signature. It is required if the StarkSigner is set.
+	StarkOracleName string
+	// Journal, if not nil, is used to record every signed price together
+	// with its trace and the broadcast result.
+	Journal journal.Journal
+}
+
+type Pair struct {
+	// AssetPair is the name of asset pair, e.g. ETHUSD.
+	AssetPair string
+	// Interval describes how often the price is checked. If zero, the
+	// Ghost's interval is used.
+	Interval time.Duration
+	// Spread is the minimum spread, in percent, between the last broadcast
+	// price and the new price required to broadcast the new price. If zero,
+	// the price is broadcast on every interval.
+	Spread float64
+	// Expiration is the heartbeat interval, the maximum amount of time after
+	// which the price is broadcast even if the spread is not reached. If
+	// zero, the price is broadcast only when the spread is reached.
+	Expiration time.Duration
+	// Gates are quality requirements which the price must meet before it
+	// is signed.
+	Gates Gates
+}
+
+// pairState contains the configuration of a pair and information about
+// the last broadcast price.
+type pairState struct {
+	Pair
+	goferPair gofer.Pair
+	lastCheck time.Time
+	lastPrice decimal.Decimal
+	lastTime  time.Time
+	// lastTick is the previous valid price fetched for the pair, whether
+	// it was broadcast or not. It is used by the MaxJump gate.
+	lastTick decimal.Decimal
 }
 
 func NewGhost(ctx context.Context, cfg Config) (*Ghost, error) {
 	if ctx == nil {
 		return nil, errors.New("context must not be nil")
 	}
+	if cfg.StarkSigner != nil && cfg.StarkOracleName == "" {
+		return nil, errors.New("stark oracle name must be set if the stark signer is used")
+	}
+	workers := cfg.SignWorkers
+	if workers <= 0 {
+		workers = DefaultSignWorkers
+	}
 	g := &Ghost{
-		ctx:        ctx,
-		doneCh:     make(chan struct{}),
-		gofer:      cfg.Gofer,
-		signer:     cfg.Signer,
-		transport:  cfg.Transport,
-		interval:   cfg.Interval,
-		pairs:      cfg.Pairs,
-		goferPairs: make(map[gofer.Pair]string),
-		log:        cfg.Logger.WithField("tag", LoggerTag),
+		ctx:         ctx,
+		doneCh:      make(chan struct{}),
+		updateCh:    make(chan struct{}, 1),
+		gofer:       cfg.Gofer,
+		signer:      cfg.Signer,
+		starkSigner: cfg.StarkSigner,
+		starkOracle: cfg.StarkOracleName,
+		transport:   cfg.Transport,
+		interval:    cfg.Interval,
+		workers:     workers,
+		configPairs: cfg.Pairs,
+		journal:     cfg.Journal,
+		pairs:       make(map[string]*pairState),
+		rejections:  make(map[string]uint64),
+		log:         cfg.Logger.WithField("tag", LoggerTag),
 	}
 	return g, nil
 }
@@ -79,28 +156,12 @@ func NewGhost(ctx context.Context, cfg Config) (*Ghost, error) {
 func (g *Ghost) Start() error {
 	g.log.Infof("Starting")
 
-	// Unfortunately, the Gofer stores pairs in the AAA/BBB format but Ghost
-	// (and oracle contract) stores them in AAABBB format. Because of this we
-	// need to make this wired mapping:
-	for _, pair := range g.pairs {
-		goferPairs, err := g.gofer.Pairs()
-		if err != nil {
-			return err
-		}
-		found := false
-		for _, goferPair := range goferPairs {
-			if goferPair.Base+goferPair.Quote == pair {
-				g.goferPairs[goferPair] = pair
-				found = true
-				break
-			}
-		}
-		if !found {
-			return ErrUnableToFindAsset{AssetName: pair}
-		}
+	err := g.SetPairs(g.configPairs)
+	if err != nil {
+		return err
 	}
 
-	err := g.broadcasterLoop()
+	err = g.broadcasterLoop()
 	if err != nil {
 		return err
 	}
@@ -113,90 +174,521 @@ func (g *Ghost) Wait() {
 	<-g.doneCh
 }
 
-// broadcast sends price for single pair to the network. This method uses
-// current price from the Gofer so it must be updated beforehand.
-func (g *Ghost) broadcast(goferPair gofer.Pair) error {
-	var err error
+// Once fetches, signs and broadcasts prices for all configured pairs in
+// a single round and returns its statistics. Because there is no last
+// broadcast price, every price is broadcast regardless of pair policies,
+// but it still has to pass quality gates. Once must not be used with
+// the Start method.
+func (g *Ghost) Once(ctx context.Context) (RoundStats, error) {
+	if err := g.SetPairs(g.configPairs); err != nil {
+		return RoundStats{}, err
+	}
+	return g.round(ctx, time.Now())
+}
 
-	pair := g.goferPairs[goferPair]
-	tick, err := g.gofer.Price(goferPair)
+// SetPairs replaces the list of pairs for which prices are broadcast. If
+// any of the pairs is not supported by the Gofer, an error is returned
+// and the current list is left unchanged. Pairs which are on both lists
+// keep the information about the last broadcast price. It is safe to call
+// this method while the Ghost is running.
+func (g *Ghost) SetPairs(pairs []*Pair) error {
+	goferPairs, err := g.gofer.Pairs()
 	if err != nil {
 		return err
 	}
-	if tick.Error != "" {
-		return errors.New(tick.Error)
+	names := make([]string, len(pairs))
+	for i, p := range pairs {
+		names[i] = p.AssetPair
 	}
-
-	// Create price:
-	price := &oracle.Price{Wat: pair, Age: tick.Time}
-	price.SetFloat64Price(tick.Price)
-
-	// Sign price:
-	err = price.Sign(g.signer)
+	m, err := MatchPairs(names, goferPairs)
 	if err != nil {
 		return err
 	}
+	states := make(map[string]*pairState, len(pairs))
+	for goferPair, name := range m {
+		for _, p := range pairs {
+			if p.AssetPair == name {
+				states[name] = &pairState{Pair: *p, goferPair: goferPair}
+			}
+		}
+	}
+	g.mu.Lock()
+	for name, s := range states {
+		if prev, ok := g.pairs[name]; ok {
+			s.lastCheck = prev.lastCheck
+			s.lastPrice = prev.lastPrice
+			s.lastTime = prev.lastTime
+			s.lastTick = prev.lastTick
+		}
+	}
+	g.pairs = states
+	g.mu.Unlock()
 
-	// Broadcast price to P2P network:
-	message, err := createPriceMessage(price, tick)
-	if err != nil {
-		return err
+	// Intervals may have changed:
+	select {
+	case g.updateCh <- struct{}{}:
+	default:
 	}
-	err = g.transport.Broadcast(messages.PriceMessageName, message)
-	if err != nil {
-		return err
+	return nil
+}
+
+// MatchPairs maps Gofer pairs to the given pairs.
+//
+// Unfortunately, the Gofer stores pairs in the AAA/BBB format but Ghost
+// (and oracle contract) stores them in AAABBB format. Because of this we
+// need to make this wired mapping.
+func MatchPairs(pairs []string, goferPairs []gofer.Pair) (map[gofer.Pair]string, error) {
+	m := make(map[gofer.Pair]string)
+	for _, pair := range pairs {
+		found := false
+		for _, goferPair := range goferPairs {
+			if goferPair.Base+goferPair.Quote == pair {
+				m[goferPair] = pair
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrUnableToFindAsset{AssetName: pair}
+		}
 	}
+	return m, nil
+}
 
-	return err
+// Rejections returns the number of prices rejected by each quality gate
+// since the Ghost was created. The numbers are also logged after every
+// round.
+func (g *Ghost) Rejections() map[string]uint64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	r := make(map[string]uint64, len(g.rejections))
+	for gate, n := range g.rejections {
+		r[gate] = n
+	}
+	return r
+}
+
+// RoundStats contains statistics of a single broadcast round.
+type RoundStats struct {
+	// Pairs is the number of pairs for which prices were requested.
+	Pairs int
+	// Broadcast is the number of successfully broadcast prices.
+	Broadcast int
+	// Skipped is the number of prices which were not broadcast because
+	// neither the spread was reached nor the last price expired.
+	Skipped int
+	// Rejected is the number of prices which did not pass quality gates.
+	Rejected int
+	// FetchTime is the time spent on fetching prices from the Gofer.
+	FetchTime time.Duration
+	// SignTime is the time spent on signing prices.
+	SignTime time.Duration
+	// BroadcastTime is the time spent on sending prices to the network.
+	BroadcastTime time.Duration
+}
+
+// signedPrice is a price prepared to be broadcast.
+type signedPrice struct {
+	assetPair string
+	goferPair gofer.Pair
+	reason    string
+	price     *oracle.Price
+	tick      *gofer.Price
+	err       error
 }
 
 // broadcasterLoop creates a asynchronous loop which fetches prices from exchanges and then
-// sends them to the network at a specified interval.
+// sends them to the network. The loop ticks as often as the GCD of all
+// pair intervals and on every tick only pairs whose interval has elapsed
+// are checked.
 func (g *Ghost) broadcasterLoop() error {
 	if g.interval == 0 {
 		return nil
 	}
 
-	ticker := time.NewTicker(g.interval)
-	wg := sync.WaitGroup{}
+	tick := g.tickInterval()
+	ticker := time.NewTicker(tick)
 	go func() {
 		for {
 			select {
 			case <-g.doneCh:
 				ticker.Stop()
 				return
+			case <-g.updateCh:
+				tick = g.tickInterval()
+				ticker.Reset(tick)
 			case <-ticker.C:
-				// TODO: fetch all prices before broadcast is called
-
-				// Send prices to the network:
-				//
-				// Signing may be slow, especially with high KDF so this is why
-				// we're using goroutines here.
-				wg.Add(1)
-				go func() {
-					for assetPair := range g.goferPairs {
-						err := g.broadcast(assetPair)
-						if err != nil {
-							g.log.
-								WithFields(log.Fields{"assetPair": assetPair}).
-								WithError(err).
-								Warn("Unable to broadcast price")
-						} else {
-							g.log.
-								WithFields(log.Fields{"assetPair": assetPair}).
-								Info("Price broadcast")
-						}
-					}
-					wg.Done()
-				}()
+				// Half of the tick is added to the current time to check
+				// pairs which would be due before the next tick, otherwise
+				// small delays of the ticker could cause skipping a tick:
+				due := time.Now().Add(tick / 2)
+				// The tick may be much shorter than pair intervals, so the
+				// round may last as long as the shortest interval of pairs
+				// which are due:
+				timeout := g.roundTimeout(due)
+				if timeout == 0 {
+					continue
+				}
+				ctx, ctxCancel := context.WithTimeout(g.ctx, timeout)
+				stats, err := g.round(ctx, due)
+				ctxCancel()
+				if stats.Pairs == 0 && err == nil {
+					continue
+				}
+				fields := log.Fields{
+					"rejections":    g.Rejections(),
+					"pairs":         stats.Pairs,
+					"broadcast":     stats.Broadcast,
+					"skipped":       stats.Skipped,
+					"rejected":      stats.Rejected,
+					"fetchTime":     stats.FetchTime.String(),
+					"signTime":      stats.SignTime.String(),
+					"broadcastTime": stats.BroadcastTime.String(),
+				}
+				if err != nil {
+					g.log.WithFields(fields).WithError(err).Warn("Broadcast round failed")
+				} else {
+					g.log.WithFields(fields).Info("Broadcast round finished")
+				}
 			}
-			wg.Wait()
 		}
 	}()
 
 	return nil
 }
 
+// tickInterval returns the GCD of intervals of all pairs.
+func (g *Ghost) tickInterval() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	tick := g.interval
+	for _, s := range g.pairs {
+		a, b := tick, g.pairInterval(s)
+		for b != 0 {
+			a, b = b, a%b
+		}
+		tick = a
+	}
+	if tick < time.Second {
+		tick = time.Second
+	}
+	return tick
+}
+
+// roundTimeout returns the shortest interval of pairs which are due at
+// the given time, or zero if there are no such pairs.
+func (g *Ghost) roundTimeout(due time.Time) time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var timeout time.Duration
+	for _, s := range g.pairs {
+		interval := g.pairInterval(s)
+		if s.lastCheck.Add(interval).After(due) {
+			continue
+		}
+		if timeout == 0 || interval < timeout {
+			timeout = interval
+		}
+	}
+	return timeout
+}
+
+func (g *Ghost) pairInterval(s *pairState) time.Duration {
+	if s.Interval > 0 {
+		return s.Interval
+	}
+	return g.interval
+}
+
+// round fetches prices for all pairs which are due at the given time using
+// a single Gofer call, signs prices which need to be broadcast using a pool
+// of workers, and then sends them to the network. If the context is
+// cancelled, the round is interrupted and remaining prices are not
+// broadcast. Pairs are considered checked only if their price was
+// broadcast, skipped or rejected, so pairs which failed are checked again
+// in the next round.
+func (g *Ghost) round(ctx context.Context, due time.Time) (RoundStats, error) {
+	var stats RoundStats
+
+	now := time.Now()
+	g.mu.RLock()
+	var states []pairState
+	for _, s := range g.pairs {
+		if s.lastCheck.Add(g.pairInterval(s)).After(due) {
+			continue
+		}
+		states = append(states, *s)
+	}
+	g.mu.RUnlock()
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].AssetPair < states[j].AssetPair
+	})
+	stats.Pairs = len(states)
+	if len(states) == 0 {
+		return stats, nil
+	}
+
+	// Fetch prices:
+	t := time.Now()
+	goferPairs := make([]gofer.Pair, len(states))
+	for i, s := range states {
+		goferPairs[i] = s.goferPair
+	}
+	ticks, err := g.fetch(ctx, goferPairs)
+	stats.FetchTime = time.Since(t)
+	if err != nil {
+		return stats, err
+	}
+
+	// Check which prices need to be broadcast:
+	var prices []*signedPrice
+	for _, s := range states {
+		tick := ticks[s.goferPair]
+		reason := broadcastReason(s, tick, now)
+		if reason == "" {
+			g.checked(s.AssetPair, now)
+			g.observed(s.AssetPair, tick)
+			stats.Skipped++
+			g.log.
+				WithFields(log.Fields{"assetPair": s.goferPair}).
+				Debug("Price broadcast skipped")
+			continue
+		}
+		if reason != "error" {
+			last := s.lastTick
+			if last.IsZero() {
+				last = s.lastPrice
+			}
+			if err := s.Gates.check(tick, last, now); err != nil {
+				g.checked(s.AssetPair, now)
+				stats.Rejected++
+				fields := log.Fields{"assetPair": s.goferPair}
+				var gErr ErrGateRejected
+				if errors.As(err, &gErr) {
+					fields["gate"] = gErr.Gate
+					g.mu.Lock()
+					g.rejections[gErr.Gate]++
+					g.mu.Unlock()
+					// A price which differs only by a jump becomes the new
+					// reference, so a sustained move is rejected only once:
+					if gErr.Gate == GateMaxJump {
+						g.observed(s.AssetPair, tick)
+					}
+				}
+				g.log.WithFields(fields).WithError(err).Warn("Price rejected")
+				continue
+			}
+			g.observed(s.AssetPair, tick)
+		}
+		prices = append(prices, &signedPrice{
+			assetPair: s.AssetPair,
+			goferPair: s.goferPair,
+			reason:    reason,
+			tick:      tick,
+		})
+	}
+
+	// Sign prices:
+	t = time.Now()
+	g.sign(ctx, prices)
+	stats.SignTime = time.Since(t)
+
+	// Broadcast prices to the P2P network:
+	t = time.Now()
+	for _, p := range prices {
+		if p.err == nil {
+			p.err = ctx.Err()
+		}
+		if p.err == nil {
+			p.err = g.broadcast(p)
+		}
+		if p.err != nil {
+			g.log.
+				WithFields(log.Fields{"assetPair": p.goferPair}).
+				WithError(p.err).
+				Warn("Unable to broadcast price")
+			continue
+		}
+		g.mu.Lock()
+		if s, ok := g.pairs[p.assetPair]; ok {
+			s.lastCheck = now
+			s.lastPrice = p.tick.Price
+			s.lastTime = now
+		}
+		g.mu.Unlock()
+		stats.Broadcast++
+		g.log.
+			WithFields(log.Fields{"assetPair": p.goferPair, "reason": p.reason}).
+			Info("Price broadcast")
+	}
+	stats.BroadcastTime = time.Since(t)
+
+	g.record(now, prices)
+
+	return stats, ctx.Err()
+}
+
+// checked sets the time of the last check of the pair.
+func (g *Ghost) checked(assetPair string, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.pairs[assetPair]; ok {
+		s.lastCheck = now
+	}
+}
+
+// observed sets the previous valid price fetched for the pair.
+func (g *Ghost) observed(assetPair string, tick *gofer.Price) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.pairs[assetPair]; ok {
+		s.lastTick = tick.Price
+	}
+}
+
+// record adds signed prices to the journal. Prices which could not be
+// signed are not recorded.
+func (g *Ghost) record(now time.Time, prices []*signedPrice) {
+	if g.journal == nil {
+		return
+	}
+	var entries []journal.Entry
+	for _, p := range prices {
+		if p.price == nil {
+			continue
+		}
+		e := journal.Entry{
+			Time:      now,
+			Signer:    g.signer.Address(),
+			Price:     p.price,
+			Reason:    p.reason,
+			Broadcast: p.err == nil,
+		}
+		if p.err != nil {
+			e.Error = p.err.Error()
+		}
+		if trace, err := marshal.Marshall(marshal.JSON, p.tick); err == nil {
+			e.Trace = trace
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if err := g.journal.Add(entries...); err != nil {
+		g.log.WithError(err).Warn("Unable to record prices in the journal")
+	}
+}
+
+// broadcastReason returns the reason why the price has to be broadcast or
+// an empty string if there is no need to broadcast it. Invalid prices are
+// always passed on, so the error is reported.
+func broadcastReason(s pairState, tick *gofer.Price, now time.Time) string {
+	switch {
+	case tick == nil || tick.Error != "":
+		return "error"
+	case s.lastTime.IsZero():
+		return "initial"
+	case s.Spread == 0:
+		return "interval"
+	case s.Expiration > 0 && !now.Before(s.lastTime.Add(s.Expiration)):
+		return "heartbeat"
+	case calcSpread(s.lastPrice, tick.Price) >= s.Spread:
+		return "deviation"
+	}
+	return ""
+}
+
+// calcSpread returns the spread between two prices in percent.
+func calcSpread(prev, curr decimal.Decimal) float64 {
+	if prev.IsZero() {
+		return math.Inf(1)
+	}
+	return curr.Sub(prev).Div(prev).Abs().Float64() * 100
+}
+
+// fetch returns prices for all given pairs. The Gofer does not support
+// cancellation, so if the context is cancelled before prices are fetched,
+// the result is discarded.
+func (g *Ghost) fetch(ctx context.Context, goferPairs []gofer.Pair) (map[gofer.Pair]*gofer.Price, error) {
+	type result struct {
+		ticks map[gofer.Pair]*gofer.Price
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		ticks, err := g.gofer.Prices(goferPairs...)
+		ch <- result{ticks: ticks, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-ch:
+		return r.ticks, r.err
+	}
+}
+
+// sign creates and signs the given prices. Signing may be slow, especially
+// with high KDF, so prices are signed by a pool of workers.
+func (g *Ghost) sign(ctx context.Context, prices []*signedPrice) {
+	workers := g.workers
+	if workers > len(prices) {
+		workers = len(prices)
+	}
+	ch := make(chan *signedPrice)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for p := range ch {
+				if err := ctx.Err(); err != nil {
+					p.err = err
+					continue
+				}
+				p.price, p.err = g.signPrice(p.assetPair, p.tick)
+			}
+		}()
+	}
+	for _, p := range prices {
+		ch <- p
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// signPrice creates an oracle price from the Gofer price and signs it. If
+// the StarkWare signer is configured, the price is signed with both
+// signers.
+func (g *Ghost) signPrice(pair string, tick *gofer.Price) (*oracle.Price, error) {
+	if tick == nil {
+		return nil, errors.New("price is missing")
+	}
+	if tick.Error != "" {
+		return nil, errors.New(tick.Error)
+	}
+	price := &oracle.Price{Wat: pair, Age: tick.Time}
+	price.SetDecimalPrice(tick.Price)
+	if err := price.Sign(g.signer); err != nil {
+		return nil, err
+	}
+	if g.starkSigner != nil {
+		if err := price.StarkSign(g.starkSigner, g.starkOracle); err != nil {
+			return nil, fmt.Errorf("unable to create StarkWare signature: %w", err)
+		}
+	}
+	return price, nil
+}
+
+// broadcast sends a signed price to the network.
+func (g *Ghost) broadcast(p *signedPrice) error {
+	message, err := createPriceMessage(p.price, p.tick)
+	if err != nil {
+		return err
+	}
+	return g.transport.Broadcast(messages.PriceMessageName, message)
+}
+
 func (g *Ghost) contextCancelHandler() {
 	defer func() { close(g.doneCh) }()
 	defer g.log.Info("Stopped")
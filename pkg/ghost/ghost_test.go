@@ -0,0 +1,357 @@
+package ghost
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	ethereumMocks "github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/ghost/journal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	goferMocks "github.com/toknowwhy/theunit-oracle/pkg/gofer/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/local"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+var (
+	testABPair = gofer.Pair{Base: "AAA", Quote: "BBB"}
+	testXYPair = gofer.Pair{Base: "XXX", Quote: "YYY"}
+)
+
+func newTestGhost(t *testing.T, gof *goferMocks.Gofer, pairs ...*Pair) (*Ghost, *local.Local) {
+	if len(pairs) == 0 {
+		pairs = []*Pair{{AssetPair: "AAABBB"}, {AssetPair: "XXXYYY"}}
+	}
+	sig := &ethereumMocks.Signer{}
+	sig.On("Signature", mock.Anything).Return(ethereum.SignatureFromBytes(make([]byte, 65)), nil)
+	tra := local.New(context.Background(), 10, map[string]transport.Message{
+		messages.PriceMessageName: (*messages.Price)(nil),
+	})
+	gof.On("Pairs").Return([]gofer.Pair{testABPair, testXYPair}, nil)
+	g, err := NewGhost(context.Background(), Config{
+		Gofer:     gof,
+		Signer:    sig,
+		Transport: tra,
+		Interval:  time.Second,
+		Logger:    null.New(),
+		Pairs:     pairs,
+	})
+	require.NoError(t, err)
+	require.NoError(t, g.SetPairs(pairs))
+	return g, tra
+}
+
+func testPrice(pair gofer.Pair, price float64) *gofer.Price {
+	return &gofer.Price{
+		Type:  "aggregator",
+		Pair:  pair,
+		Price: decimal.NewFromFloat64(price),
+		Time:  time.Unix(1000, 0),
+	}
+}
+
+func TestGhost_round(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, tra := newTestGhost(t, gof)
+
+	// Prices for all pairs must be fetched at once:
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+		testXYPair: testPrice(testXYPair, 20),
+	}, nil).Once()
+
+	stats, err := g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Pairs)
+	assert.Equal(t, 2, stats.Broadcast)
+	gof.AssertExpectations(t)
+
+	var wats []string
+	for i := 0; i < 2; i++ {
+		msg := <-tra.Messages(messages.PriceMessageName)
+		require.NoError(t, msg.Error)
+		wats = append(wats, msg.Message.(*messages.Price).Price.Wat)
+	}
+	assert.ElementsMatch(t, []string{"AAABBB", "XXXYYY"}, wats)
+}
+
+func TestGhost_round_PriceError(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(t, gof)
+
+	xy := testPrice(testXYPair, 20)
+	xy.Error = "something went wrong"
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+		testXYPair: xy,
+	}, nil).Once()
+
+	stats, err := g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Pairs)
+	assert.Equal(t, 1, stats.Broadcast)
+}
+
+func TestGhost_round_Deadline(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(t, gof)
+
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+		testXYPair: testPrice(testXYPair, 20),
+	}, nil).After(time.Second).Once()
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer ctxCancel()
+
+	t0 := time.Now()
+	stats, err := g.round(ctx, time.Now())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, stats.Broadcast)
+	assert.Less(t, int64(time.Since(t0)), int64(500*time.Millisecond))
+}
+
+func TestGhost_round_Policies(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(
+		t,
+		gof,
+		&Pair{AssetPair: "AAABBB", Spread: 1, Expiration: time.Hour},
+		&Pair{AssetPair: "XXXYYY", Interval: time.Hour},
+	)
+
+	// The first price is always broadcast:
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 100),
+		testXYPair: testPrice(testXYPair, 20),
+	}, nil).Once()
+	stats, err := g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Broadcast)
+
+	// The AAABBB pair uses the 1s Ghost interval:
+	due := time.Now().Add(2 * time.Second)
+
+	// The XXXYYY interval did not elapse yet, the AAABBB price changed less
+	// than the spread:
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 100.5),
+	}, nil).Once()
+	stats, err = g.round(context.Background(), due)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Pairs)
+	assert.Equal(t, 0, stats.Broadcast)
+	assert.Equal(t, 1, stats.Skipped)
+
+	// The spread is reached:
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 101),
+	}, nil).Once()
+	stats, err = g.round(context.Background(), due)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Broadcast)
+
+	// The heartbeat interval elapsed:
+	g.pairs["AAABBB"].lastTime = time.Now().Add(-2 * time.Hour)
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 101),
+	}, nil).Once()
+	stats, err = g.round(context.Background(), due)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Broadcast)
+	gof.AssertExpectations(t)
+}
+
+func TestGhost_tickInterval(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(
+		t,
+		gof,
+		&Pair{AssetPair: "AAABBB", Interval: 20 * time.Second},
+		&Pair{AssetPair: "XXXYYY", Interval: 30 * time.Second},
+	)
+	assert.Equal(t, time.Second, g.tickInterval())
+
+	g.interval = time.Minute
+	assert.Equal(t, 10*time.Second, g.tickInterval())
+}
+
+func TestGhost_roundTimeout(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(
+		t,
+		gof,
+		&Pair{AssetPair: "AAABBB", Interval: 60 * time.Second},
+		&Pair{AssetPair: "XXXYYY", Interval: 45 * time.Second},
+	)
+	now := time.Now()
+
+	// The tick is 1s, but the round may last as long as the shortest
+	// interval of due pairs:
+	assert.Equal(t, 45*time.Second, g.roundTimeout(now))
+
+	g.pairs["XXXYYY"].lastCheck = now
+	assert.Equal(t, 60*time.Second, g.roundTimeout(now))
+
+	g.pairs["AAABBB"].lastCheck = now
+	assert.Equal(t, time.Duration(0), g.roundTimeout(now))
+}
+
+func TestGhost_round_RetryAfterError(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(t, gof, &Pair{AssetPair: "AAABBB", Interval: time.Hour})
+
+	// Pairs are not marked as checked if prices could not be fetched:
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price(nil), errors.New("error")).Once()
+	_, err := g.round(context.Background(), time.Now())
+	require.Error(t, err)
+	assert.True(t, g.pairs["AAABBB"].lastCheck.IsZero())
+
+	// So they are retried in the next round:
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+	}, nil).Once()
+	stats, err := g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Broadcast)
+	assert.False(t, g.pairs["AAABBB"].lastCheck.IsZero())
+
+	// And then they wait for the next interval:
+	stats, err = g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Pairs)
+	gof.AssertExpectations(t)
+}
+
+func Test_calcSpread(t *testing.T) {
+	assert.Equal(t, 10.0, calcSpread(decimal.NewFromInt(100), decimal.NewFromInt(110)))
+	assert.Equal(t, 10.0, calcSpread(decimal.NewFromInt(100), decimal.NewFromInt(90)))
+	assert.True(t, math.IsInf(calcSpread(decimal.NewFromInt(0), decimal.NewFromInt(1)), 1))
+}
+
+func TestGhost_round_Gates(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(
+		t,
+		gof,
+		&Pair{AssetPair: "AAABBB", Gates: Gates{MinSources: 3}},
+		&Pair{AssetPair: "XXXYYY"},
+	)
+
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testTrace(time.Now()),
+		testXYPair: testPrice(testXYPair, 20),
+	}, nil).Once()
+
+	stats, err := g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Broadcast)
+	assert.Equal(t, 1, stats.Rejected)
+	assert.Equal(t, map[string]uint64{GateMinSources: 1}, g.Rejections())
+}
+
+func TestGhost_round_MaxJumpRecovery(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(t, gof, &Pair{AssetPair: "AAABBB", Gates: Gates{MaxJump: 5}})
+	round := func(price float64, n int) RoundStats {
+		gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+			testABPair: testPrice(testABPair, price),
+		}, nil).Once()
+		stats, err := g.round(context.Background(), time.Now().Add(time.Duration(n)*time.Minute))
+		require.NoError(t, err)
+		return stats
+	}
+
+	assert.Equal(t, 1, round(100, 0).Broadcast)
+
+	// The market moves by more than the max jump, only the first price
+	// after the move is rejected:
+	assert.Equal(t, 1, round(150, 1).Rejected)
+	assert.Equal(t, 1, round(151, 2).Broadcast)
+	assert.Equal(t, 1, round(152, 3).Broadcast)
+	assert.Equal(t, map[string]uint64{GateMaxJump: 1}, g.Rejections())
+}
+
+func TestGhost_round_Journal(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, _ := newTestGhost(t, gof)
+
+	addr := ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	g.signer.(*ethereumMocks.Signer).On("Address").Return(addr)
+	j, err := journal.NewFileJournal(journal.FileJournalConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+	g.journal = j
+
+	xy := testPrice(testXYPair, 20)
+	xy.Error = "something went wrong"
+	gof.On("Prices", testABPair, testXYPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+		testXYPair: xy,
+	}, nil).Once()
+
+	_, err = g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	// Only signed prices are recorded:
+	es, err := j.Query(journal.Query{})
+	require.NoError(t, err)
+	require.Len(t, es, 1)
+	assert.Equal(t, "AAABBB", es[0].Price.Wat)
+	assert.Equal(t, addr, es[0].Signer)
+	assert.Equal(t, "initial", es[0].Reason)
+	assert.True(t, es[0].Broadcast)
+	assert.NotEmpty(t, es[0].Trace)
+}
+
+func TestGhost_Once(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, tra := newTestGhost(t, gof, &Pair{AssetPair: "AAABBB", Interval: time.Hour, Spread: 10})
+
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+	}, nil).Once()
+
+	stats, err := g.Once(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Pairs)
+	assert.Equal(t, 1, stats.Broadcast)
+
+	msg := <-tra.Messages(messages.PriceMessageName)
+	require.NoError(t, msg.Error)
+	assert.Equal(t, "AAABBB", msg.Message.(*messages.Price).Price.Wat)
+}
+
+func TestGhost_round_Stark(t *testing.T) {
+	gof := &goferMocks.Gofer{}
+	g, tra := newTestGhost(t, gof, &Pair{AssetPair: "AAABBB"})
+
+	ss, err := stark.NewSigner(big.NewInt(42))
+	require.NoError(t, err)
+	g.starkSigner = ss
+	g.starkOracle = "Test"
+
+	gof.On("Prices", testABPair).Return(map[gofer.Pair]*gofer.Price{
+		testABPair: testPrice(testABPair, 10),
+	}, nil).Once()
+
+	_, err = g.round(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	msg := <-tra.Messages(messages.PriceMessageName)
+	require.NoError(t, msg.Error)
+	price := msg.Message.(*messages.Price).Price
+	assert.Equal(t, ss.PublicKey().Bytes(), price.StarkPK)
+	assert.NoError(t, price.StarkVerify("Test"))
+}
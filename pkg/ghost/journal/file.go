@@ -0,0 +1,197 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	currentName   = "journal.ndjson"
+	segmentPrefix = "journal-"
+	segmentSuffix = ".ndjson"
+)
+
+// segmentTimeLayout is used in names of rotated files. It is sortable and
+// does not contain characters which are not allowed in file names.
+const segmentTimeLayout = "20060102T150405.000000000Z"
+
+// FileJournalConfig is the configuration for the FileJournal.
+type FileJournalConfig struct {
+	// Path is a directory in which journal files are stored. It is created
+	// if it does not exist.
+	Path string
+	// MaxSize is the maximum size of a single journal file in bytes. When
+	// the current file exceeds this size, it is rotated. Rotated files are
+	// never removed. Zero means that files are never rotated.
+	MaxSize int64
+}
+
+// FileJournal implements the Journal interface. Entries are appended to the
+// journal.ndjson file in the NDJSON format. When the file reaches
+// the maximum size, it is renamed to journal-TIME.ndjson, where TIME is
+// the time of rotation, and a new file is created.
+type FileJournal struct {
+	mu sync.Mutex
+
+	path    string
+	maxSize int64
+}
+
+// NewFileJournal returns a new FileJournal instance.
+func NewFileJournal(cfg FileJournalConfig) (*FileJournal, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("journal path must not be empty")
+	}
+	if cfg.MaxSize < 0 {
+		return nil, errors.New("journal max size must not be negative")
+	}
+	if err := os.MkdirAll(cfg.Path, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create journal directory: %w", err)
+	}
+	return &FileJournal{path: cfg.Path, maxSize: cfg.MaxSize}, nil
+}
+
+// Add implements the Journal interface.
+func (f *FileJournal) Add(entries ...Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	for _, e := range entries {
+		e.Time = e.Time.UTC()
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	if err := f.rotate(time.Now()); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(f.path, currentName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(buf.Bytes()); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Query implements the Journal interface.
+func (f *FileJournal) Query(q Query) ([]Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names, err := f.files()
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, name := range names {
+		es, err := f.readFile(name, q)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, es...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// rotate renames the current file if it exceeds the maximum size.
+func (f *FileJournal) rotate(now time.Time) error {
+	if f.maxSize == 0 {
+		return nil
+	}
+	current := filepath.Join(f.path, currentName)
+	info, err := os.Stat(current)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < f.maxSize {
+		return nil
+	}
+	rotated := filepath.Join(f.path, segmentPrefix+now.UTC().Format(segmentTimeLayout)+segmentSuffix)
+	if _, err := os.Stat(rotated); err == nil {
+		return fmt.Errorf("unable to rotate journal, the %s file already exists", rotated)
+	}
+	return os.Rename(current, rotated)
+}
+
+// files returns names of journal files, rotated files first, ordered by
+// the time of rotation.
+func (f *FileJournal) files() ([]string, error) {
+	dirEntries, err := os.ReadDir(f.path)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	current := false
+	for _, e := range dirEntries {
+		switch {
+		case e.IsDir():
+		case e.Name() == currentName:
+			current = true
+		case strings.HasPrefix(e.Name(), segmentPrefix) && strings.HasSuffix(e.Name(), segmentSuffix):
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	if current {
+		names = append(names, currentName)
+	}
+	return names, nil
+}
+
+func (f *FileJournal) readFile(name string, q Query) ([]Entry, error) {
+	file, err := os.Open(filepath.Join(f.path, name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Decode(file, q)
+}
+
+// Decode reads entries in the NDJSON format from the reader and returns
+// those matching the query, in the order in which they were read. Invalid
+// lines are skipped.
+func Decode(r io.Reader, q Query) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		// The last line may be incomplete if the file is being written
+		// at the same time:
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if q.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
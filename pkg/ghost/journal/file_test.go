@@ -0,0 +1,96 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileJournal_AddQuery(t *testing.T) {
+	signer := newTestSigner(t)
+	j, err := NewFileJournal(FileJournalConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, j.Add(
+		testEntry(t, signer, "BTCUSD", 42000, time.Unix(200, 0)),
+		testEntry(t, signer, "ETHUSD", 3000, time.Unix(100, 0)),
+	))
+	require.NoError(t, j.Add(testEntry(t, signer, "BTCUSD", 43000, time.Unix(300, 0))))
+
+	// All entries, ordered by time:
+	es, err := j.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, es, 3)
+	assert.Equal(t, "ETHUSD", es[0].Price.Wat)
+	assert.Equal(t, "BTCUSD", es[1].Price.Wat)
+	assert.Equal(t, "BTCUSD", es[2].Price.Wat)
+	assert.Equal(t, signer.Address(), es[0].Signer)
+	assert.JSONEq(t, `{"type":"aggregator"}`, string(es[0].Trace))
+
+	// Signatures must survive the round trip:
+	for _, e := range es {
+		assert.NoError(t, Verify(e, signer))
+	}
+
+	// Filtered entries:
+	es, err = j.Query(Query{AssetPair: "BTCUSD", From: time.Unix(250, 0)})
+	require.NoError(t, err)
+	require.Len(t, es, 1)
+	assert.Equal(t, float64(43000), es[0].Price.Float64Price())
+}
+
+func TestFileJournal_Rotate(t *testing.T) {
+	signer := newTestSigner(t)
+	dir := t.TempDir()
+	j, err := NewFileJournal(FileJournalConfig{Path: dir, MaxSize: 1})
+	require.NoError(t, err)
+
+	// Every entry exceeds the max size, so the file is rotated before
+	// every write:
+	for i := 0; i < 3; i++ {
+		require.NoError(t, j.Add(testEntry(t, signer, "BTCUSD", float64(i), time.Unix(int64(i), 0))))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "journal-*.ndjson"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	_, err = os.Stat(filepath.Join(dir, "journal.ndjson"))
+	assert.NoError(t, err)
+
+	es, err := j.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, es, 3)
+	for i, e := range es {
+		assert.Equal(t, float64(i), e.Price.Float64Price())
+	}
+}
+
+func TestFileJournal_Query_InvalidLines(t *testing.T) {
+	signer := newTestSigner(t)
+	dir := t.TempDir()
+	j, err := NewFileJournal(FileJournalConfig{Path: dir})
+	require.NoError(t, err)
+	require.NoError(t, j.Add(testEntry(t, signer, "BTCUSD", 1, time.Unix(100, 0))))
+
+	// Simulate an incomplete write:
+	f, err := os.OpenFile(filepath.Join(dir, "journal.ndjson"), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	es, err := j.Query(Query{})
+	require.NoError(t, err)
+	assert.Len(t, es, 1)
+}
+
+func TestNewFileJournal_Invalid(t *testing.T) {
+	_, err := NewFileJournal(FileJournalConfig{})
+	assert.Error(t, err)
+	_, err = NewFileJournal(FileJournalConfig{Path: t.TempDir(), MaxSize: -1})
+	assert.Error(t, err)
+}
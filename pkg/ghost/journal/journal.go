@@ -0,0 +1,92 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+// ErrSignerMismatch is returned by the Verify function when the signature
+// of a price is valid, but it was created by a different address.
+type ErrSignerMismatch struct {
+	Expected  ethereum.Address
+	Recovered ethereum.Address
+}
+
+func (e ErrSignerMismatch) Error() string {
+	return fmt.Sprintf("price was signed by %s, but %s was recorded as a signer", e.Recovered, e.Expected)
+}
+
+// Entry is a single signed price recorded in the journal.
+type Entry struct {
+	// Time is the time when the price was signed.
+	Time time.Time `json:"time"`
+	// Signer is the address used to sign the price.
+	Signer ethereum.Address `json:"signer"`
+	// Price is the signed price.
+	Price *oracle.Price `json:"price"`
+	// Trace is the Gofer price, in the JSON format, from which the price
+	// was created. It is the same trace as sent with the price message.
+	Trace json.RawMessage `json:"trace"`
+	// Reason describes why the price was broadcast, e.g. "heartbeat".
+	Reason string `json:"reason"`
+	// Broadcast is true if the price was successfully sent to the network.
+	Broadcast bool `json:"broadcast"`
+	// Error is the broadcast error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Query describes which entries should be returned by the Journal.Query
+// method.
+type Query struct {
+	// AssetPair is the name of the pair, e.g. BTCUSD. If empty, entries for
+	// all pairs are returned.
+	AssetPair string
+	// From and To define an inclusive time range. A zero value means that
+	// the range is not limited on that side.
+	From time.Time
+	To   time.Time
+}
+
+// Match returns true if the entry matches the query.
+func (q Query) Match(e Entry) bool {
+	if q.AssetPair != "" && (e.Price == nil || e.Price.Wat != q.AssetPair) {
+		return false
+	}
+	if !q.From.IsZero() && e.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && e.Time.After(q.To) {
+		return false
+	}
+	return true
+}
+
+// Journal is an append-only log of signed prices.
+type Journal interface {
+	// Add appends entries to the journal.
+	Add(entries ...Entry) error
+	// Query returns all entries matching the query ordered by time.
+	Query(q Query) ([]Entry, error)
+}
+
+// Verify checks if the price signature in the entry is valid and if it was
+// created by the recorded signer. The signer is only used to recover
+// addresses, so it does not need an account.
+func Verify(e Entry, signer ethereum.Signer) error {
+	if e.Price == nil {
+		return errors.New("price is missing")
+	}
+	from, err := e.Price.From(signer)
+	if err != nil {
+		return err
+	}
+	if *from != e.Signer {
+		return ErrSignerMismatch{Expected: e.Signer, Recovered: *from}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/geth"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+func newTestSigner(t *testing.T) *geth.Signer {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	acc, err := ks.NewAccount("test123")
+	require.NoError(t, err)
+	account, err := geth.NewAccount(dir, "test123", acc.Address)
+	require.NoError(t, err)
+	return geth.NewSigner(account)
+}
+
+func testEntry(t *testing.T, signer ethereum.Signer, wat string, price float64, tm time.Time) Entry {
+	p := &oracle.Price{Wat: wat, Age: tm}
+	p.SetFloat64Price(price)
+	require.NoError(t, p.Sign(signer))
+	return Entry{
+		Time:      tm,
+		Signer:    signer.Address(),
+		Price:     p,
+		Trace:     []byte(`{"type":"aggregator"}`),
+		Reason:    "initial",
+		Broadcast: true,
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	e := Entry{Time: time.Unix(100, 0), Price: &oracle.Price{Wat: "BTCUSD"}}
+	tests := []struct {
+		query Query
+		want  bool
+	}{
+		{query: Query{}, want: true},
+		{query: Query{AssetPair: "BTCUSD"}, want: true},
+		{query: Query{AssetPair: "ETHUSD"}, want: false},
+		{query: Query{From: time.Unix(100, 0), To: time.Unix(100, 0)}, want: true},
+		{query: Query{From: time.Unix(101, 0)}, want: false},
+		{query: Query{To: time.Unix(99, 0)}, want: false},
+	}
+	for n, tt := range tests {
+		assert.Equal(t, tt.want, tt.query.Match(e), "test #%d", n)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	e := testEntry(t, signer, "BTCUSD", 42000, time.Unix(100, 0))
+	recoverer := geth.NewSigner(nil)
+
+	// Valid signature:
+	assert.NoError(t, Verify(e, recoverer))
+
+	// Different signer:
+	other := e
+	other.Signer = ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	var mErr ErrSignerMismatch
+	assert.ErrorAs(t, Verify(other, recoverer), &mErr)
+	assert.Equal(t, signer.Address(), mErr.Recovered)
+
+	// Modified price:
+	modified := e
+	price := *e.Price
+	price.SetFloat64Price(1)
+	modified.Price = &price
+	assert.Error(t, Verify(modified, recoverer))
+}
@@ -0,0 +1,306 @@
+// Package backtest replays recorded origin prices through price models to
+// compare how different model configurations would have behaved.
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
+)
+
+// HistoryReference is the name of the reference which uses aggregator
+// prices recorded in the history instead of one of the models.
+const HistoryReference = "history"
+
+type ErrModelNotFound struct {
+	Name string
+}
+
+func (e ErrModelNotFound) Error() string {
+	return fmt.Sprintf("unable to find the %s model", e.Name)
+}
+
+// Model is a named set of price model graphs to test.
+type Model struct {
+	Name   string
+	Graphs map[gofer.Pair]nodes.Aggregator
+}
+
+// Config is the configuration for the Run function.
+type Config struct {
+	// Models is a list of models to test. Graphs must not be used anywhere
+	// else, because their origin nodes are fed with recorded prices.
+	Models []Model
+	// Pairs is a list of pairs to test. If empty, all pairs of the first
+	// model are used.
+	Pairs []gofer.Pair
+	// Reference is a name of the model to which other models are compared
+	// or the HistoryReference. If empty, the first model is used.
+	Reference string
+	// Records is a list of recorded prices. Origin prices are replayed
+	// through models, aggregator prices are used only if the Reference is
+	// set to the HistoryReference.
+	Records []history.Record
+}
+
+// Result contains statistics for a single model and pair.
+type Result struct {
+	Model     string
+	Reference string
+	Pair      gofer.Pair
+	// Ticks is the number of times the model price was calculated. A tick
+	// occurs for every distinct time of recorded origin prices.
+	Ticks int
+	// Errors is the number of ticks for which the model returned an error.
+	Errors int
+	// ErrorRate is Errors divided by Ticks.
+	ErrorRate float64
+	// Compared is the number of ticks for which both the model and
+	// the reference returned a valid price.
+	Compared int
+	// MaxDeviation is the maximum relative difference between the model
+	// price and the reference price.
+	MaxDeviation float64
+	// MeanDeviation is the mean relative difference between the model
+	// price and the reference price.
+	MeanDeviation float64
+	// TrackingError is the root-mean-square of relative differences between
+	// the model price and the reference price.
+	TrackingError float64
+}
+
+// Run replays recorded origin prices through models tick by tick and
+// returns statistics for every model and pair, in the order of models and
+// pairs.
+//
+// Recorded prices are ingested into origin nodes the same way as the feeder
+// does: a node accepts a new price only if its current price is older than
+// the node's minimum TTL, and prices with errors do not replace prices
+// which are not expired yet.
+func Run(cfg Config) ([]Result, error) {
+	if len(cfg.Models) == 0 {
+		return nil, errors.New("at least one model is required")
+	}
+	reference := cfg.Reference
+	if reference == "" {
+		reference = cfg.Models[0].Name
+	}
+	refIdx := -1
+	for i, m := range cfg.Models {
+		if m.Name == reference {
+			refIdx = i
+		}
+	}
+	if refIdx < 0 && reference != HistoryReference {
+		return nil, ErrModelNotFound{Name: reference}
+	}
+	pairs := cfg.Pairs
+	if len(pairs) == 0 {
+		pairs = sortPairs(cfg.Models[0].Graphs)
+	}
+
+	// Prepare models, all of them share the same, simulated clock:
+	var now time.Time
+	clock := func() time.Time { return now }
+	feedables := make([]map[nodes.OriginPair][]*nodes.OriginNode, len(cfg.Models))
+	for i, m := range cfg.Models {
+		feedables[i] = map[nodes.OriginPair][]*nodes.OriginNode{}
+		var roots []nodes.Node
+		for _, r := range m.Graphs {
+			roots = append(roots, r)
+		}
+		nodes.Walk(func(n nodes.Node) {
+			if on, ok := n.(*nodes.OriginNode); ok {
+				on.SetClock(clock)
+				feedables[i][on.OriginPair()] = append(feedables[i][on.OriginPair()], on)
+			}
+		}, roots...)
+	}
+
+	var origins []history.Record
+	aggregators := map[gofer.Pair][]history.Record{}
+	for _, r := range cfg.Records {
+		switch r.Type {
+		case history.OriginType:
+			origins = append(origins, r)
+		case history.AggregatorType:
+			// Intermediate prices of price models are not reference prices:
+			if r.Path == "" {
+				aggregators[r.Pair] = append(aggregators[r.Pair], r)
+			}
+		}
+	}
+	sort.SliceStable(origins, func(i, j int) bool {
+		return origins[i].Time.Before(origins[j].Time)
+	})
+	for _, rs := range aggregators {
+		sort.SliceStable(rs, func(i, j int) bool {
+			return rs[i].Time.Before(rs[j].Time)
+		})
+	}
+
+	stats := make([][]*stat, len(cfg.Models))
+	for i := range cfg.Models {
+		stats[i] = make([]*stat, len(pairs))
+		for j := range pairs {
+			stats[i][j] = &stat{}
+		}
+	}
+
+	for i := 0; i < len(origins); {
+		// Ingest all prices with the same time:
+		now = origins[i].Time
+		for ; i < len(origins) && origins[i].Time.Equal(now); i++ {
+			for _, fs := range feedables {
+				for _, n := range fs[nodes.OriginPair{Origin: origins[i].Origin, Pair: origins[i].Pair}] {
+					ingest(n, origins[i], now)
+				}
+			}
+		}
+
+		// Calculate prices:
+		for j, pair := range pairs {
+			ref, refOK := referencePrice(cfg.Models, refIdx, aggregators[pair], pair, now)
+			for k, m := range cfg.Models {
+				root, ok := m.Graphs[pair]
+				if !ok {
+					continue
+				}
+				price := root.Price()
+				stats[k][j].add(price.Price, price.Error == nil && price.Price.IsPositive(), ref, refOK)
+			}
+		}
+	}
+
+	var results []Result
+	for i, m := range cfg.Models {
+		for j, pair := range pairs {
+			if _, ok := m.Graphs[pair]; !ok {
+				continue
+			}
+			results = append(results, stats[i][j].result(m.Name, reference, pair))
+		}
+	}
+	return results, nil
+}
+
+// ingest sets the recorded price to the origin node, following the rules
+// used by the feeder.
+func ingest(n *nodes.OriginNode, r history.Record, now time.Time) {
+	cur := n.Price()
+	if !cur.Time.IsZero() && cur.Error == nil && now.Sub(cur.Time) < n.MinTTL() {
+		return
+	}
+	var err error
+	if r.Error != "" {
+		if !n.Expired() {
+			return
+		}
+		err = errors.New(r.Error)
+	}
+	_ = n.Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{
+			Pair:      r.Pair,
+			Price:     r.Price,
+			Bid:       r.Bid,
+			Ask:       r.Ask,
+			Volume24h: r.Volume24h,
+			Time:      r.Time,
+		},
+		Origin: r.Origin,
+		Error:  err,
+	})
+}
+
+// referencePrice returns the reference price at the given time.
+func referencePrice(
+	models []Model,
+	refIdx int,
+	records []history.Record,
+	pair gofer.Pair,
+	now time.Time) (decimal.Decimal, bool) {
+
+	if refIdx >= 0 {
+		root, ok := models[refIdx].Graphs[pair]
+		if !ok {
+			return decimal.Zero, false
+		}
+		price := root.Price()
+		return price.Price, price.Error == nil && price.Price.IsPositive()
+	}
+	// Find the most recent recorded price:
+	i := sort.Search(len(records), func(i int) bool {
+		return records[i].Time.After(now)
+	})
+	for i--; i >= 0; i-- {
+		if records[i].Error == "" && records[i].Price.IsPositive() {
+			return records[i].Price, true
+		}
+	}
+	return decimal.Zero, false
+}
+
+type stat struct {
+	ticks    int
+	errors   int
+	compared int
+	maxDev   float64
+	sumDev   float64
+	sumSqDev float64
+}
+
+func (s *stat) add(price decimal.Decimal, ok bool, ref decimal.Decimal, refOK bool) {
+	s.ticks++
+	if !ok {
+		s.errors++
+		return
+	}
+	if !refOK {
+		return
+	}
+	dev := price.Sub(ref).Abs().Div(ref).Float64()
+	s.compared++
+	s.sumDev += dev
+	s.sumSqDev += dev * dev
+	if dev > s.maxDev {
+		s.maxDev = dev
+	}
+}
+
+func (s *stat) result(model, reference string, pair gofer.Pair) Result {
+	r := Result{
+		Model:        model,
+		Reference:    reference,
+		Pair:         pair,
+		Ticks:        s.ticks,
+		Errors:       s.errors,
+		Compared:     s.compared,
+		MaxDeviation: s.maxDev,
+	}
+	if s.ticks > 0 {
+		r.ErrorRate = float64(s.errors) / float64(s.ticks)
+	}
+	if s.compared > 0 {
+		r.MeanDeviation = s.sumDev / float64(s.compared)
+		r.TrackingError = math.Sqrt(s.sumSqDev / float64(s.compared))
+	}
+	return r
+}
+
+func sortPairs(graphs map[gofer.Pair]nodes.Aggregator) []gofer.Pair {
+	var ps []gofer.Pair
+	for p := range graphs {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].String() < ps[j].String()
+	})
+	return ps
+}
@@ -0,0 +1,135 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/history"
+)
+
+var btcusd = gofer.Pair{Base: "BTC", Quote: "USD"}
+
+func medianModel(name string, minSources int, ttl time.Duration, origins ...string) Model {
+	root := nodes.NewMedianAggregatorNode(btcusd, minSources)
+	for _, o := range origins {
+		root.AddChild(nodes.NewOriginNode(nodes.OriginPair{Origin: o, Pair: btcusd}, ttl, ttl+time.Minute))
+	}
+	return Model{Name: name, Graphs: map[gofer.Pair]nodes.Aggregator{btcusd: root}}
+}
+
+func originRecord(origin, price string, t time.Time) history.Record {
+	return history.Record{
+		Type:   history.OriginType,
+		Origin: origin,
+		Pair:   btcusd,
+		Price:  decimal.RequireFromString(price),
+		Time:   t,
+	}
+}
+
+func TestRun(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	records := []history.Record{
+		originRecord("a", "100", t0),
+		originRecord("b", "110", t0),
+		originRecord("c", "90", t0),
+		originRecord("a", "100", t0.Add(time.Second)),
+		originRecord("b", "100", t0.Add(time.Second)),
+		originRecord("c", "100", t0.Add(time.Second)),
+	}
+
+	results, err := Run(Config{
+		Models: []Model{
+			medianModel("ref", 3, 0, "a", "b", "c"),
+			medianModel("ab", 2, 0, "a", "b"),
+			medianModel("single", 1, 0, "b"),
+		},
+		Records: records,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// Reference model is compared to itself:
+	assert.Equal(t, "ref", results[0].Model)
+	assert.Equal(t, 2, results[0].Ticks)
+	assert.Equal(t, 0, results[0].Errors)
+	assert.Equal(t, 0.0, results[0].MaxDeviation)
+
+	// The median of 100 and 110 is 105, which differs by 5% from the reference
+	// price in the first tick:
+	assert.Equal(t, "ab", results[1].Model)
+	assert.Equal(t, "ref", results[1].Reference)
+	assert.Equal(t, 2, results[1].Compared)
+	assert.InDelta(t, 0.05, results[1].MaxDeviation, 1e-12)
+	assert.InDelta(t, 0.025, results[1].MeanDeviation, 1e-12)
+	assert.InDelta(t, 0.035355339, results[1].TrackingError, 1e-9)
+
+	assert.Equal(t, "single", results[2].Model)
+	assert.InDelta(t, 0.1, results[2].MaxDeviation, 1e-12)
+}
+
+func TestRun_ErrorRateAndTTL(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	records := []history.Record{
+		originRecord("a", "100", t0),
+		originRecord("b", "100", t0.Add(10*time.Second)),
+		originRecord("a", "200", t0.Add(20*time.Second)),
+		originRecord("b", "200", t0.Add(30*time.Second)),
+	}
+
+	results, err := Run(Config{
+		Models: []Model{
+			medianModel("fast", 2, time.Second, "a", "b"),
+			medianModel("slow", 2, time.Minute, "a", "b"),
+		},
+		Records: records,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// There are not enough sources in the first tick:
+	assert.Equal(t, 4, results[0].Ticks)
+	assert.Equal(t, 1, results[0].Errors)
+	assert.Equal(t, 0.25, results[0].ErrorRate)
+
+	// The slow model does not update prices within the TTL, so its price
+	// stays at 100 while the fast model goes to 150 and 200:
+	assert.Equal(t, 1, results[1].Errors)
+	assert.Equal(t, 3, results[1].Compared)
+	assert.InDelta(t, 0.5, results[1].MaxDeviation, 1e-12)
+}
+
+func TestRun_HistoryReference(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	records := []history.Record{
+		originRecord("a", "100", t0),
+		{Type: history.AggregatorType, Pair: btcusd, Price: decimal.NewFromInt(80), Time: t0.Add(-time.Second)},
+	}
+
+	results, err := Run(Config{
+		Models:    []Model{medianModel("a", 1, 0, "a")},
+		Reference: HistoryReference,
+		Records:   records,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, HistoryReference, results[0].Reference)
+	assert.InDelta(t, 0.25, results[0].MaxDeviation, 1e-12)
+}
+
+func TestRun_InvalidReference(t *testing.T) {
+	_, err := Run(Config{
+		Models:    []Model{medianModel("a", 1, 0, "a")},
+		Reference: "b",
+	})
+	assert.ErrorIs(t, err, ErrModelNotFound{Name: "b"})
+
+	_, err = Run(Config{})
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Pair represents an asset pair.
@@ -85,10 +87,10 @@ type Price struct {
 	Type       string
 	Parameters map[string]string
 	Pair       Pair
-	Price      float64
-	Bid        float64
-	Ask        float64
-	Volume24h  float64
+	Price      decimal.Decimal
+	Bid        decimal.Decimal
+	Ask        decimal.Decimal
+	Volume24h  decimal.Decimal
 	Time       time.Time
 	Prices     []*Price
 	Error      string
@@ -38,6 +38,17 @@ func (a *AsyncGofer) Start() error {
 	return a.feeder.Start(ns...)
 }
 
+// UpdateGraphs implements the Updater interface. Nodes updated by the
+// asynchronous price updater are replaced as well.
+func (a *AsyncGofer) UpdateGraphs(g map[gofer.Pair]nodes.Aggregator) error {
+	if err := a.Gofer.UpdateGraphs(g); err != nil {
+		return err
+	}
+	ns, _ := a.findNodes()
+	a.feeder.Update(ns...)
+	return nil
+}
+
 // Wait waits until feeder's context is cancelled.
 func (a *AsyncGofer) Wait() {
 	<-a.doneCh
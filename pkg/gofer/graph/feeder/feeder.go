@@ -2,12 +2,15 @@ package feeder
 
 import (
 	"context"
+	"sync"
+	"time"
+
 	"github.com/hashicorp/go-multierror"
+
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/origins"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
-	"time"
 )
 
 const LoggerTag = "FEEDER"
@@ -55,15 +58,20 @@ type Feeder struct {
 	set    *origins.Set
 	log    log.Logger
 	doneCh chan struct{}
+
+	mu       sync.RWMutex
+	nodes    []nodes.Node
+	updateCh chan struct{}
 }
 
 // NewFeeder creates new Feeder instance.
 func NewFeeder(ctx context.Context, set *origins.Set, log log.Logger) *Feeder {
 	return &Feeder{
-		ctx:    ctx,
-		set:    set,
-		log:    log.WithField("tag", LoggerTag),
-		doneCh: make(chan struct{}),
+		ctx:      ctx,
+		set:      set,
+		log:      log.WithField("tag", LoggerTag),
+		doneCh:   make(chan struct{}),
+		updateCh: make(chan struct{}, 1),
 	}
 }
 
@@ -77,17 +85,20 @@ func (f *Feeder) Feed(ns ...nodes.Node) Warnings {
 func (f *Feeder) Start(ns ...nodes.Node) error {
 	f.log.Infof("Starting")
 
-	gcdTTL := getGCDTTL(ns)
+	f.mu.Lock()
+	f.nodes = ns
+	f.mu.Unlock()
 
-	if gcdTTL < time.Second {
-		gcdTTL = time.Second
-	}
+	gcdTTL := f.interval()
 	f.log.WithField("interval", gcdTTL.String()).Infof("Update interval (GCD of all TTLs)")
 
 	feed := func() {
 		// We have to add gcdTTL to the current time because we want
 		// to find all nodes that will expire before the next tick.
 		t := time.Now().Add(gcdTTL)
+		f.mu.RLock()
+		ns := f.nodes
+		f.mu.RUnlock()
 		warns := f.fetchPricesAndFeedThemToFeedableNodes(f.findFeedableNodes(ns, t))
 		if len(warns.List) > 0 {
 			f.log.WithError(warns.ToError()).Warn("Unable to feed some nodes")
@@ -104,6 +115,11 @@ func (f *Feeder) Start(ns ...nodes.Node) error {
 				return
 			case <-ticker.C:
 				feed()
+			case <-f.updateCh:
+				gcdTTL = f.interval()
+				ticker.Reset(gcdTTL)
+				f.log.WithField("interval", gcdTTL.String()).Infof("Update interval (GCD of all TTLs)")
+				feed()
 			}
 		}
 	}()
@@ -112,6 +128,19 @@ func (f *Feeder) Start(ns ...nodes.Node) error {
 	return nil
 }
 
+// Update replaces nodes updated by the goroutine started by the Start
+// method. Nodes with expired prices are fed immediately, and the update
+// interval is recalculated.
+func (f *Feeder) Update(ns ...nodes.Node) {
+	f.mu.Lock()
+	f.nodes = ns
+	f.mu.Unlock()
+	select {
+	case f.updateCh <- struct{}{}:
+	default:
+	}
+}
+
 // Wait waits until feeder's context is cancelled.
 func (f *Feeder) Wait() {
 	<-f.doneCh
@@ -124,6 +153,17 @@ func (f *Feeder) contextCancelHandler() {
 	<-f.ctx.Done()
 }
 
+// interval returns the GCD of TTLs of all nodes, but not less than one second.
+func (f *Feeder) interval() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	gcdTTL := getGCDTTL(f.nodes)
+	if gcdTTL < time.Second {
+		gcdTTL = time.Second
+	}
+	return gcdTTL
+}
+
 // findFeedableNodes returns a list of children nodes from given root nodes
 // which implement Feedable interface, and their price is expired according
 // to the time from the t arg.
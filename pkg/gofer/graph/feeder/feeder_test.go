@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/origins"
@@ -89,10 +90,10 @@ func TestFeeder_Feed_OneOriginNode(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     10,
-				Bid:       9,
-				Ask:       11,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(9),
+				Ask:       decimal.NewFromInt(11),
+				Volume24h: decimal.NewFromInt(10),
 				Timestamp: time.Unix(10000, 0),
 			},
 		},
@@ -111,10 +112,10 @@ func TestFeeder_Feed_OneOriginNode(t *testing.T) {
 
 	assert.Len(t, warns.List, 0)
 	assert.Equal(t, gofer.Pair{Base: "A", Quote: "B"}, o.Price().Pair)
-	assert.Equal(t, 10.0, o.Price().Price)
-	assert.Equal(t, 9.0, o.Price().Bid)
-	assert.Equal(t, 11.0, o.Price().Ask)
-	assert.Equal(t, 10.0, o.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(9), o.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(11), o.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Volume24h)
 	assert.Equal(t, time.Unix(10000, 0), o.Price().Time)
 }
 
@@ -126,28 +127,28 @@ func TestFeeder_Feed_ManyOriginNodes(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     10,
-				Bid:       9,
-				Ask:       11,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(9),
+				Ask:       decimal.NewFromInt(11),
+				Volume24h: decimal.NewFromInt(10),
 				Timestamp: time.Unix(10000, 0),
 			},
 			origins.Price{
 				Pair:      origins.Pair{Base: "C", Quote: "D"},
-				Price:     20,
-				Bid:       19,
-				Ask:       21,
-				Volume24h: 20,
+				Price:     decimal.NewFromInt(20),
+				Bid:       decimal.NewFromInt(19),
+				Ask:       decimal.NewFromInt(21),
+				Volume24h: decimal.NewFromInt(20),
 				Timestamp: time.Unix(20000, 0),
 			},
 		},
 		"test2": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "E", Quote: "F"},
-				Price:     30,
-				Bid:       39,
-				Ask:       31,
-				Volume24h: 30,
+				Price:     decimal.NewFromInt(30),
+				Bid:       decimal.NewFromInt(39),
+				Ask:       decimal.NewFromInt(31),
+				Volume24h: decimal.NewFromInt(30),
 				Timestamp: time.Unix(30000, 0),
 			},
 		},
@@ -187,31 +188,31 @@ func TestFeeder_Feed_ManyOriginNodes(t *testing.T) {
 	assert.Len(t, warns.List, 0)
 
 	assert.Equal(t, gofer.Pair{Base: "A", Quote: "B"}, o1.Price().Pair)
-	assert.Equal(t, 10.0, o1.Price().Price)
-	assert.Equal(t, 9.0, o1.Price().Bid)
-	assert.Equal(t, 11.0, o1.Price().Ask)
-	assert.Equal(t, 10.0, o1.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(10), o1.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(9), o1.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(11), o1.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(10), o1.Price().Volume24h)
 	assert.Equal(t, time.Unix(10000, 0), o1.Price().Time)
 
 	assert.Equal(t, gofer.Pair{Base: "C", Quote: "D"}, o2.Price().Pair)
-	assert.Equal(t, 20.0, o2.Price().Price)
-	assert.Equal(t, 19.0, o2.Price().Bid)
-	assert.Equal(t, 21.0, o2.Price().Ask)
-	assert.Equal(t, 20.0, o2.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(20), o2.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(19), o2.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(21), o2.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(20), o2.Price().Volume24h)
 	assert.Equal(t, time.Unix(20000, 0), o2.Price().Time)
 
 	assert.Equal(t, gofer.Pair{Base: "E", Quote: "F"}, o3.Price().Pair)
-	assert.Equal(t, 30.0, o3.Price().Price)
-	assert.Equal(t, 39.0, o3.Price().Bid)
-	assert.Equal(t, 31.0, o3.Price().Ask)
-	assert.Equal(t, 30.0, o3.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(30), o3.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(39), o3.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(31), o3.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(30), o3.Price().Volume24h)
 	assert.Equal(t, time.Unix(30000, 0), o3.Price().Time)
 
 	assert.Equal(t, gofer.Pair{Base: "E", Quote: "F"}, o4.Price().Pair)
-	assert.Equal(t, 30.0, o4.Price().Price)
-	assert.Equal(t, 39.0, o4.Price().Bid)
-	assert.Equal(t, 31.0, o4.Price().Ask)
-	assert.Equal(t, 30.0, o4.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(30), o4.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(39), o4.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(31), o4.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(30), o4.Price().Volume24h)
 	assert.Equal(t, time.Unix(30000, 0), o4.Price().Time)
 
 	// Check if pairs were properly grouped per origins and check if the E/F pair
@@ -230,10 +231,10 @@ func TestFeeder_Feed_NestedOriginNode(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     10,
-				Bid:       9,
-				Ask:       11,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(9),
+				Ask:       decimal.NewFromInt(11),
+				Volume24h: decimal.NewFromInt(10),
 				Timestamp: time.Unix(10000, 0),
 			},
 		},
@@ -254,10 +255,10 @@ func TestFeeder_Feed_NestedOriginNode(t *testing.T) {
 
 	assert.Len(t, warns.List, 0)
 	assert.Equal(t, gofer.Pair{Base: "A", Quote: "B"}, o.Price().Pair)
-	assert.Equal(t, 10.0, o.Price().Price)
-	assert.Equal(t, 9.0, o.Price().Bid)
-	assert.Equal(t, 11.0, o.Price().Ask)
-	assert.Equal(t, 10.0, o.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(9), o.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(11), o.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Volume24h)
 	assert.Equal(t, time.Unix(10000, 0), o.Price().Time)
 }
 
@@ -269,10 +270,10 @@ func TestFeeder_Feed_BelowMinTTL(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     11,
-				Bid:       10,
-				Ask:       12,
-				Volume24h: 11,
+				Price:     decimal.NewFromInt(11),
+				Bid:       decimal.NewFromInt(10),
+				Ask:       decimal.NewFromInt(12),
+				Volume24h: decimal.NewFromInt(11),
 				Timestamp: time.Unix(10000, 0),
 			},
 		},
@@ -289,10 +290,10 @@ func TestFeeder_Feed_BelowMinTTL(t *testing.T) {
 	_ = o.Ingest(nodes.OriginPrice{
 		PairPrice: nodes.PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       9,
-			Ask:       11,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(9),
+			Ask:       decimal.NewFromInt(11),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now().Add(-5 * time.Second),
 		},
 		Origin: "test",
@@ -305,10 +306,10 @@ func TestFeeder_Feed_BelowMinTTL(t *testing.T) {
 	// OriginNode shouldn't be updated because time diff is below MinTTL setting:
 	assert.Len(t, warns.List, 0)
 	assert.Equal(t, gofer.Pair{Base: "A", Quote: "B"}, o.Price().Pair)
-	assert.Equal(t, 10.0, o.Price().Price)
-	assert.Equal(t, 9.0, o.Price().Bid)
-	assert.Equal(t, 11.0, o.Price().Ask)
-	assert.Equal(t, 10.0, o.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(9), o.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(11), o.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Volume24h)
 }
 
 func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
@@ -319,10 +320,10 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     11,
-				Bid:       10,
-				Ask:       12,
-				Volume24h: 11,
+				Price:     decimal.NewFromInt(11),
+				Bid:       decimal.NewFromInt(10),
+				Ask:       decimal.NewFromInt(12),
+				Volume24h: decimal.NewFromInt(11),
 				Timestamp: time.Unix(10000, 0),
 			},
 		},
@@ -339,10 +340,10 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 	_ = o.Ingest(nodes.OriginPrice{
 		PairPrice: nodes.PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       9,
-			Ask:       11,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(9),
+			Ask:       decimal.NewFromInt(11),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now().Add(-30 * time.Second),
 		},
 		Origin: "test",
@@ -355,10 +356,10 @@ func TestFeeder_Feed_BetweenTTLs(t *testing.T) {
 	// OriginNode should be updated because time diff is above MinTTL setting:
 	assert.Len(t, warns.List, 0)
 	assert.Equal(t, gofer.Pair{Base: "A", Quote: "B"}, o.Price().Pair)
-	assert.Equal(t, 11.0, o.Price().Price)
-	assert.Equal(t, 10.0, o.Price().Bid)
-	assert.Equal(t, 12.0, o.Price().Ask)
-	assert.Equal(t, 11.0, o.Price().Volume24h)
+	assert.Equal(t, decimal.NewFromInt(11), o.Price().Price)
+	assert.Equal(t, decimal.NewFromInt(10), o.Price().Bid)
+	assert.Equal(t, decimal.NewFromInt(12), o.Price().Ask)
+	assert.Equal(t, decimal.NewFromInt(11), o.Price().Volume24h)
 }
 
 func Test_getGCDTTL(t *testing.T) {
@@ -389,10 +390,10 @@ func TestFeeder_ch11427(t *testing.T) {
 		"test": {
 			origins.Price{
 				Pair:      origins.Pair{Base: "A", Quote: "B"},
-				Price:     11,
-				Bid:       10,
-				Ask:       12,
-				Volume24h: 11,
+				Price:     decimal.NewFromInt(11),
+				Bid:       decimal.NewFromInt(10),
+				Ask:       decimal.NewFromInt(12),
+				Volume24h: decimal.NewFromInt(11),
 				Timestamp: time.Now(),
 			},
 		},
@@ -411,3 +412,43 @@ func TestFeeder_ch11427(t *testing.T) {
 	time.Sleep(2500 * time.Millisecond)
 	assert.False(t, o.Expired())
 }
+
+func TestFeeder_Update(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	s := originsSetMock(map[string][]origins.Price{
+		"test": {
+			origins.Price{
+				Pair:      origins.Pair{Base: "A", Quote: "B"},
+				Price:     decimal.NewFromInt(10),
+				Timestamp: time.Now(),
+			},
+			origins.Price{
+				Pair:      origins.Pair{Base: "X", Quote: "Y"},
+				Price:     decimal.NewFromInt(20),
+				Timestamp: time.Now(),
+			},
+		},
+	}, 0, true)
+
+	f := NewFeeder(ctx, s, null.New())
+
+	o1 := nodes.NewOriginNode(nodes.OriginPair{
+		Origin: "test",
+		Pair:   gofer.Pair{Base: "A", Quote: "B"},
+	}, time.Minute, time.Minute)
+	o2 := nodes.NewOriginNode(nodes.OriginPair{
+		Origin: "test",
+		Pair:   gofer.Pair{Base: "X", Quote: "Y"},
+	}, time.Minute, time.Minute)
+
+	assert.NoError(t, f.Start(o1))
+	assert.Eventually(t, func() bool { return !o1.Expired() }, time.Second, 10*time.Millisecond)
+	assert.True(t, o2.Expired())
+
+	// New nodes should be fed immediately, without waiting for the next tick:
+	f.Update(o2)
+	assert.Eventually(t, func() bool { return !o2.Expired() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "20", o2.Price().Price.String())
+}
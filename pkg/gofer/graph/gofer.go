@@ -1,9 +1,13 @@
 package graph
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/feeder"
@@ -18,9 +22,17 @@ func (e ErrPairNotFound) Error() string {
 	return fmt.Sprintf("unable to find the %s pair", e.Pair)
 }
 
+// Updater is implemented by Gofer instances whose price models can be
+// replaced while they are running.
+type Updater interface {
+	// UpdateGraphs replaces all price models with the given graphs.
+	UpdateGraphs(g map[gofer.Pair]nodes.Aggregator) error
+}
+
 // Gofer implements the gofer.Gofer interface. It uses a graph structure
 // to calculate pairs prices.
 type Gofer struct {
+	mu     sync.RWMutex
 	graphs map[gofer.Pair]nodes.Aggregator
 	feeder *feeder.Feeder
 }
@@ -32,6 +44,20 @@ func NewGofer(g map[gofer.Pair]nodes.Aggregator, f *feeder.Feeder) *Gofer {
 	return &Gofer{graphs: g, feeder: f}
 }
 
+// UpdateGraphs implements the Updater interface. Origin nodes of the new
+// graphs which have the same origin, pair and TTLs as nodes in the current
+// graphs keep their prices, so they do not have to be fetched again.
+func (g *Gofer) UpdateGraphs(graphs map[gofer.Pair]nodes.Aggregator) error {
+	if graphs == nil {
+		return errors.New("graphs must not be nil")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	copyOriginPrices(g.graphs, graphs)
+	g.graphs = graphs
+	return nil
+}
+
 // Models implements the gofer.Gofer interface.
 func (g *Gofer) Models(pairs ...gofer.Pair) (map[gofer.Pair]*gofer.Model, error) {
 	ns, err := g.findNodes(pairs...)
@@ -49,7 +75,9 @@ func (g *Gofer) Models(pairs ...gofer.Pair) (map[gofer.Pair]*gofer.Model, error)
 
 // Price implements the gofer.Gofer interface.
 func (g *Gofer) Price(pair gofer.Pair) (*gofer.Price, error) {
+	g.mu.RLock()
 	n, ok := g.graphs[pair]
+	g.mu.RUnlock()
 	if !ok {
 		return nil, ErrPairNotFound{Pair: pair}
 	}
@@ -84,6 +112,8 @@ func (g *Gofer) TokenTotalSupply(token []gofer.Token) (float64, error) {
 
 // Pairs implements the gofer.Gofer interface.
 func (g *Gofer) Pairs() ([]gofer.Pair, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	var ps []gofer.Pair
 	for p := range g.graphs {
 		ps = append(ps, p)
@@ -94,6 +124,8 @@ func (g *Gofer) Pairs() ([]gofer.Pair, error) {
 // findNodes return root nodes for given pairs. If no nodes are specified,
 // then all root nodes are returned.
 func (g *Gofer) findNodes(pairs ...gofer.Pair) ([]nodes.Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	var ns []nodes.Node
 	if len(pairs) == 0 { // Return all:
 		for _, n := range g.graphs {
@@ -111,6 +143,39 @@ func (g *Gofer) findNodes(pairs ...gofer.Pair) ([]nodes.Node, error) {
 	return ns, nil
 }
 
+// copyOriginPrices copies prices from origin nodes of the src graphs to
+// the corresponding origin nodes of the dst graphs.
+func copyOriginPrices(src, dst map[gofer.Pair]nodes.Aggregator) {
+	type originKey struct {
+		originPair nodes.OriginPair
+		minTTL     time.Duration
+		maxTTL     time.Duration
+	}
+	prices := map[originKey]nodes.OriginPrice{}
+	nodes.Walk(func(n nodes.Node) {
+		if on, ok := n.(*nodes.OriginNode); ok {
+			if price := on.Price(); !price.Time.IsZero() {
+				prices[originKey{on.OriginPair(), on.MinTTL(), on.MaxTTL()}] = price
+			}
+		}
+	}, aggregatorNodes(src)...)
+	nodes.Walk(func(n nodes.Node) {
+		if on, ok := n.(*nodes.OriginNode); ok {
+			if price, ok := prices[originKey{on.OriginPair(), on.MinTTL(), on.MaxTTL()}]; ok {
+				_ = on.Ingest(price)
+			}
+		}
+	}, aggregatorNodes(dst)...)
+}
+
+func aggregatorNodes(graphs map[gofer.Pair]nodes.Aggregator) []nodes.Node {
+	var ns []nodes.Node
+	for _, n := range graphs {
+		ns = append(ns, n)
+	}
+	return ns
+}
+
 func mapGraphNodes(n nodes.Node) *gofer.Model {
 	gn := &gofer.Model{
 		Type:       strings.TrimLeft(reflect.TypeOf(n).String(), "*"),
@@ -128,6 +193,7 @@ func mapGraphNodes(n nodes.Node) *gofer.Model {
 		gn.Type = "origin"
 		gn.Pair = typedNode.OriginPair().Pair
 		gn.Parameters["origin"] = typedNode.OriginPair().Origin
+		gn.Parameters["ttl"] = strconv.Itoa(int(typedNode.MinTTL() / time.Second))
 	default:
 		panic("unsupported node")
 	}
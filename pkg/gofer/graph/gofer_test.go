@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/feeder"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
@@ -31,7 +32,7 @@ var (
 			Models: []*gofer.Model{
 				{
 					Type:       "origin",
-					Parameters: map[string]string{"origin": "a"},
+					Parameters: map[string]string{"origin": "a", "ttl": "3600"},
 					Pair:       testPairs["A/B"],
 					Models:     nil,
 				},
@@ -42,13 +43,13 @@ var (
 					Models: []*gofer.Model{
 						{
 							Type:       "origin",
-							Parameters: map[string]string{"origin": "a"},
+							Parameters: map[string]string{"origin": "a", "ttl": "3600"},
 							Pair:       testPairs["A/B"],
 							Models:     nil,
 						},
 						{
 							Type:       "origin",
-							Parameters: map[string]string{"origin": "b"},
+							Parameters: map[string]string{"origin": "b", "ttl": "3600"},
 							Pair:       testPairs["A/B"],
 							Models:     nil,
 						},
@@ -63,13 +64,13 @@ var (
 			Models: []*gofer.Model{
 				{
 					Type:       "origin",
-					Parameters: map[string]string{"origin": "x"},
+					Parameters: map[string]string{"origin": "x", "ttl": "3600"},
 					Pair:       testPairs["X/Y"],
 					Models:     nil,
 				},
 				{
 					Type:       "origin",
-					Parameters: map[string]string{"origin": "y"},
+					Parameters: map[string]string{"origin": "y", "ttl": "3600"},
 					Pair:       testPairs["X/Y"],
 					Models:     nil,
 				},
@@ -84,10 +85,10 @@ var (
 				"minimumSuccessfulSources": "0",
 			},
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       9,
-			Ask:       11,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(9),
+			Ask:       decimal.NewFromInt(11),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      testTime,
 			Prices: []*gofer.Price{
 				{
@@ -96,10 +97,10 @@ var (
 						"origin": "a",
 					},
 					Pair:      gofer.Pair{Base: "A", Quote: "B"},
-					Price:     10,
-					Bid:       9,
-					Ask:       11,
-					Volume24h: 20,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(9),
+					Ask:       decimal.NewFromInt(11),
+					Volume24h: decimal.NewFromInt(20),
 					Time:      testTime,
 				},
 				{
@@ -109,10 +110,10 @@ var (
 						"minimumSuccessfulSources": "0",
 					},
 					Pair:      gofer.Pair{Base: "A", Quote: "B"},
-					Price:     10,
-					Bid:       9,
-					Ask:       11,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(9),
+					Ask:       decimal.NewFromInt(11),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      testTime,
 					Prices: []*gofer.Price{
 						{
@@ -121,10 +122,10 @@ var (
 								"origin": "a",
 							},
 							Pair:      gofer.Pair{Base: "A", Quote: "B"},
-							Price:     10,
-							Bid:       9,
-							Ask:       11,
-							Volume24h: 20,
+							Price:     decimal.NewFromInt(10),
+							Bid:       decimal.NewFromInt(9),
+							Ask:       decimal.NewFromInt(11),
+							Volume24h: decimal.NewFromInt(20),
 							Time:      testTime,
 						},
 						{
@@ -133,10 +134,10 @@ var (
 								"origin": "b",
 							},
 							Pair:      gofer.Pair{Base: "A", Quote: "B"},
-							Price:     10,
-							Bid:       9,
-							Ask:       11,
-							Volume24h: 20,
+							Price:     decimal.NewFromInt(10),
+							Bid:       decimal.NewFromInt(9),
+							Ask:       decimal.NewFromInt(11),
+							Volume24h: decimal.NewFromInt(20),
 							Time:      testTime,
 						},
 					},
@@ -150,10 +151,10 @@ var (
 				"minimumSuccessfulSources": "0",
 			},
 			Pair:      gofer.Pair{Base: "X", Quote: "Y"},
-			Price:     10,
-			Bid:       9,
-			Ask:       11,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(9),
+			Ask:       decimal.NewFromInt(11),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      testTime,
 			Prices: []*gofer.Price{
 				{
@@ -162,10 +163,10 @@ var (
 						"origin": "x",
 					},
 					Pair:      gofer.Pair{Base: "X", Quote: "Y"},
-					Price:     10,
-					Bid:       9,
-					Ask:       11,
-					Volume24h: 20,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(9),
+					Ask:       decimal.NewFromInt(11),
+					Volume24h: decimal.NewFromInt(20),
 					Time:      testTime,
 				},
 				{
@@ -174,10 +175,10 @@ var (
 						"origin": "y",
 					},
 					Pair:      gofer.Pair{Base: "X", Quote: "Y"},
-					Price:     10,
-					Bid:       9,
-					Ask:       11,
-					Volume24h: 20,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(9),
+					Ask:       decimal.NewFromInt(11),
+					Volume24h: decimal.NewFromInt(20),
 					Time:      testTime,
 				},
 			},
@@ -193,10 +194,10 @@ func (f *testExchange) Fetch(pairs []origins.Pair) []origins.FetchResult {
 		r = append(r, origins.FetchResult{
 			Price: origins.Price{
 				Pair:      p,
-				Price:     10,
-				Bid:       9,
-				Ask:       11,
-				Volume24h: 20,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(9),
+				Ask:       decimal.NewFromInt(11),
+				Volume24h: decimal.NewFromInt(20),
 				Timestamp: testTime,
 			},
 			Error: nil,
@@ -311,3 +312,35 @@ func TestGofer_Prices_MissingPair(t *testing.T) {
 
 	assert.True(t, errors.As(err, &ErrPairNotFound{}))
 }
+
+func TestGofer_UpdateGraphs(t *testing.T) {
+	ab := testPairs["A/B"]
+	xy := testPairs["X/Y"]
+	exp := 3600 * time.Second
+
+	on := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: ab}, exp, exp)
+	assert.NoError(t, on.Ingest(nodes.OriginPrice{
+		PairPrice: nodes.PairPrice{Pair: ab, Price: decimal.NewFromInt(10), Time: testTime},
+		Origin:    "a",
+	}))
+	abGraph := nodes.NewMedianAggregatorNode(ab, 0)
+	abGraph.AddChild(on)
+	g := NewGofer(map[gofer.Pair]nodes.Aggregator{ab: abGraph}, nil)
+
+	// The "a" origin node is unchanged, so it should keep its price, the "b"
+	// node uses a different TTL:
+	newON1 := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: ab}, exp, exp)
+	newON2 := nodes.NewOriginNode(nodes.OriginPair{Origin: "a", Pair: ab}, time.Minute, time.Minute)
+	newABGraph := nodes.NewMedianAggregatorNode(ab, 0)
+	newABGraph.AddChild(newON1)
+	newABGraph.AddChild(newON2)
+	xyGraph := nodes.NewMedianAggregatorNode(xy, 0)
+	assert.NoError(t, g.UpdateGraphs(map[gofer.Pair]nodes.Aggregator{ab: newABGraph, xy: xyGraph}))
+
+	pairs, err := g.Pairs()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []gofer.Pair{ab, xy}, pairs)
+	assert.Equal(t, "10", newON1.Price().Price.String())
+	assert.True(t, newON2.Price().Time.IsZero())
+	assert.Error(t, g.UpdateGraphs(nil))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -167,7 +168,7 @@ func (n *IndirectAggregatorNode) Price() AggregatorPrice {
 		)
 	}
 
-	if indirectPrice.Price <= 0 {
+	if !indirectPrice.Price.IsPositive() {
 		err = multierror.Append(
 			err,
 			ErrInvalidPrice{
@@ -185,8 +186,12 @@ func (n *IndirectAggregatorNode) Price() AggregatorPrice {
 	}
 }
 
+var one = decimal.NewFromInt(1)
+
 // crossRate returns a calculated price from the list of prices. Prices order
-// is important because prices are calculated from first to last.
+// is important because prices are calculated from first to last. All
+// calculations are exact, so the result does not depend on the magnitude
+// of the prices.
 //
 // TODO: Decide what to do with division by zero during calculating Bid/Ask prices.
 //
@@ -203,79 +208,79 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 		b := t[i+1]
 
 		var pair gofer.Pair
-		var price, bid, ask float64
+		var price, bid, ask decimal.Decimal
 		switch {
 		case a.Pair.Quote == b.Pair.Quote: // A/C, B/C
 			pair.Base = a.Pair.Base
 			pair.Quote = b.Pair.Base
 
-			if b.Price > 0 {
-				price = a.Price / b.Price
+			if b.Price.IsPositive() {
+				price = a.Price.Div(b.Price)
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
-				price = 0
+				price = decimal.Zero
 			}
 
-			if b.Bid > 0 {
-				bid = a.Bid / b.Bid
+			if b.Bid.IsPositive() {
+				bid = a.Bid.Div(b.Bid)
 			} else {
-				bid = 0
+				bid = decimal.Zero
 			}
 
-			if b.Ask > 0 {
-				ask = a.Ask / b.Ask
+			if b.Ask.IsPositive() {
+				ask = a.Ask.Div(b.Ask)
 			} else {
-				ask = 0
+				ask = decimal.Zero
 			}
 		case a.Pair.Base == b.Pair.Base: // C/A, C/B
 			pair.Base = a.Pair.Quote
 			pair.Quote = b.Pair.Quote
 
-			if a.Price > 0 {
-				price = b.Price / a.Price
+			if a.Price.IsPositive() {
+				price = b.Price.Div(a.Price)
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
-				price = 0
+				price = decimal.Zero
 			}
 
-			if a.Bid > 0 {
-				bid = b.Bid / a.Bid
+			if a.Bid.IsPositive() {
+				bid = b.Bid.Div(a.Bid)
 			} else {
-				bid = 0
+				bid = decimal.Zero
 			}
 
-			if a.Ask > 0 {
-				ask = b.Ask / a.Ask
+			if a.Ask.IsPositive() {
+				ask = b.Ask.Div(a.Ask)
 			} else {
-				ask = 0
+				ask = decimal.Zero
 			}
 		case a.Pair.Quote == b.Pair.Base: // A/C, C/B
 			pair.Base = a.Pair.Base
 			pair.Quote = b.Pair.Quote
-			price = a.Price * b.Price
-			bid = a.Bid * b.Bid
-			ask = a.Ask * b.Ask
+			price = a.Price.Mul(b.Price)
+			bid = a.Bid.Mul(b.Bid)
+			ask = a.Ask.Mul(b.Ask)
 		case a.Pair.Base == b.Pair.Quote: // C/A, B/C -> A/B
 			pair.Base = a.Pair.Quote
 			pair.Quote = b.Pair.Base
 
-			if a.Price > 0 && b.Price > 0 {
-				price = (float64(1) / b.Price) / a.Price
+			if a.Price.IsPositive() && b.Price.IsPositive() {
+				price = one.Div(b.Price).Div(a.Price)
 			} else {
 				err = multierror.Append(err, ErrDivByZero{a.Pair, b.Pair})
-				price = 0
+				price = decimal.Zero
 			}
 
-			if a.Bid > 0 && b.Bid > 0 {
-				bid = (float64(1) / b.Bid) / a.Bid
+			if a.Bid.IsPositive() && b.Bid.IsPositive() {
+				bid = one.Div(b.Bid).Div(a.Bid)
 			} else {
-				bid = 0
+				bid = decimal.Zero
 			}
 
-			if a.Ask > 0 && b.Ask > 0 {
-				ask = (float64(1) / b.Ask) / a.Ask
+			if a.Ask.IsPositive() && b.Ask.IsPositive() {
+				ask = one.Div(b.Ask).Div(a.Ask)
 			} else {
-				ask = 0
+				ask = decimal.Zero
 			}
 		default:
 			err = multierror.Append(err, ErrNoCommonPart{a.Pair, b.Pair})
@@ -287,7 +292,7 @@ func crossRate(t []PairPrice) (PairPrice, error) {
 		b.Price = price
 		b.Bid = bid
 		b.Ask = ask
-		b.Volume24h = 0
+		b.Volume24h = decimal.Zero
 		if a.Time.Before(b.Time) {
 			b.Time = a.Time
 		}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -52,10 +54,10 @@ func TestIndirectAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -65,10 +67,10 @@ func TestIndirectAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -78,10 +80,10 @@ func TestIndirectAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c3.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p3,
-			Price:     30,
-			Bid:       30,
-			Ask:       30,
-			Volume24h: 30,
+			Price:     decimal.NewFromInt(30),
+			Bid:       decimal.NewFromInt(30),
+			Ask:       decimal.NewFromInt(30),
+			Volume24h: decimal.NewFromInt(30),
 			Time:      n,
 		},
 		Origin: "c",
@@ -95,10 +97,10 @@ func TestIndirectAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	expected := AggregatorPrice{
 		PairPrice: PairPrice{
 			Pair:      pf,
-			Price:     6000,
-			Bid:       6000,
-			Ask:       6000,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(6000),
+			Bid:       decimal.NewFromInt(6000),
+			Ask:       decimal.NewFromInt(6000),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		OriginPrices:     []OriginPrice{c1.Price(), c2.Price(), c3.Price()},
@@ -135,10 +137,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -148,10 +150,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -161,10 +163,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c3.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p3,
-			Price:     30,
-			Bid:       30,
-			Ask:       30,
-			Volume24h: 30,
+			Price:     decimal.NewFromInt(30),
+			Bid:       decimal.NewFromInt(30),
+			Ask:       decimal.NewFromInt(30),
+			Volume24h: decimal.NewFromInt(30),
 			Time:      n,
 		},
 		Origin: "c",
@@ -178,10 +180,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	expected := AggregatorPrice{
 		PairPrice: PairPrice{
 			Pair:      pf,
-			Price:     6000,
-			Bid:       6000,
-			Ask:       6000,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(6000),
+			Bid:       decimal.NewFromInt(6000),
+			Ask:       decimal.NewFromInt(6000),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		OriginPrices: nil,
@@ -189,10 +191,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p1,
-					Price:     10,
-					Bid:       10,
-					Ask:       10,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(10),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c1.Price()},
@@ -203,10 +205,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p2,
-					Price:     20,
-					Bid:       20,
-					Ask:       20,
-					Volume24h: 20,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(20),
+					Ask:       decimal.NewFromInt(20),
+					Volume24h: decimal.NewFromInt(20),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c2.Price()},
@@ -217,10 +219,10 @@ func TestIndirectAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p3,
-					Price:     30,
-					Bid:       30,
-					Ask:       30,
-					Volume24h: 30,
+					Price:     decimal.NewFromInt(30),
+					Bid:       decimal.NewFromInt(30),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(30),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c3.Price()},
@@ -250,10 +252,10 @@ func TestIndirectAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -263,10 +265,10 @@ func TestIndirectAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -294,10 +296,10 @@ func TestIndirectAggregatorNode_Price_ResolveToWrongPair(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -307,10 +309,10 @@ func TestIndirectAggregatorNode_Price_ResolveToWrongPair(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -338,10 +340,10 @@ func TestIndirectAggregatorNode_Price_UnableToResolve(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -351,10 +353,10 @@ func TestIndirectAggregatorNode_Price_UnableToResolve(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -382,10 +384,10 @@ func TestIndirectAggregatorNode_Price_DivByZero(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -395,10 +397,10 @@ func TestIndirectAggregatorNode_Price_DivByZero(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     0,
-			Bid:       0,
-			Ask:       0,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(0),
+			Bid:       decimal.NewFromInt(0),
+			Ask:       decimal.NewFromInt(0),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		Origin: "b",
@@ -431,19 +433,19 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "A", Quote: "B"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "B"},
-				Price:     10,
-				Bid:       5,
-				Ask:       15,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10),
+				Bid:       decimal.NewFromInt(5),
+				Ask:       decimal.NewFromInt(15),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			wantErr: false,
@@ -462,27 +464,27 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "A", Quote: "C"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "C"},
-				Price:     float64(10) / 20,
-				Bid:       float64(5) / 10,
-				Ask:       float64(15) / 30,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(10).Div(decimal.NewFromInt(20)),
+				Bid:       decimal.NewFromInt(5).Div(decimal.NewFromInt(10)),
+				Ask:       decimal.NewFromInt(15).Div(decimal.NewFromInt(30)),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			wantErr: false,
@@ -492,18 +494,18 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "A", Quote: "C"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     0,
-					Bid:       0,
-					Ask:       0,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(0),
+					Bid:       decimal.NewFromInt(0),
+					Ask:       decimal.NewFromInt(0),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
@@ -515,27 +517,27 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "A"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "B"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "C"},
-				Price:     float64(20) / 10,
-				Bid:       float64(10) / 5,
-				Ask:       float64(30) / 15,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(20).Div(decimal.NewFromInt(10)),
+				Bid:       decimal.NewFromInt(10).Div(decimal.NewFromInt(5)),
+				Ask:       decimal.NewFromInt(30).Div(decimal.NewFromInt(15)),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			wantErr: false,
@@ -545,18 +547,18 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "A"},
-					Price:     0,
-					Bid:       0,
-					Ask:       0,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(0),
+					Bid:       decimal.NewFromInt(0),
+					Ask:       decimal.NewFromInt(0),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "B"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
@@ -568,27 +570,27 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "A", Quote: "C"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "B"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "C"},
-				Price:     float64(20) * 10,
-				Bid:       float64(10) * 5,
-				Ask:       float64(30) * 15,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(20).Mul(decimal.NewFromInt(10)),
+				Bid:       decimal.NewFromInt(10).Mul(decimal.NewFromInt(5)),
+				Ask:       decimal.NewFromInt(30).Mul(decimal.NewFromInt(15)),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			wantErr: false,
@@ -598,27 +600,27 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "A"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "C"},
-				Price:     float64(1) / 20 / 10,
-				Bid:       float64(1) / 10 / 5,
-				Ask:       float64(1) / 30 / 15,
-				Volume24h: 10,
+				Price:     decimal.NewFromInt(1).Div(decimal.NewFromInt(20)).Div(decimal.NewFromInt(10)),
+				Bid:       decimal.NewFromInt(1).Div(decimal.NewFromInt(10)).Div(decimal.NewFromInt(5)),
+				Ask:       decimal.NewFromInt(1).Div(decimal.NewFromInt(30)).Div(decimal.NewFromInt(15)),
+				Volume24h: decimal.NewFromInt(10),
 				Time:      time.Unix(10, 0),
 			},
 			wantErr: false,
@@ -628,18 +630,18 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "A"},
-					Price:     10,
-					Bid:       5,
-					Ask:       15,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(5),
+					Ask:       decimal.NewFromInt(15),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     0,
-					Bid:       0,
-					Ask:       0,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(0),
+					Bid:       decimal.NewFromInt(0),
+					Ask:       decimal.NewFromInt(0),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(10, 0),
 				},
 			},
@@ -651,18 +653,18 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "A"},
-					Price:     0,
-					Bid:       0,
-					Ask:       0,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(0),
+					Bid:       decimal.NewFromInt(0),
+					Ask:       decimal.NewFromInt(0),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     20,
-					Bid:       10,
-					Ask:       30,
-					Volume24h: 10,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 			},
@@ -674,35 +676,35 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "A", Quote: "B"},
-					Price:     1,
-					Bid:       1,
-					Ask:       1,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(1),
+					Bid:       decimal.NewFromInt(1),
+					Ask:       decimal.NewFromInt(1),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "B", Quote: "C"},
-					Price:     1,
-					Bid:       1,
-					Ask:       1,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(1),
+					Bid:       decimal.NewFromInt(1),
+					Ask:       decimal.NewFromInt(1),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(5, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "C", Quote: "D"},
-					Price:     1,
-					Bid:       1,
-					Ask:       1,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(1),
+					Bid:       decimal.NewFromInt(1),
+					Ask:       decimal.NewFromInt(1),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      time.Unix(15, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "A", Quote: "D"},
-				Price:     1,
-				Bid:       1,
-				Ask:       1,
-				Volume24h: 0,
+				Price:     decimal.NewFromInt(1),
+				Bid:       decimal.NewFromInt(1),
+				Ask:       decimal.NewFromInt(1),
+				Volume24h: decimal.NewFromInt(0),
 				Time:      time.Unix(5, 0),
 			},
 			wantErr: false,
@@ -712,51 +714,51 @@ func Test_crossRate(t *testing.T) {
 			prices: []PairPrice{
 				{
 					Pair:      gofer.Pair{Base: "ETH", Quote: "BTC"}, // -> ETH/BTC
-					Price:     0.050,
-					Bid:       0.040,
-					Ask:       0.060,
-					Volume24h: 10,
+					Price:     decimal.RequireFromString("0.050"),
+					Bid:       decimal.RequireFromString("0.040"),
+					Ask:       decimal.RequireFromString("0.060"),
+					Volume24h: decimal.NewFromInt(10),
 					Time:      time.Unix(10, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "BTC", Quote: "USD"}, // -> ETH/USD
-					Price:     10000.000,
-					Bid:       9000.000,
-					Ask:       11000.000,
-					Volume24h: 15,
+					Price:     decimal.RequireFromString("10000.000"),
+					Bid:       decimal.RequireFromString("9000.000"),
+					Ask:       decimal.RequireFromString("11000.000"),
+					Volume24h: decimal.NewFromInt(15),
 					Time:      time.Unix(9, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "EUR", Quote: "USD"}, // -> ETH/EUR
-					Price:     1.250,
-					Bid:       1.200,
-					Ask:       1.300,
-					Volume24h: 20,
+					Price:     decimal.RequireFromString("1.250"),
+					Bid:       decimal.RequireFromString("1.200"),
+					Ask:       decimal.RequireFromString("1.300"),
+					Volume24h: decimal.NewFromInt(20),
 					Time:      time.Unix(11, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "EUR", Quote: "CAD"}, // -> ETH/CAD
-					Price:     1.250,
-					Bid:       1.200,
-					Ask:       1.300,
-					Volume24h: 25,
+					Price:     decimal.RequireFromString("1.250"),
+					Bid:       decimal.RequireFromString("1.200"),
+					Ask:       decimal.RequireFromString("1.300"),
+					Volume24h: decimal.NewFromInt(25),
 					Time:      time.Unix(8, 0),
 				},
 				{
 					Pair:      gofer.Pair{Base: "GPB", Quote: "ETH"}, // -> GPB/CAD
-					Price:     0.005,
-					Bid:       0.004,
-					Ask:       0.006,
-					Volume24h: 30,
+					Price:     decimal.RequireFromString("0.005"),
+					Bid:       decimal.RequireFromString("0.004"),
+					Ask:       decimal.RequireFromString("0.006"),
+					Volume24h: decimal.NewFromInt(30),
 					Time:      time.Unix(13, 0),
 				},
 			},
 			want: PairPrice{
 				Pair:      gofer.Pair{Base: "GPB", Quote: "CAD"},
-				Price:     float64(1) / (((float64(0.050) * 10000.000) / 1.250) * 1.250) / 0.005,
-				Bid:       float64(1) / (((float64(0.040) * 9000.000) / 1.200) * 1.200) / 0.004,
-				Ask:       float64(1) / (((float64(0.060) * 11000.000) / 1.300) * 1.300) / 0.006,
-				Volume24h: 0,
+				Price:     decimal.NewFromInt(1).Div(decimal.RequireFromString("0.050").Mul(decimal.RequireFromString("10000.000")).Div(decimal.RequireFromString("1.250")).Mul(decimal.RequireFromString("1.250"))).Div(decimal.RequireFromString("0.005")),
+				Bid:       decimal.NewFromInt(1).Div(decimal.RequireFromString("0.040").Mul(decimal.RequireFromString("9000.000")).Div(decimal.RequireFromString("1.200")).Mul(decimal.RequireFromString("1.200"))).Div(decimal.RequireFromString("0.004")),
+				Ask:       decimal.NewFromInt(1).Div(decimal.RequireFromString("0.060").Mul(decimal.RequireFromString("11000.000")).Div(decimal.RequireFromString("1.300")).Mul(decimal.RequireFromString("1.300"))).Div(decimal.RequireFromString("0.006")),
+				Volume24h: decimal.NewFromInt(0),
 				Time:      time.Unix(8, 0),
 			},
 			wantErr: false,
@@ -775,11 +777,50 @@ func Test_crossRate(t *testing.T) {
 				return
 			}
 
-			assert.InDelta(t, tt.want.Price, got.Price, 0.000000001)
-			assert.InDelta(t, tt.want.Bid, got.Bid, 0.000000001)
-			assert.InDelta(t, tt.want.Ask, got.Ask, 0.000000001)
+			assert.Equal(t, tt.want.Price, got.Price)
+			assert.Equal(t, tt.want.Bid, got.Bid)
+			assert.Equal(t, tt.want.Ask, got.Ask)
 			assert.Equal(t, tt.want.Time.Unix(), got.Time.Unix())
 			assert.Nil(t, err)
 		})
 	}
 }
+
+func Test_crossRate_precision(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string // BASE/USD price
+		quote string // QUOTE/USD price
+	}{
+		{name: "very-small", base: "0.000008123456789012", quote: "3456.789012345678"},
+		{name: "very-large", base: "98765432109876543210.123456789", quote: "0.000000000000000001"},
+		{name: "non-terminating", base: "1", quote: "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := decimal.RequireFromString(tt.base)
+			quote := decimal.RequireFromString(tt.quote)
+
+			// BASE/USD, QUOTE/USD -> BASE/QUOTE
+			got, err := crossRate([]PairPrice{
+				{Pair: gofer.Pair{Base: "BASE", Quote: "USD"}, Price: base, Bid: base, Ask: base},
+				{Pair: gofer.Pair{Base: "QUOTE", Quote: "USD"}, Price: quote, Bid: quote, Ask: quote},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, gofer.Pair{Base: "BASE", Quote: "QUOTE"}, got.Pair)
+
+			// The calculated price must be exact, so the original price
+			// can be restored without any drift:
+			assert.Equal(t, base, got.Price.Mul(quote))
+
+			// BASE/QUOTE, QUOTE/USD -> BASE/USD
+			back, err := crossRate([]PairPrice{
+				{Pair: gofer.Pair{Base: "BASE", Quote: "QUOTE"}, Price: got.Price, Bid: got.Bid, Ask: got.Ask},
+				{Pair: gofer.Pair{Base: "QUOTE", Quote: "USD"}, Price: quote, Bid: quote, Ask: quote},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, base, back.Price)
+			assert.Equal(t, tt.base, back.Price.String())
+		})
+	}
+}
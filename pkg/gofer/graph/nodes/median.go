@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -78,7 +79,7 @@ func (n *MedianAggregatorNode) Pair() gofer.Pair {
 
 func (n *MedianAggregatorNode) Price() AggregatorPrice {
 	var ts time.Time
-	var prices, bids, asks []float64
+	var prices, bids, asks []decimal.Decimal
 	var originPrices []OriginPrice
 	var aggregatorPrices []AggregatorPrice
 	var err error
@@ -113,13 +114,13 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 			continue
 		}
 
-		if price.Price > 0 {
+		if price.Price.IsPositive() {
 			prices = append(prices, price.Price)
 		}
-		if price.Bid > 0 {
+		if price.Bid.IsPositive() {
 			bids = append(bids, price.Bid)
 		}
-		if price.Ask > 0 {
+		if price.Ask.IsPositive() {
 			asks = append(asks, price.Ask)
 		}
 		if i == 0 || price.Time.Before(ts) {
@@ -140,7 +141,7 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 			Price:     median(prices),
 			Bid:       median(bids),
 			Ask:       median(asks),
-			Volume24h: 0,
+			Volume24h: decimal.Zero,
 			Time:      ts,
 		},
 		OriginPrices:     originPrices,
@@ -150,18 +151,20 @@ func (n *MedianAggregatorNode) Price() AggregatorPrice {
 	}
 }
 
-func median(xs []float64) float64 {
+func median(xs []decimal.Decimal) decimal.Decimal {
 	count := len(xs)
 	if count == 0 {
-		return 0
+		return decimal.Zero
 	}
 
-	sort.Float64s(xs)
+	sort.Slice(xs, func(i, j int) bool {
+		return xs[i].Cmp(xs[j]) < 0
+	})
 	if count%2 == 0 {
 		m := count / 2
 		x1 := xs[m-1]
 		x2 := xs[m]
-		return (x1 + x2) / 2
+		return x1.Add(x2).Div(decimal.NewFromInt(2))
 	}
 
 	return xs[(count-1)/2]
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -48,10 +49,10 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -61,10 +62,10 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -74,10 +75,10 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	_ = c3.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     30,
-			Bid:       30,
-			Ask:       30,
-			Volume24h: 30,
+			Price:     decimal.NewFromInt(30),
+			Bid:       decimal.NewFromInt(30),
+			Ask:       decimal.NewFromInt(30),
+			Volume24h: decimal.NewFromInt(30),
 			Time:      n,
 		},
 		Origin: "c",
@@ -91,10 +92,10 @@ func TestMedianAggregatorNode_Price_ThreeOriginPrices(t *testing.T) {
 	expected := AggregatorPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		OriginPrices:     []OriginPrice{c1.Price(), c2.Price(), c3.Price()},
@@ -127,10 +128,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -140,10 +141,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -153,10 +154,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	_ = c3.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     30,
-			Bid:       30,
-			Ask:       30,
-			Volume24h: 30,
+			Price:     decimal.NewFromInt(30),
+			Bid:       decimal.NewFromInt(30),
+			Ask:       decimal.NewFromInt(30),
+			Volume24h: decimal.NewFromInt(30),
 			Time:      n,
 		},
 		Origin: "c",
@@ -170,10 +171,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 	expected := AggregatorPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		OriginPrices: nil,
@@ -181,10 +182,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p,
-					Price:     10,
-					Bid:       10,
-					Ask:       10,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(10),
+					Bid:       decimal.NewFromInt(10),
+					Ask:       decimal.NewFromInt(10),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c1.Price()},
@@ -195,10 +196,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p,
-					Price:     20,
-					Bid:       20,
-					Ask:       20,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(20),
+					Bid:       decimal.NewFromInt(20),
+					Ask:       decimal.NewFromInt(20),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c2.Price()},
@@ -209,10 +210,10 @@ func TestMedianAggregatorNode_Price_ThreeAggregatorPrices(t *testing.T) {
 			{
 				PairPrice: PairPrice{
 					Pair:      p,
-					Price:     30,
-					Bid:       30,
-					Ask:       30,
-					Volume24h: 0,
+					Price:     decimal.NewFromInt(30),
+					Bid:       decimal.NewFromInt(30),
+					Ask:       decimal.NewFromInt(30),
+					Volume24h: decimal.NewFromInt(0),
 					Time:      n,
 				},
 				OriginPrices:     []OriginPrice{c3.Price()},
@@ -238,10 +239,10 @@ func TestMedianAggregatorNode_Price_NotEnoughSources(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -255,9 +256,9 @@ func TestMedianAggregatorNode_Price_NotEnoughSources(t *testing.T) {
 	assert.True(t, errors.As(price.Error, &ErrNotEnoughSources{}))
 
 	// If possible, the median should be calculated for the rest of the prices:
-	assert.Equal(t, float64(10), price.Price)
-	assert.Equal(t, float64(10), price.Bid)
-	assert.Equal(t, float64(10), price.Ask)
+	assert.Equal(t, decimal.NewFromInt(10), price.Price)
+	assert.Equal(t, decimal.NewFromInt(10), price.Bid)
+	assert.Equal(t, decimal.NewFromInt(10), price.Ask)
 }
 
 func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
@@ -271,10 +272,10 @@ func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -284,10 +285,10 @@ func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -302,9 +303,9 @@ func TestMedianAggregatorNode_Price_ChildPriceWithError(t *testing.T) {
 	assert.True(t, errors.As(price.Error, &ErrNotEnoughSources{}))
 
 	// If possible, the median should be calculated for the rest of the prices:
-	assert.Equal(t, float64(10), price.Price)
-	assert.Equal(t, float64(10), price.Bid)
-	assert.Equal(t, float64(10), price.Ask)
+	assert.Equal(t, decimal.NewFromInt(10), price.Price)
+	assert.Equal(t, decimal.NewFromInt(10), price.Bid)
+	assert.Equal(t, decimal.NewFromInt(10), price.Ask)
 }
 
 func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
@@ -319,10 +320,10 @@ func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p1,
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      n,
 		},
 		Origin: "a",
@@ -332,10 +333,10 @@ func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p2,
-			Price:     20,
-			Bid:       20,
-			Ask:       20,
-			Volume24h: 20,
+			Price:     decimal.NewFromInt(20),
+			Bid:       decimal.NewFromInt(20),
+			Ask:       decimal.NewFromInt(20),
+			Volume24h: decimal.NewFromInt(20),
 			Time:      n,
 		},
 		Origin: "b",
@@ -350,9 +351,9 @@ func TestMedianAggregatorNode_Price_IncompatiblePairs(t *testing.T) {
 	assert.True(t, errors.As(price.Error, &ErrIncompatiblePairs{}))
 
 	// If possible, the median should be calculated for the rest of the prices:
-	assert.Equal(t, float64(10), price.Price)
-	assert.Equal(t, float64(10), price.Bid)
-	assert.Equal(t, float64(10), price.Ask)
+	assert.Equal(t, decimal.NewFromInt(10), price.Price)
+	assert.Equal(t, decimal.NewFromInt(10), price.Bid)
+	assert.Equal(t, decimal.NewFromInt(10), price.Ask)
 }
 
 func TestMedianAggregatorNode_Price_NoChildrenNodes(t *testing.T) {
@@ -376,10 +377,10 @@ func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 	_ = c1.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     10,
-			Bid:       0,
-			Ask:       0,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(0),
+			Ask:       decimal.NewFromInt(0),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		Origin: "a",
@@ -389,10 +390,10 @@ func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 	_ = c2.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     0,
-			Bid:       10,
-			Ask:       0,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(0),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(0),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		Origin: "b",
@@ -402,10 +403,10 @@ func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 	_ = c3.Ingest(OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      p,
-			Price:     0,
-			Bid:       0,
-			Ask:       10,
-			Volume24h: 0,
+			Price:     decimal.NewFromInt(0),
+			Bid:       decimal.NewFromInt(0),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(0),
 			Time:      n,
 		},
 		Origin: "c",
@@ -418,36 +419,52 @@ func TestMedianAggregatorNode_Price_FilterOutPricesLteZero(t *testing.T) {
 
 	price := m.Price()
 
-	assert.Equal(t, float64(10), price.Price)
-	assert.Equal(t, float64(10), price.Bid)
-	assert.Equal(t, float64(10), price.Ask)
+	assert.Equal(t, decimal.NewFromInt(10), price.Price)
+	assert.Equal(t, decimal.NewFromInt(10), price.Bid)
+	assert.Equal(t, decimal.NewFromInt(10), price.Ask)
 }
 
 func Test_median(t *testing.T) {
 	tests := []struct {
 		name   string
-		prices []float64
-		want   float64
+		prices []decimal.Decimal
+		want   decimal.Decimal
 	}{
 		{
 			name:   "no-prices",
-			prices: []float64{},
-			want:   float64(0),
+			prices: []decimal.Decimal{},
+			want:   decimal.Zero,
 		},
 		{
 			name:   "one-price",
-			prices: []float64{10},
-			want:   float64(10),
+			prices: []decimal.Decimal{decimal.NewFromInt(10)},
+			want:   decimal.NewFromInt(10),
 		},
 		{
 			name:   "three-prices",
-			prices: []float64{-20, 10, 20},
-			want:   float64(10),
+			prices: []decimal.Decimal{decimal.NewFromInt(-20), decimal.NewFromInt(10), decimal.NewFromInt(20)},
+			want:   decimal.NewFromInt(10),
 		},
 		{
 			name:   "four-prices",
-			prices: []float64{10, 20, 30, 40},
-			want:   float64(25),
+			prices: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(20), decimal.NewFromInt(30), decimal.NewFromInt(40)},
+			want:   decimal.NewFromInt(25),
+		},
+		{
+			name: "small-prices",
+			prices: []decimal.Decimal{
+				decimal.RequireFromString("0.000000006137000000000000001"),
+				decimal.RequireFromString("0.000000006137000000000000003"),
+			},
+			want: decimal.RequireFromString("0.000000006137000000000000002"),
+		},
+		{
+			name: "large-prices",
+			prices: []decimal.Decimal{
+				decimal.RequireFromString("123456789012345678901234567890.1"),
+				decimal.RequireFromString("123456789012345678901234567890.2"),
+			},
+			want: decimal.RequireFromString("123456789012345678901234567890.15"),
 		},
 	}
 
@@ -56,6 +56,7 @@ type OriginNode struct {
 	price      OriginPrice
 	minTTL     time.Duration
 	maxTTL     time.Duration
+	clock      func() time.Time
 }
 
 func NewOriginNode(originPair OriginPair, minTTL time.Duration, maxTTL time.Duration) *OriginNode {
@@ -98,6 +99,15 @@ func (n *OriginNode) Ingest(price OriginPrice) error {
 	return err
 }
 
+// SetClock sets the function used to get the current time while checking
+// if the price is expired. By default, time.Now is used. It allows to replay
+// historical prices.
+func (n *OriginNode) SetClock(clock func() time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.clock = clock
+}
+
 // MinTTL implements the Feedable interface.
 func (n *OriginNode) MinTTL() time.Duration {
 	return n.minTTL
@@ -138,5 +148,9 @@ func (n *OriginNode) Children() []Node {
 }
 
 func (n *OriginNode) expired() bool {
-	return n.price.Time.Before(time.Now().Add(-1 * n.MaxTTL()))
+	now := time.Now
+	if n.clock != nil {
+		now = n.clock
+	}
+	return n.price.Time.Before(now().Add(-1 * n.MaxTTL()))
 }
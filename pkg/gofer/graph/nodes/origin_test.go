@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -31,10 +32,10 @@ func TestOriginNode_Ingest_Valid(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now(),
 		},
 		Origin: "foo",
@@ -59,10 +60,10 @@ func TestOriginNode_Ingest_IncompatiblePair(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "C"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now(),
 		},
 		Origin: "foo",
@@ -85,10 +86,10 @@ func TestOriginNode_Ingest_IncompatibleOrigin(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now(),
 		},
 		Origin: "bar",
@@ -111,10 +112,10 @@ func TestOriginNode_Ingest_IncompatibleEverything(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "C"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now(),
 		},
 		Origin: "bar",
@@ -140,10 +141,10 @@ func TestOriginNode_Ingest_PriceWithError(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now(),
 		},
 		Origin: "foo",
@@ -166,10 +167,10 @@ func TestOriginNode_Price_Expired(t *testing.T) {
 	ot := OriginPrice{
 		PairPrice: PairPrice{
 			Pair:      gofer.Pair{Base: "A", Quote: "B"},
-			Price:     10,
-			Bid:       10,
-			Ask:       10,
-			Volume24h: 10,
+			Price:     decimal.NewFromInt(10),
+			Bid:       decimal.NewFromInt(10),
+			Ask:       decimal.NewFromInt(10),
+			Volume24h: decimal.NewFromInt(10),
 			Time:      time.Now().Add(-20 * time.Second),
 		},
 		Origin: "foo",
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
 )
 
@@ -18,10 +19,10 @@ func (o OriginPair) String() string {
 
 type PairPrice struct {
 	Pair      gofer.Pair
-	Price     float64
-	Bid       float64
-	Ask       float64
-	Volume24h float64
+	Price     decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Volume24h decimal.Decimal
 	Time      time.Time
 }
 
@@ -0,0 +1,333 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+const (
+	segmentPrefix     = "history-"
+	segmentSuffix     = ".ndjson"
+	segmentDateLayout = "2006-01-02"
+	segmentDuration   = 24 * time.Hour
+)
+
+// pruneInterval is the minimum time between two checks of the retention
+// limits.
+const pruneInterval = time.Minute
+
+// FileStoreConfig is the configuration for the FileStore.
+type FileStoreConfig struct {
+	// Path is a directory in which history files are stored. It is created
+	// if it does not exist.
+	Path string
+	// MaxAge is the time after which records are removed. Records are
+	// stored in daily files, so they are removed with the daily precision.
+	// Zero means that records are never removed because of their age.
+	MaxAge time.Duration
+	// MaxSize is the maximum size of all history files in bytes. If the limit
+	// is exceeded, the oldest files are removed. The file with the most
+	// recent records is never removed. Zero means no limit.
+	MaxSize int64
+}
+
+// FileStore implements the Store interface. Records are stored in
+// the NDJSON format, in one append-only file per day (UTC).
+type FileStore struct {
+	mu sync.Mutex
+
+	path      string
+	maxAge    time.Duration
+	maxSize   int64
+	lastPrune time.Time
+}
+
+// NewFileStore returns a new FileStore instance.
+func NewFileStore(cfg FileStoreConfig) (*FileStore, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("history path must not be empty")
+	}
+	if cfg.MaxAge < 0 {
+		return nil, errors.New("history max age must not be negative")
+	}
+	if cfg.MaxSize < 0 {
+		return nil, errors.New("history max size must not be negative")
+	}
+	if err := os.MkdirAll(cfg.Path, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create history directory: %w", err)
+	}
+	return &FileStore{
+		path:    cfg.Path,
+		maxAge:  cfg.MaxAge,
+		maxSize: cfg.MaxSize,
+	}, nil
+}
+
+// Add implements the Store interface.
+func (s *FileStore) Add(records ...Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Group records by the file to which they belong:
+	bufs := map[string]*bytes.Buffer{}
+	var names []string
+	for _, r := range records {
+		name := segmentName(r.Time)
+		if _, ok := bufs[name]; !ok {
+			bufs[name] = &bytes.Buffer{}
+			names = append(names, name)
+		}
+		b, err := json.Marshal(mapRecordToJSON(r))
+		if err != nil {
+			return err
+		}
+		bufs[name].Write(b)
+		bufs[name].WriteByte('\n')
+	}
+	for _, name := range names {
+		if err := s.appendFile(name, bufs[name].Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if time.Since(s.lastPrune) >= pruneInterval {
+		s.lastPrune = time.Now()
+		return s.prune(time.Now())
+	}
+	return nil
+}
+
+// Query implements the Store interface.
+func (s *FileStore) Query(q Query) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, seg := range segments {
+		if !q.From.IsZero() && seg.day.Add(segmentDuration).Before(q.From) {
+			continue
+		}
+		if !q.To.IsZero() && seg.day.After(q.To) {
+			continue
+		}
+		rs, err := s.readFile(seg.name, q)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rs...)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records, nil
+}
+
+func (s *FileStore) appendFile(name string, b []byte) error {
+	f, err := os.OpenFile(filepath.Join(s.path, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *FileStore) readFile(name string, q Query) ([]Record, error) {
+	f, err := os.Open(filepath.Join(s.path, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Decode(f, q)
+}
+
+// prune removes files which exceed retention limits.
+func (s *FileStore) prune(now time.Time) error {
+	segments, err := s.segments()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		expired := s.maxAge > 0 && now.Sub(seg.day.Add(segmentDuration)) > s.maxAge
+		oversize := s.maxSize > 0 && size+seg.size > s.maxSize && i != len(segments)-1
+		if expired || oversize {
+			if err := os.Remove(filepath.Join(s.path, seg.name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		size += seg.size
+	}
+	return nil
+}
+
+type segment struct {
+	name string
+	day  time.Time
+	size int64
+}
+
+// segments returns a list of history files ordered by date.
+func (s *FileStore) segments() ([]segment, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var segments []segment
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), segmentPrefix) || !strings.HasSuffix(e.Name(), segmentSuffix) {
+			continue
+		}
+		date := strings.TrimSuffix(strings.TrimPrefix(e.Name(), segmentPrefix), segmentSuffix)
+		day, err := time.Parse(segmentDateLayout, date)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment{name: e.Name(), day: day, size: info.Size()})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].day.Before(segments[j].day)
+	})
+	return segments, nil
+}
+
+func segmentName(t time.Time) string {
+	return segmentPrefix + t.UTC().Format(segmentDateLayout) + segmentSuffix
+}
+
+// Decode reads records in the NDJSON format from the reader and returns
+// those matching the query, in the order in which they were read. Besides
+// the format used by the FileStore, records in the format returned by the
+// "gofer history" command are accepted. Invalid lines are skipped.
+func Decode(r io.Reader, q Query) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var jr jsonRecord
+		// The last line may be incomplete if the file is being written
+		// at the same time:
+		if err := json.Unmarshal(scanner.Bytes(), &jr); err != nil {
+			continue
+		}
+		r, err := mapJSONToRecord(jr)
+		if err != nil {
+			continue
+		}
+		if q.Match(r) {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+type jsonRecord struct {
+	Type      string          `json:"type"`
+	Origin    string          `json:"origin,omitempty"`
+	Path      string          `json:"path,omitempty"`
+	Pair      string          `json:"pair,omitempty"`
+	Base      string          `json:"base,omitempty"`
+	Quote     string          `json:"quote,omitempty"`
+	Price     decimal.Decimal `json:"price"`
+	Bid       decimal.Decimal `json:"bid"`
+	Ask       decimal.Decimal `json:"ask"`
+	Volume24h decimal.Decimal `json:"vol24h"`
+	Time      time.Time       `json:"ts"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func mapRecordToJSON(r Record) jsonRecord {
+	return jsonRecord{
+		Type:      r.Type,
+		Origin:    r.Origin,
+		Path:      r.Path,
+		Pair:      r.Pair.String(),
+		Price:     r.Price,
+		Bid:       r.Bid,
+		Ask:       r.Ask,
+		Volume24h: r.Volume24h,
+		Time:      r.Time.In(time.UTC),
+		Error:     r.Error,
+	}
+}
+
+func mapJSONToRecord(jr jsonRecord) (Record, error) {
+	if jr.Pair == "" {
+		jr.Pair = jr.Base + "/" + jr.Quote
+	}
+	pair, err := gofer.NewPair(jr.Pair)
+	if err != nil {
+		return Record{}, err
+	}
+	if jr.Type != OriginType && jr.Type != AggregatorType {
+		return Record{}, fmt.Errorf("unknown record type: %q", jr.Type)
+	}
+	return Record{
+		Type:      jr.Type,
+		Origin:    jr.Origin,
+		Path:      jr.Path,
+		Pair:      pair,
+		Price:     jr.Price,
+		Bid:       jr.Bid,
+		Ask:       jr.Ask,
+		Volume24h: jr.Volume24h,
+		Time:      jr.Time,
+		Error:     jr.Error,
+	}, nil
+}
+
+// Load returns records matching the query from the given path, ordered by
+// time. The path may be either a directory used by the FileStore or a single
+// file with records in the NDJSON format.
+func Load(path string, q Query) ([]Record, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		s, err := NewFileStore(FileStoreConfig{Path: path})
+		if err != nil {
+			return nil, err
+		}
+		return s.Query(q)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := Decode(f, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records, nil
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+var (
+	btcusd = gofer.Pair{Base: "BTC", Quote: "USD"}
+	ethusd = gofer.Pair{Base: "ETH", Quote: "USD"}
+)
+
+func testRecord(typ, origin string, pair gofer.Pair, price string, t time.Time) Record {
+	return Record{
+		Type:   typ,
+		Origin: origin,
+		Pair:   pair,
+		Price:  decimal.RequireFromString(price),
+		Time:   t,
+	}
+}
+
+func TestFileStore_AddAndQuery(t *testing.T) {
+	s, err := NewFileStore(FileStoreConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	t1 := time.Date(2021, 5, 18, 23, 59, 0, 0, time.UTC)
+	t2 := time.Date(2021, 5, 19, 0, 1, 0, 0, time.UTC)
+	t3 := time.Date(2021, 5, 20, 12, 0, 0, 0, time.UTC)
+
+	r1 := testRecord(OriginType, "binance", btcusd, "45227.05", t1)
+	r2 := testRecord(OriginType, "bitstamp", btcusd, "0.000000002713742016", t2)
+	r3 := testRecord(AggregatorType, "", btcusd, "45242.13", t3)
+	r4 := testRecord(OriginType, "binance", ethusd, "3500", t2)
+	r2.Error = "something"
+
+	require.NoError(t, s.Add(r3, r1))
+	require.NoError(t, s.Add(r2, r4))
+
+	// All records for a pair, ordered by time:
+	rs, err := s.Query(Query{Pair: btcusd})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{r1, r2, r3}, rs)
+
+	// Time range:
+	rs, err = s.Query(Query{Pair: btcusd, From: t2, To: t2})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{r2}, rs)
+
+	// Origin:
+	rs, err = s.Query(Query{Pair: btcusd, Origin: "binance"})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{r1}, rs)
+
+	// No results:
+	rs, err = s.Query(Query{Pair: btcusd, From: t3.Add(time.Second)})
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+}
+
+func TestFileStore_SkipInvalidLines(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(FileStoreConfig{Path: dir})
+	require.NoError(t, err)
+
+	t1 := time.Date(2021, 5, 18, 0, 0, 0, 0, time.UTC)
+	r1 := testRecord(OriginType, "binance", btcusd, "1", t1)
+	require.NoError(t, s.Add(r1))
+
+	// Simulate a partially written line:
+	f, err := os.OpenFile(filepath.Join(dir, segmentName(t1)), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"origin","pair":"BTC/US`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	rs, err := s.Query(Query{Pair: btcusd})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{r1}, rs)
+}
+
+func TestFileStore_PruneMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(FileStoreConfig{Path: dir, MaxAge: 48 * time.Hour})
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Round(0)
+	old := testRecord(OriginType, "binance", btcusd, "1", now.Add(-4*24*time.Hour))
+	recent := testRecord(OriginType, "binance", btcusd, "2", now.Add(-24*time.Hour))
+	require.NoError(t, s.Add(old, recent))
+	require.NoError(t, s.prune(now))
+
+	rs, err := s.Query(Query{Pair: btcusd})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{recent}, rs)
+}
+
+func TestFileStore_PruneMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(FileStoreConfig{Path: dir, MaxSize: 1})
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Round(0)
+	r1 := testRecord(OriginType, "binance", btcusd, "1", now.Add(-48*time.Hour))
+	r2 := testRecord(OriginType, "binance", btcusd, "2", now.Add(-24*time.Hour))
+	r3 := testRecord(OriginType, "binance", btcusd, "3", now)
+	require.NoError(t, s.Add(r1, r2, r3))
+	require.NoError(t, s.prune(now))
+
+	// The most recent file must not be removed, even if it exceeds the limit:
+	rs, err := s.Query(Query{Pair: btcusd})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{r3}, rs)
+}
+
+func TestNewFileStore_InvalidConfig(t *testing.T) {
+	_, err := NewFileStore(FileStoreConfig{})
+	assert.Error(t, err)
+
+	_, err = NewFileStore(FileStoreConfig{Path: t.TempDir(), MaxAge: -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,215 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/graph/nodes"
+	"github.com/toknowwhy/theunit-oracle/pkg/log"
+)
+
+const LoggerTag = "GOFER_HISTORY"
+
+// Config is the configuration for the Gofer.
+type Config struct {
+	// Gofer is the instance whose prices are recorded.
+	Gofer gofer.Gofer
+	// Store is used to persist prices.
+	Store Store
+	// Interval specifies how often prices for all pairs are recorded.
+	// If zero, prices are recorded only when they are requested using
+	// the Price or Prices methods.
+	Interval time.Duration
+	Logger   log.Logger
+}
+
+// Gofer implements the gofer.StartableGofer interface. It wraps another
+// Gofer instance and records every origin and aggregator price it returns
+// in the Store. Prices which did not change since they were last recorded
+// are skipped.
+type Gofer struct {
+	gofer.Gofer
+
+	mu       sync.Mutex
+	ctx      context.Context
+	store    Store
+	interval time.Duration
+	last     map[recordKey]Record
+	log      log.Logger
+	doneCh   chan struct{}
+}
+
+type recordKey struct {
+	typ    string
+	origin string
+	path   string
+	pair   gofer.Pair
+}
+
+// NewGofer returns a new Gofer instance.
+func NewGofer(ctx context.Context, cfg Config) (*Gofer, error) {
+	if ctx == nil {
+		return nil, errors.New("context must not be nil")
+	}
+	if cfg.Gofer == nil {
+		return nil, errors.New("gofer must not be nil")
+	}
+	if cfg.Store == nil {
+		return nil, errors.New("store must not be nil")
+	}
+	return &Gofer{
+		Gofer:    cfg.Gofer,
+		ctx:      ctx,
+		store:    cfg.Store,
+		interval: cfg.Interval,
+		last:     map[recordKey]Record{},
+		log:      cfg.Logger.WithField("tag", LoggerTag),
+		doneCh:   make(chan struct{}),
+	}, nil
+}
+
+// Price implements the gofer.Gofer interface.
+func (g *Gofer) Price(pair gofer.Pair) (*gofer.Price, error) {
+	price, err := g.Gofer.Price(pair)
+	if err != nil {
+		return nil, err
+	}
+	g.record(price)
+	return price, nil
+}
+
+// Prices implements the gofer.Gofer interface.
+func (g *Gofer) Prices(pairs ...gofer.Pair) (map[gofer.Pair]*gofer.Price, error) {
+	prices, err := g.Gofer.Prices(pairs...)
+	if err != nil {
+		return nil, err
+	}
+	var ps []*gofer.Price
+	for _, p := range prices {
+		ps = append(ps, p)
+	}
+	g.record(ps...)
+	return prices, nil
+}
+
+// UpdateGraphs implements the graph.Updater interface. The call is passed
+// to the wrapped Gofer instance.
+func (g *Gofer) UpdateGraphs(graphs map[gofer.Pair]nodes.Aggregator) error {
+	u, ok := g.Gofer.(graph.Updater)
+	if !ok {
+		return errors.New("wrapped gofer does not support updating price models")
+	}
+	return u.UpdateGraphs(graphs)
+}
+
+// Start implements the gofer.StartableGofer interface. If the wrapped Gofer
+// also implements that interface, it is started too.
+func (g *Gofer) Start() error {
+	if sg, ok := g.Gofer.(gofer.StartableGofer); ok {
+		if err := sg.Start(); err != nil {
+			return err
+		}
+	}
+	go g.recorderRoutine()
+	return nil
+}
+
+// Wait implements the gofer.StartableGofer interface.
+func (g *Gofer) Wait() {
+	if sg, ok := g.Gofer.(gofer.StartableGofer); ok {
+		sg.Wait()
+	}
+	<-g.doneCh
+}
+
+func (g *Gofer) recorderRoutine() {
+	defer close(g.doneCh)
+	if g.interval <= 0 {
+		<-g.ctx.Done()
+		return
+	}
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := g.Prices(); err != nil {
+				g.log.WithError(err).Warn("Unable to record prices")
+			}
+		}
+	}
+}
+
+// record stores given root prices and all aggregator and origin prices
+// used to calculate them.
+func (g *Gofer) record(prices ...*gofer.Price) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var records []Record
+	add := func(r Record) {
+		k := recordKey{typ: r.Type, origin: r.Origin, path: r.Path, pair: r.Pair}
+		if l, ok := g.last[k]; ok && l == r {
+			return
+		}
+		g.last[k] = r
+		records = append(records, r)
+	}
+	for _, p := range prices {
+		if p == nil {
+			continue
+		}
+		add(mapPrice(p, ""))
+		walkPrices(p, p.Pair.String()+":", func(p *gofer.Price, path string) {
+			add(mapPrice(p, path))
+		})
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err := g.store.Add(records...); err != nil {
+		g.log.WithError(err).Error("Unable to store prices in the history")
+	}
+}
+
+// walkPrices calls fn for all prices used to calculate the given price,
+// together with their paths.
+func walkPrices(p *gofer.Price, prefix string, fn func(p *gofer.Price, path string)) {
+	for i, c := range p.Prices {
+		if c == nil {
+			continue
+		}
+		path := prefix + strconv.Itoa(i)
+		fn(c, path)
+		walkPrices(c, path+".", fn)
+	}
+}
+
+// mapPrice maps the price to a record. The path is used only for
+// aggregator prices.
+func mapPrice(p *gofer.Price, path string) Record {
+	r := Record{
+		Type:      AggregatorType,
+		Path:      path,
+		Pair:      p.Pair,
+		Price:     p.Price,
+		Bid:       p.Bid,
+		Ask:       p.Ask,
+		Volume24h: p.Volume24h,
+		Time:      p.Time,
+		Error:     p.Error,
+	}
+	if p.Type == OriginType {
+		r.Type = OriginType
+		r.Origin = p.Parameters["origin"]
+		r.Path = ""
+	}
+	return r
+}
@@ -0,0 +1,136 @@
+package history
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (m *memoryStore) Add(records ...Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *memoryStore) Query(q Query) ([]Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rs []Record
+	for _, r := range m.records {
+		if q.Match(r) {
+			rs = append(rs, r)
+		}
+	}
+	return rs, nil
+}
+
+func TestGofer_Prices(t *testing.T) {
+	ts := time.Unix(10, 0)
+	btcusdt := gofer.Pair{Base: "BTC", Quote: "USDT"}
+	usdtusd := gofer.Pair{Base: "USDT", Quote: "USD"}
+	newOrigin := func(origin string, pair gofer.Pair, price int64) *gofer.Price {
+		return &gofer.Price{
+			Type:       "origin",
+			Parameters: map[string]string{"origin": origin},
+			Pair:       pair,
+			Price:      decimal.NewFromInt(price),
+			Time:       ts,
+		}
+	}
+	binance := newOrigin("binance", btcusd, 10)
+	// The price model: median(binance, indirect(binance, median(kraken))).
+	price := &gofer.Price{
+		Type:       "aggregator",
+		Parameters: map[string]string{"method": "median"},
+		Pair:       btcusd,
+		Price:      decimal.NewFromInt(10),
+		Time:       ts,
+		Prices: []*gofer.Price{
+			binance,
+			{
+				Type:       "aggregator",
+				Parameters: map[string]string{"method": "indirect"},
+				Pair:       btcusd,
+				Price:      decimal.NewFromInt(11),
+				Time:       ts,
+				Prices: []*gofer.Price{
+					newOrigin("binance", btcusdt, 11),
+					{
+						Type:       "aggregator",
+						Parameters: map[string]string{"method": "median"},
+						Pair:       usdtusd,
+						Price:      decimal.NewFromInt(1),
+						Time:       ts,
+						Prices:     []*gofer.Price{newOrigin("kraken", usdtusd, 1)},
+					},
+				},
+			},
+			binance,
+		},
+	}
+
+	m := &mocks.Gofer{}
+	m.On("Prices", mock.Anything).Return(map[gofer.Pair]*gofer.Price{btcusd: price}, nil)
+
+	s := &memoryStore{}
+	g, err := NewGofer(context.Background(), Config{Gofer: m, Store: s, Logger: null.New()})
+	require.NoError(t, err)
+
+	_, err = g.Prices(btcusd)
+	require.NoError(t, err)
+	_, err = g.Prices(btcusd)
+	require.NoError(t, err)
+
+	// All aggregators are recorded with their paths, origin prices are
+	// recorded only once, and unchanged prices are not recorded again:
+	assert.Equal(t, []Record{
+		{Type: AggregatorType, Pair: btcusd, Price: decimal.NewFromInt(10), Time: ts},
+		{Type: OriginType, Origin: "binance", Pair: btcusd, Price: decimal.NewFromInt(10), Time: ts},
+		{Type: AggregatorType, Path: "BTC/USD:1", Pair: btcusd, Price: decimal.NewFromInt(11), Time: ts},
+		{Type: OriginType, Origin: "binance", Pair: btcusdt, Price: decimal.NewFromInt(11), Time: ts},
+		{Type: AggregatorType, Path: "BTC/USD:1.1", Pair: usdtusd, Price: decimal.NewFromInt(1), Time: ts},
+		{Type: OriginType, Origin: "kraken", Pair: usdtusd, Price: decimal.NewFromInt(1), Time: ts},
+	}, s.records)
+}
+
+func TestGofer_Interval(t *testing.T) {
+	price := &gofer.Price{
+		Type: "aggregator",
+		Pair: btcusd,
+		Time: time.Now(),
+	}
+
+	m := &mocks.Gofer{}
+	m.On("Prices").Return(map[gofer.Pair]*gofer.Price{btcusd: price}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &memoryStore{}
+	g, err := NewGofer(ctx, Config{Gofer: m, Store: s, Interval: time.Millisecond, Logger: null.New()})
+	require.NoError(t, err)
+	require.NoError(t, g.Start())
+
+	assert.Eventually(t, func() bool {
+		rs, _ := s.Query(Query{})
+		return len(rs) > 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	g.Wait()
+	assert.Len(t, s.records, 1)
+}
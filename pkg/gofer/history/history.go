@@ -0,0 +1,77 @@
+package history
+
+import (
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
+	"github.com/toknowwhy/theunit-oracle/pkg/gofer"
+)
+
+const (
+	// OriginType is used for records with prices fetched directly from
+	// an origin.
+	OriginType = "origin"
+	// AggregatorType is used for records with prices calculated by price
+	// models.
+	AggregatorType = "aggregator"
+)
+
+// Record is a single price stored in the price history.
+type Record struct {
+	// Type is either OriginType or AggregatorType.
+	Type string
+	// Origin is the name of the origin from which the price was fetched.
+	// Empty for aggregator prices.
+	Origin string
+	// Path identifies an intermediate aggregator price within the price
+	// model of the root pair, e.g. "BTC/USD:0.1" is the second price used
+	// to calculate the first price used to calculate the BTC/USD price.
+	// Empty for root aggregator prices and origin prices.
+	Path      string
+	Pair      gofer.Pair
+	Price     decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Volume24h decimal.Decimal
+	// Time is the time of the price, as reported by the origin or
+	// calculated by the price model.
+	Time  time.Time
+	Error string
+}
+
+// Query describes which records should be returned by the Store.Query method.
+type Query struct {
+	// Pair is a pair for which records are returned.
+	Pair gofer.Pair
+	// From and To define an inclusive time range. A zero value means that
+	// the range is not limited on that side.
+	From time.Time
+	To   time.Time
+	// Origin, if not empty, limits results to prices from that origin only.
+	Origin string
+}
+
+// Match returns true if the record matches the query.
+func (q Query) Match(r Record) bool {
+	if !q.Pair.Empty() && !q.Pair.Equal(r.Pair) {
+		return false
+	}
+	if q.Origin != "" && (r.Type != OriginType || q.Origin != r.Origin) {
+		return false
+	}
+	if !q.From.IsZero() && r.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.Time.After(q.To) {
+		return false
+	}
+	return true
+}
+
+// Store persists price records.
+type Store interface {
+	// Add appends records to the store.
+	Add(records ...Record) error
+	// Query returns all records matching the query ordered by time.
+	Query(q Query) ([]Record, error)
+}
@@ -32,6 +32,11 @@ func (g *Gofer) Pairs() ([]gofer.Pair, error) {
 	return args.Get(0).([]gofer.Pair), args.Error(1)
 }
 
+func (g *Gofer) TokenTotalSupply(token []gofer.Token) (float64, error) {
+	args := g.Called(token)
+	return args.Get(0).(float64), args.Error(1)
+}
+
 func interfaceSlice(slice interface{}) []interface{} {
 	s := reflect.ValueOf(slice)
 	if s.Kind() != reflect.Slice {
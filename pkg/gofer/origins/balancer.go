@@ -20,8 +20,8 @@ type balancerResponse struct {
 
 type balancerPairResponse struct {
 	Symbol string          `json:"symbol"`
-	Price  stringAsFloat64 `json:"price"`
-	Volume stringAsFloat64 `json:"poolLiquidity"`
+	Price  stringAsDecimal `json:"price"`
+	Volume stringAsDecimal `json:"poolLiquidity"`
 }
 
 type Balancer struct {
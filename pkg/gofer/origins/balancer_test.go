@@ -123,8 +123,8 @@ func (suite *BalancerSuite) TestSuccessResponse() {
 	// BAL/USD
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairBALUSD, fr[0].Price.Pair)
-	suite.Equal(57.84, fr[0].Price.Price)
-	suite.Equal(283523717.59, fr[0].Price.Volume24h)
+	suite.Equal("57.84", fr[0].Price.Price.String())
+	suite.Equal("283523717.59", fr[0].Price.Volume24h.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
@@ -77,7 +78,7 @@ func (s BalancerV2) callOne(pair Pair) (*Price, error) {
 		return nil, err
 	}
 	bn := new(big.Int).SetBytes(resp)
-	price, _ := new(big.Float).Quo(new(big.Float).SetInt(bn), new(big.Float).SetUint64(balancerV2Denominator)).Float64()
+	price := decimal.NewFromBigInt(bn, 0).Div(decimal.NewFromInt(balancerV2Denominator))
 
 	return &Price{
 		Pair:      pair,
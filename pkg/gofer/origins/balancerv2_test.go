@@ -57,7 +57,7 @@ func (suite *BalancerV2Suite) TestSuccessResponse() {
 
 	results1 := suite.origin.Fetch([]Pair{pair})
 	suite.Require().NoError(results1[0].Error)
-	suite.Equal(0.9912488403014287, results1[0].Price.Price)
+	suite.Equal("0.991248840301428707", results1[0].Price.Price.String())
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
 
 	results2 := suite.origin.Fetch([]Pair{pair.Inverse()})
@@ -11,10 +11,10 @@ const binanceURL = "https://www.binance.com/api/v3/ticker/24hr"
 
 type binanceResponse struct {
 	Symbol    string               `json:"symbol"`
-	LastPrice stringAsFloat64      `json:"lastPrice"`
-	BidPrice  stringAsFloat64      `json:"bidPrice"`
-	AskPrice  stringAsFloat64      `json:"askPrice"`
-	Volume    stringAsFloat64      `json:"volume"`
+	LastPrice stringAsDecimal      `json:"lastPrice"`
+	BidPrice  stringAsDecimal      `json:"bidPrice"`
+	AskPrice  stringAsDecimal      `json:"askPrice"`
+	Volume    stringAsDecimal      `json:"volume"`
 	CloseTime intAsUnixTimestampMs `json:"closeTime"`
 }
 
@@ -138,19 +138,19 @@ func (suite *BinanceSuite) TestSuccessResponse() {
 	// BTC/ETH
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairBTCETH, fr[0].Price.Pair)
-	suite.Equal(1.1, fr[0].Price.Price)
-	suite.Equal(1.0, fr[0].Price.Bid)
-	suite.Equal(1.3, fr[0].Price.Ask)
-	suite.Equal(10.1, fr[0].Price.Volume24h)
+	suite.Equal("1.1", fr[0].Price.Price.String())
+	suite.Equal("1", fr[0].Price.Bid.String())
+	suite.Equal("1.3", fr[0].Price.Ask.String())
+	suite.Equal("10.1", fr[0].Price.Volume24h.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 
 	// BTC/USD
 	suite.NoError(fr[1].Error)
 	suite.Equal(pairBTCUSD, fr[1].Price.Pair)
-	suite.Equal(2.1, fr[1].Price.Price)
-	suite.Equal(2.0, fr[1].Price.Bid)
-	suite.Equal(2.3, fr[1].Price.Ask)
-	suite.Equal(20.1, fr[1].Price.Volume24h)
+	suite.Equal("2.1", fr[1].Price.Price.String())
+	suite.Equal("2", fr[1].Price.Bid.String())
+	suite.Equal("2.3", fr[1].Price.Ask.String())
+	suite.Equal("20.1", fr[1].Price.Volume24h.String())
 	suite.Greater(fr[1].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -1,6 +1,7 @@
 package origins
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 type Bitfinex struct {
@@ -33,7 +35,9 @@ func (b Bitfinex) PullPrices(pairs []Pair) []FetchResult {
 
 func (b *Bitfinex) parseResponse(pairs []Pair, res *query.HTTPResponse) []FetchResult {
 	var resp [][]interface{}
-	err := json.Unmarshal(res.Body, &resp)
+	dec := json.NewDecoder(bytes.NewReader(res.Body))
+	dec.UseNumber()
+	err := dec.Decode(&resp)
 	if err != nil {
 		return fetchResultListWithErrors(pairs, fmt.Errorf("failed to parse response: %w", err))
 	}
@@ -80,17 +84,17 @@ func (b *Bitfinex) mapTickers(resp [][]interface{}) map[string]bitfinexTicker {
 }
 
 type bitfinexTicker struct {
-	Symbol              string  //  [0]: SYMBOL
-	Bid                 float64 //  [1]: BID
-	BidSize             float64 //  [2]: BID_SIZE
-	Ask                 float64 //  [3]: ASK
-	AskSize             float64 //  [4]: ASK_SIZE
-	DailyChange         float64 //  [5]: DAILY_CHANGE
-	DailyChangeRelative float64 //  [6]: DAILY_CHANGE_RELATIVE
-	LastPrice           float64 //  [7]: LAST_PRICE
-	Volume              float64 //  [8]: VOLUME
-	High                float64 //  [9]: HIGH
-	Low                 float64 // [10]: LOW
+	Symbol              string          //  [0]: SYMBOL
+	Bid                 decimal.Decimal //  [1]: BID
+	BidSize             decimal.Decimal //  [2]: BID_SIZE
+	Ask                 decimal.Decimal //  [3]: ASK
+	AskSize             decimal.Decimal //  [4]: ASK_SIZE
+	DailyChange         decimal.Decimal //  [5]: DAILY_CHANGE
+	DailyChangeRelative decimal.Decimal //  [6]: DAILY_CHANGE_RELATIVE
+	LastPrice           decimal.Decimal //  [7]: LAST_PRICE
+	Volume              decimal.Decimal //  [8]: VOLUME
+	High                decimal.Decimal //  [9]: HIGH
+	Low                 decimal.Decimal // [10]: LOW
 	Error               error
 }
 
@@ -107,28 +111,33 @@ func (*Bitfinex) parseTicker(tt []interface{}) bitfinexTicker {
 				return t
 			}
 			crc[i] = true
-		case float64:
+		case json.Number:
+			n, err := decimal.NewFromString(x.String())
+			if err != nil {
+				t.Error = fmt.Errorf("item at index %d is not a valid number: %w", i, err)
+				return t
+			}
 			switch i {
 			case 1:
-				t.Bid = x
+				t.Bid = n
 			case 2:
-				t.BidSize = x
+				t.BidSize = n
 			case 3:
-				t.Ask = x
+				t.Ask = n
 			case 4:
-				t.AskSize = x
+				t.AskSize = n
 			case 5:
-				t.DailyChange = x
+				t.DailyChange = n
 			case 6:
-				t.DailyChangeRelative = x
+				t.DailyChangeRelative = n
 			case 7:
-				t.LastPrice = x
+				t.LastPrice = n
 			case 8:
-				t.Volume = x
+				t.Volume = n
 			case 9:
-				t.High = x
+				t.High = n
 			case 10:
-				t.Low = x
+				t.Low = n
 			}
 			crc[i] = true
 		default:
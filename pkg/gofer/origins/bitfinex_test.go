@@ -83,10 +83,10 @@ func (suite *BitfinexSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Bitfinex).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.01, cr[0].Price.Bid)
-	suite.Equal(1.03, cr[0].Price.Ask)
-	suite.Equal(1.07, cr[0].Price.Price)
-	suite.Equal(1.08, cr[0].Price.Volume24h)
+	suite.Equal("1.01", cr[0].Price.Bid.String())
+	suite.Equal("1.03", cr[0].Price.Ask.String())
+	suite.Equal("1.07", cr[0].Price.Price.String())
+	suite.Equal("1.08", cr[0].Price.Volume24h.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Bitstamp URL
@@ -68,22 +69,22 @@ func (b *Bitstamp) callOne(pair Pair) (*Price, error) {
 	}
 
 	// Parsing price from string
-	price, err := strconv.ParseFloat(resp.Price, 64)
+	price, err := decimal.NewFromString(resp.Price)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse price from bitstamp origin %s", res.Body)
 	}
 	// Parsing ask from string
-	ask, err := strconv.ParseFloat(resp.Ask, 64)
+	ask, err := decimal.NewFromString(resp.Ask)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ask from bitstamp origin %s", res.Body)
 	}
 	// Parsing volume from string
-	volume, err := strconv.ParseFloat(resp.Volume, 64)
+	volume, err := decimal.NewFromString(resp.Volume)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse volume from bitstamp origin %s", res.Body)
 	}
 	// Parsing bid from string
-	bid, err := strconv.ParseFloat(resp.Bid, 64)
+	bid, err := decimal.NewFromString(resp.Bid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse bid from bitstamp origin %s", res.Body)
 	}
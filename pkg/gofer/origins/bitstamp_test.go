@@ -108,10 +108,10 @@ func (suite *BitstampSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Bitstamp).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.0, cr[0].Price.Price)
-	suite.Equal(2.0, cr[0].Price.Ask)
-	suite.Equal(3.0, cr[0].Price.Volume24h)
-	suite.Equal(4.0, cr[0].Price.Bid)
+	suite.Equal("1", cr[0].Price.Price.String())
+	suite.Equal("2", cr[0].Price.Ask.String())
+	suite.Equal("3", cr[0].Price.Volume24h.String())
+	suite.Equal("4", cr[0].Price.Bid.String())
 	suite.Equal(int64(5), cr[0].Price.Timestamp.Unix())
 }
 
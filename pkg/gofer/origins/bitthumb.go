@@ -28,11 +28,11 @@ const bitThumpURL = "https://api.bithumb.com/public/ticker/%s"
 	}
 */
 type bitThumbPriceResponse struct {
-	Low    stringAsFloat64 `json:"l"`
-	High   stringAsFloat64 `json:"h"`
-	Last   stringAsFloat64 `json:"c"`
+	Low    stringAsDecimal `json:"l"`
+	High   stringAsDecimal `json:"h"`
+	Last   stringAsDecimal `json:"c"`
 	Symbol string          `json:"s"`
-	Volume stringAsFloat64 `json:"v"`
+	Volume stringAsDecimal `json:"v"`
 }
 type bitThumbResponse struct {
 	Data bitThumbPriceResponse `json:"data"`
@@ -104,8 +104,8 @@ func (suite *BitThumpSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(BitThump).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.0, cr[0].Price.Price)
-	suite.Equal(2.0, cr[0].Price.Volume24h)
+	suite.Equal("1", cr[0].Price.Price.String())
+	suite.Equal("2", cr[0].Price.Volume24h.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 const bittrexURL = "https://api.bittrex.com/api/v1.1/public/getticker?market=%s"
@@ -16,9 +17,9 @@ type bittrexResponse struct {
 }
 
 type bittrexSymbolResponse struct {
-	Ask  float64 `json:"Ask"`
-	Bid  float64 `json:"Bid"`
-	Last float64 `json:"Last"`
+	Ask  decimal.Decimal `json:"Ask"`
+	Bid  decimal.Decimal `json:"Bid"`
+	Last decimal.Decimal `json:"Last"`
 }
 
 // Bittrex origin handler
@@ -120,9 +120,9 @@ func (suite *BittrexSuite) TestSuccessResponse() {
 	// BTC/ETH
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairBTCETH, fr[0].Price.Pair)
-	suite.Equal(1.1, fr[0].Price.Price)
-	suite.Equal(1.0, fr[0].Price.Bid)
-	suite.Equal(1.3, fr[0].Price.Ask)
+	suite.Equal("1.1", fr[0].Price.Price.String())
+	suite.Equal("1", fr[0].Price.Bid.String())
+	suite.Equal("1.3", fr[0].Price.Ask.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 }
 
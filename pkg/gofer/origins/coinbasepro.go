@@ -4,11 +4,11 @@ package origins
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Coinbase URL
@@ -63,22 +63,22 @@ func (c *CoinbasePro) callOne(pair Pair) (*Price, error) {
 		return nil, fmt.Errorf("failed to parse coinbasepro response: %w", err)
 	}
 	// Parsing price from string
-	price, err := strconv.ParseFloat(resp.Price, 64)
+	price, err := decimal.NewFromString(resp.Price)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse price from coinbasepro origin %s", res.Body)
 	}
 	// Parsing ask from string
-	ask, err := strconv.ParseFloat(resp.Ask, 64)
+	ask, err := decimal.NewFromString(resp.Ask)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ask from coinbasepro origin %s", res.Body)
 	}
 	// Parsing volume from string
-	volume, err := strconv.ParseFloat(resp.Volume, 64)
+	volume, err := decimal.NewFromString(resp.Volume)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse volume from coinbasepro origin %s", res.Body)
 	}
 	// Parsing bid from string
-	bid, err := strconv.ParseFloat(resp.Bid, 64)
+	bid, err := decimal.NewFromString(resp.Bid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse bid from coinbasepro origin %s", res.Body)
 	}
@@ -7,14 +7,15 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Exchange URL
 const coinMarketCapURL = "https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest?id=%s"
 
 type quoteResponse struct {
-	Price  float64 `json:"price"`
-	Volume float64 `json:"volume_24h"`
+	Price  decimal.Decimal `json:"price"`
+	Volume decimal.Decimal `json:"volume_24h"`
 }
 
 type coinMarketCapPairResponse struct {
@@ -164,8 +164,8 @@ func (suite *CoinmarketcapSuite) TestSuccessResponse() {
 	cr := suite.origin.Fetch([]Pair{pair})
 
 	suite.NoError(cr[0].Error)
-	suite.Equal(6602.60701122, cr[0].Price.Price)
-	suite.Equal(4314444687.5194, cr[0].Price.Volume24h)
+	suite.Equal("6602.60701122", cr[0].Price.Price.String())
+	suite.Equal("4314444687.5194", cr[0].Price.Volume24h.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 //nolint:lll
@@ -14,11 +15,11 @@ const cryptoCompareMultiURL = "https://min-api.cryptocompare.com/data/pricemulti
 
 type cryptoCompareMultiResponse struct {
 	Raw map[string]map[string]struct {
-		Base  string  `json:"FROMSYMBOL"`
-		Query string  `json:"TOSYMBOL"`
-		Price float64 `json:"PRICE"`
-		TS    int64   `json:"LASTUPDATE"`
-		Vol24 float64 `json:"VOLUME24HOUR"`
+		Base  string          `json:"FROMSYMBOL"`
+		Query string          `json:"TOSYMBOL"`
+		Price decimal.Decimal `json:"PRICE"`
+		TS    int64           `json:"LASTUPDATE"`
+		Vol24 decimal.Decimal `json:"VOLUME24HOUR"`
 	} `json:"RAW"`
 }
 
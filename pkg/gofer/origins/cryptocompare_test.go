@@ -79,7 +79,7 @@ func (suite *CryptoCompareSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(CryptoCompare).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(0.04687, cr[0].Price.Price)
+	suite.Equal("0.04687", cr[0].Price.Price.String())
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(1599982420))
 }
 
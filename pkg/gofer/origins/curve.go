@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	pkgEthereum "github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
@@ -76,7 +77,7 @@ func (s CurveFinance) callOne(pair Pair) (*Price, error) {
 		return nil, err
 	}
 	bn := new(big.Int).SetBytes(resp)
-	price, _ := new(big.Float).Quo(new(big.Float).SetInt(bn), new(big.Float).SetUint64(curveDenominator)).Float64()
+	price := decimal.NewFromBigInt(bn, 0).Div(decimal.NewFromInt(curveDenominator))
 
 	return &Price{
 		Pair:      pair,
@@ -57,7 +57,7 @@ func (suite *CurveSuite) TestSuccessResponse() {
 
 	results1 := suite.origin.Fetch([]Pair{pair})
 	suite.Require().NoError(results1[0].Error)
-	suite.Equal(0.9912488403014287, results1[0].Price.Price)
+	suite.Equal("0.991248840301428707", results1[0].Price.Price.String())
 	suite.Greater(results1[0].Price.Timestamp.Unix(), int64(0))
 
 	suite.client.On("Call", mock.Anything, ethereum.Call{
@@ -67,7 +67,7 @@ func (suite *CurveSuite) TestSuccessResponse() {
 
 	results2 := suite.origin.Fetch([]Pair{pair.Inverse()})
 	suite.Require().NoError(results2[0].Error)
-	suite.Equal(0.9912488403014287, results2[0].Price.Price)
+	suite.Equal("0.991248840301428707", results2[0].Price.Price.String())
 	suite.Greater(results2[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -33,14 +33,14 @@ func (d *Ddex) localPairName(pair Pair) string {
 }
 
 type ddexTicker struct {
-	Ask      stringAsFloat64      `json:"ask"`
-	Bid      stringAsFloat64      `json:"bid"`
-	High     stringAsFloat64      `json:"high"`
-	Low      stringAsFloat64      `json:"low"`
+	Ask      stringAsDecimal      `json:"ask"`
+	Bid      stringAsDecimal      `json:"bid"`
+	High     stringAsDecimal      `json:"high"`
+	Low      stringAsDecimal      `json:"low"`
 	MarketID string               `json:"marketId"`
-	Price    stringAsFloat64      `json:"price"`
+	Price    stringAsDecimal      `json:"price"`
 	UpdateAt intAsUnixTimestampMs `json:"updateAt"`
-	Volume   stringAsFloat64      `json:"volume"`
+	Volume   stringAsDecimal      `json:"volume"`
 }
 type ddexTickersResponse struct {
 	Desc   string `json:"desc"`
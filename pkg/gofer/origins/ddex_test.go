@@ -197,10 +197,10 @@ func (suite *DdexSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Ddex).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(362.64, cr[0].Price.Ask)
-	suite.Equal(362.57, cr[0].Price.Bid)
-	suite.Equal(362.64, cr[0].Price.Price)
-	suite.Equal(6.75, cr[0].Price.Volume24h)
+	suite.Equal("362.64", cr[0].Price.Ask.String())
+	suite.Equal("362.57", cr[0].Price.Bid.String())
+	suite.Equal("362.64", cr[0].Price.Price.String())
+	suite.Equal("6.75", cr[0].Price.Volume24h.String())
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
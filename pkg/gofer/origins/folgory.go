@@ -31,8 +31,8 @@ const folgoryURL = "https://folgory.com/api/v1"
 
 type folgoryTicker struct {
 	Symbol string          `json:"symbol"`
-	Price  stringAsFloat64 `json:"last"`
-	Volume stringAsFloat64 `json:"volume"`
+	Price  stringAsDecimal `json:"last"`
+	Volume stringAsDecimal `json:"volume"`
 }
 
 func (o *Folgory) localPairName(pair Pair) string {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 type Ftx struct {
@@ -35,26 +36,26 @@ type ftxResponse struct {
 }
 
 type ftxTicker struct {
-	Ask            float64 `json:"ask"`
-	BaseCurrency   string  `json:"baseCurrency"`
-	Bid            float64 `json:"bid"`
-	Change1H       float64 `json:"change1h"`
-	Change24H      float64 `json:"change24h"`
-	ChangeBod      float64 `json:"changeBod"`
-	Enabled        bool    `json:"enabled"`
-	Last           float64 `json:"last"`
-	MinProvideSize float64 `json:"minProvideSize"`
-	Name           string  `json:"name"`
-	PostOnly       bool    `json:"postOnly"`
-	Price          float64 `json:"price"`
-	PriceIncrement float64 `json:"priceIncrement"`
-	QuoteCurrency  string  `json:"quoteCurrency"`
-	QuoteVolume24H float64 `json:"quoteVolume24h"`
-	Restricted     bool    `json:"restricted"`
-	SizeIncrement  float64 `json:"sizeIncrement"`
-	Type           string  `json:"type"`
-	Underlying     string  `json:"underlying"`
-	VolumeUsd24H   float64 `json:"volumeUsd24h"`
+	Ask            decimal.Decimal `json:"ask"`
+	BaseCurrency   string          `json:"baseCurrency"`
+	Bid            decimal.Decimal `json:"bid"`
+	Change1H       float64         `json:"change1h"`
+	Change24H      float64         `json:"change24h"`
+	ChangeBod      float64         `json:"changeBod"`
+	Enabled        bool            `json:"enabled"`
+	Last           decimal.Decimal `json:"last"`
+	MinProvideSize float64         `json:"minProvideSize"`
+	Name           string          `json:"name"`
+	PostOnly       bool            `json:"postOnly"`
+	Price          float64         `json:"price"`
+	PriceIncrement float64         `json:"priceIncrement"`
+	QuoteCurrency  string          `json:"quoteCurrency"`
+	QuoteVolume24H decimal.Decimal `json:"quoteVolume24h"`
+	Restricted     bool            `json:"restricted"`
+	SizeIncrement  float64         `json:"sizeIncrement"`
+	Type           string          `json:"type"`
+	Underlying     string          `json:"underlying"`
+	VolumeUsd24H   float64         `json:"volumeUsd24h"`
 }
 
 func (f *Ftx) parseResponse(pairs []Pair, res *query.HTTPResponse) []FetchResult {
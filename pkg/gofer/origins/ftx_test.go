@@ -119,10 +119,10 @@ func (suite *FtxSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Ftx).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(380.23, cr[0].Price.Price)
-	suite.Equal(380.38, cr[0].Price.Ask)
-	suite.Equal(380.25, cr[0].Price.Bid)
-	suite.Equal(12467473.8244, cr[0].Price.Volume24h)
+	suite.Equal("380.23", cr[0].Price.Price.String())
+	suite.Equal("380.38", cr[0].Price.Ask.String())
+	suite.Equal("380.25", cr[0].Price.Bid.String())
+	suite.Equal("12467473.8244", cr[0].Price.Volume24h.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -7,13 +7,14 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Fx URL
 const fxURL = "https://api.exchangeratesapi.io/latest?symbols=%s&base=%s&access_key=%s"
 
 type fxResponse struct {
-	Rates map[string]float64 `json:"rates"`
+	Rates map[string]decimal.Decimal `json:"rates"`
 }
 
 // Fx exchange handler
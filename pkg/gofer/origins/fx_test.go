@@ -82,7 +82,7 @@ func (suite *FxSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Fx).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.0, cr[0].Price.Price)
+	suite.Equal("1", cr[0].Price.Price.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
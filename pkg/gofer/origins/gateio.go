@@ -3,11 +3,11 @@ package origins
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Gateio URL
@@ -110,20 +110,20 @@ func (g *Gateio) newPrice(pair Pair, resp gateioResponse) (Price, error) {
 	}
 
 	// Parsing price from string
-	price, err := strconv.ParseFloat(resp.Price, 64)
+	price, err := decimal.NewFromString(resp.Price)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse price from gateio exchange")
 	}
 	// Parsing volume from string
-	volume, err := strconv.ParseFloat(resp.Volume, 64)
+	volume, err := decimal.NewFromString(resp.Volume)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse volume from gateio exchange")
 	}
-	ask, err := strconv.ParseFloat(resp.Ask, 64)
+	ask, err := decimal.NewFromString(resp.Ask)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse ask from gateio exchange")
 	}
-	bid, err := strconv.ParseFloat(resp.Bid, 64)
+	bid, err := decimal.NewFromString(resp.Bid)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse bid from gateio exchange")
 	}
@@ -100,10 +100,10 @@ func (suite *GateioSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Gateio).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(5.0, cr[0].Price.Price)
-	suite.Equal(6.0, cr[0].Price.Ask)
-	suite.Equal(7.0, cr[0].Price.Bid)
-	suite.Equal(8.0, cr[0].Price.Volume24h)
+	suite.Equal("5", cr[0].Price.Price.String())
+	suite.Equal("6", cr[0].Price.Ask.String())
+	suite.Equal("7", cr[0].Price.Bid.String())
+	suite.Equal("8", cr[0].Price.Volume24h.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -100,9 +100,9 @@ func (suite *GeminiSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Gemini).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.0, cr[0].Price.Price)
-	suite.Equal(2.0, cr[0].Price.Ask)
-	suite.Equal(4.0, cr[0].Price.Bid)
+	suite.Equal("1", cr[0].Price.Price.String())
+	suite.Equal("2", cr[0].Price.Ask.String())
+	suite.Equal("4", cr[0].Price.Bid.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -3,11 +3,11 @@ package origins
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Hitbtc URL
@@ -101,22 +101,22 @@ func (h *Hitbtc) fetch(pairs []Pair) ([]FetchResult, error) {
 
 func (h *Hitbtc) newPrice(pair Pair, resp hitbtcResponse) (Price, error) {
 	// Parsing price from string.
-	price, err := strconv.ParseFloat(resp.Price, 64)
+	price, err := decimal.NewFromString(resp.Price)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse price from hitbtc exchange")
 	}
 	// Parsing ask from string.
-	ask, err := strconv.ParseFloat(resp.Ask, 64)
+	ask, err := decimal.NewFromString(resp.Ask)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse ask from hitbtc exchange")
 	}
 	// Parsing volume from string.
-	volume, err := strconv.ParseFloat(resp.Volume, 64)
+	volume, err := decimal.NewFromString(resp.Volume)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse volume from hitbtc exchange")
 	}
 	// Parsing bid from string.
-	bid, err := strconv.ParseFloat(resp.Bid, 64)
+	bid, err := decimal.NewFromString(resp.Bid)
 	if err != nil {
 		return Price{}, fmt.Errorf("failed to parse bid from hitbtc exchange")
 	}
@@ -7,16 +7,17 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Huobi URL
 const huobiURL = "https://api.huobi.pro/market/tickers"
 
 type huobiResponse struct {
-	Symbol string  `json:"symbol"`
-	Volume float64 `json:"vol"`
-	Bid    float64 `json:"bid"`
-	Ask    float64 `json:"ask"`
+	Symbol string          `json:"symbol"`
+	Volume decimal.Decimal `json:"vol"`
+	Bid    decimal.Decimal `json:"bid"`
+	Ask    decimal.Decimal `json:"ask"`
 }
 
 // Huobi origin handler
@@ -79,7 +80,7 @@ func (h *Huobi) fetch(pairs []Pair) ([]FetchResult, error) {
 		if t, has := respMap[h.localPairName(p)]; has {
 			frs[i] = fetchResult(Price{
 				Pair:      p,
-				Price:     (t.Ask + t.Bid) / 2,
+				Price:     t.Ask.Add(t.Bid).Div(decimal.NewFromInt(2)),
 				Ask:       t.Ask,
 				Bid:       t.Bid,
 				Volume24h: t.Volume,
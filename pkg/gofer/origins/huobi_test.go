@@ -109,9 +109,9 @@ func (suite *HuobiSuite) TestSuccessResponse() {
 	cr := suite.origin.Fetch([]Pair{pair})
 
 	suite.NoError(cr[0].Error)
-	suite.Equal(1.3, cr[0].Price.Volume24h)
-	suite.Equal(1.0, cr[0].Price.Ask)
-	suite.Equal(2.1, cr[0].Price.Bid)
+	suite.Equal("1.3", cr[0].Price.Volume24h.String())
+	suite.Equal("1", cr[0].Price.Ask.String())
+	suite.Equal("2.1", cr[0].Price.Bid.String())
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
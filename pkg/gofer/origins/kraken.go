@@ -35,10 +35,10 @@ type krakenResponse struct {
 }
 
 type krakenPairResponse struct {
-	Price  firstStringFromSliceAsFloat64 `json:"c"`
-	Volume firstStringFromSliceAsFloat64 `json:"v"`
-	Ask    firstStringFromSliceAsFloat64 `json:"a"`
-	Bid    firstStringFromSliceAsFloat64 `json:"b"`
+	Price  firstStringFromSliceAsDecimal `json:"c"`
+	Volume firstStringFromSliceAsDecimal `json:"v"`
+	Ask    firstStringFromSliceAsDecimal `json:"a"`
+	Bid    firstStringFromSliceAsDecimal `json:"b"`
 }
 
 func (k *Kraken) parseResponse(pairs []Pair, res *query.HTTPResponse) []FetchResult {
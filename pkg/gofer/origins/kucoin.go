@@ -3,10 +3,10 @@ package origins
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Kucoin URL
@@ -64,17 +64,17 @@ func (k *Kucoin) callOne(pair Pair) (*Price, error) {
 		return nil, fmt.Errorf("failed to parse kucoin response: %w", err)
 	}
 	// Parsing price from string
-	price, err := strconv.ParseFloat(resp.Data.Price, 64)
+	price, err := decimal.NewFromString(resp.Data.Price)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse price from kucoin origin %s", res.Body)
 	}
 	// Parsing ask from string
-	ask, err := strconv.ParseFloat(resp.Data.BestAsk, 64)
+	ask, err := decimal.NewFromString(resp.Data.BestAsk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ask from kucoin origin %s", res.Body)
 	}
 	// Parsing bid from string
-	bid, err := strconv.ParseFloat(resp.Data.BestBid, 64)
+	bid, err := decimal.NewFromString(resp.Data.BestBid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse bid from kucoin origin %s", res.Body)
 	}
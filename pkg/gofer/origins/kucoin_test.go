@@ -141,9 +141,9 @@ func (suite *KucoinSuite) TestSuccessResponse() {
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
 	suite.Equal(int64(1596632420), cr[0].Price.Timestamp.Unix())
-	suite.Equal(1.23, cr[0].Price.Price)
-	suite.Equal(1.3, cr[0].Price.Bid)
-	suite.Equal(1.2, cr[0].Price.Ask)
+	suite.Equal("1.23", cr[0].Price.Price.String())
+	suite.Equal("1.3", cr[0].Price.Bid.String())
+	suite.Equal("1.2", cr[0].Price.Ask.String())
 }
 
 func (suite *KucoinSuite) TestRealAPICall() {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 type Kyber struct {
@@ -34,7 +35,7 @@ type kyberTicker struct {
 	TokenSymbol  string               `json:"token_symbol"`
 	TokenDecimal int                  `json:"token_decimal"`
 	TokenAddress string               `json:"token_address"`
-	RateEthNow   float64              `json:"rate_eth_now"`
+	RateEthNow   decimal.Decimal      `json:"rate_eth_now"`
 	ChangeEth24H float64              `json:"change_eth_24h"`
 	ChangeUsd24H float64              `json:"change_usd_24h"`
 	RateUsdNow   float64              `json:"rate_usd_now"`
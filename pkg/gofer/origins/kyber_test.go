@@ -158,7 +158,7 @@ func (suite *KyberSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Kyber).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(30.11825982131223, cr[0].Price.Price)
+	suite.Equal("30.11825982131223", cr[0].Price.Price.String())
 	suite.Equal(time.Unix(1600331875, 0).Unix(), cr[0].Price.Timestamp.Unix())
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Loopring URL
@@ -84,15 +85,15 @@ func (l *Loopring) pickPairDetails(response loopringResponse, pair Pair) FetchRe
 		return fetchResultWithError(pair, fmt.Errorf("failed to parse timestamp for pair %s: %w", pair, err))
 	}
 
-	price, err := strconv.ParseFloat(pairRes[7], 64)
+	price, err := decimal.NewFromString(pairRes[7])
 	if err != nil {
 		return fetchResultWithError(pair, fmt.Errorf("failed to parse price for pair %s: %w", pair, err))
 	}
-	bid, err := strconv.ParseFloat(pairRes[9], 64)
+	bid, err := decimal.NewFromString(pairRes[9])
 	if err != nil {
 		return fetchResultWithError(pair, fmt.Errorf("failed to parse bid for pair %s: %w", pair, err))
 	}
-	ask, err := strconv.ParseFloat(pairRes[10], 64)
+	ask, err := decimal.NewFromString(pairRes[10])
 	if err != nil {
 		return fetchResultWithError(pair, fmt.Errorf("failed to parse ask for pair %s: %w", pair, err))
 	}
@@ -153,15 +153,15 @@ func (suite *LoopringSuite) TestSuccessResponse() {
 	cr := suite.origin.Fetch([]Pair{pair, pair2})
 
 	suite.NoError(cr[0].Error)
-	suite.Equal(0.000267, cr[0].Price.Price)
-	suite.Equal(0.0002694, cr[0].Price.Ask)
-	suite.Equal(0.00026699, cr[0].Price.Bid)
+	suite.Equal("0.000267", cr[0].Price.Price.String())
+	suite.Equal("0.0002694", cr[0].Price.Ask.String())
+	suite.Equal("0.00026699", cr[0].Price.Bid.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(2))
 
 	suite.NoError(cr[1].Error)
-	suite.Equal(0.5742, cr[1].Price.Price)
-	suite.Equal(0.5757, cr[1].Price.Ask)
-	suite.Equal(0.5743, cr[1].Price.Bid)
+	suite.Equal("0.5742", cr[1].Price.Price.String())
+	suite.Equal("0.5757", cr[1].Price.Ask.String())
+	suite.Equal("0.5743", cr[1].Price.Bid.String())
 	suite.Greater(cr[1].Price.Timestamp.Unix(), int64(2))
 }
 
@@ -2,45 +2,51 @@ package origins
 
 import (
 	"encoding/json"
+	"errors"
 	"strconv"
 	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
-type stringAsFloat64 float64
+type stringAsDecimal decimal.Decimal
 
-func (s *stringAsFloat64) UnmarshalJSON(bytes []byte) error {
+func (s *stringAsDecimal) UnmarshalJSON(bytes []byte) error {
 	var ss string
 	if err := json.Unmarshal(bytes, &ss); err != nil {
 		return err
 	}
-	f, err := strconv.ParseFloat(ss, 64)
+	d, err := decimal.NewFromString(ss)
 	if err != nil {
 		return err
 	}
-	*s = stringAsFloat64(f)
+	*s = stringAsDecimal(d)
 	return nil
 }
-func (s *stringAsFloat64) val() float64 {
-	return float64(*s)
+func (s *stringAsDecimal) val() decimal.Decimal {
+	return decimal.Decimal(*s)
 }
 
-type firstStringFromSliceAsFloat64 float64
+type firstStringFromSliceAsDecimal decimal.Decimal
 
-func (s *firstStringFromSliceAsFloat64) UnmarshalJSON(bytes []byte) error {
+func (s *firstStringFromSliceAsDecimal) UnmarshalJSON(bytes []byte) error {
 	var ss []string
 	if err := json.Unmarshal(bytes, &ss); err != nil {
 		return err
 	}
-	f, err := strconv.ParseFloat(ss[0], 64)
+	if len(ss) == 0 {
+		return errors.New("empty slice")
+	}
+	d, err := decimal.NewFromString(ss[0])
 	if err != nil {
 		return err
 	}
-	*s = firstStringFromSliceAsFloat64(f)
+	*s = firstStringFromSliceAsDecimal(d)
 	return nil
 }
 
-func (s *firstStringFromSliceAsFloat64) val() float64 {
-	return float64(*s)
+func (s *firstStringFromSliceAsDecimal) val() decimal.Decimal {
+	return decimal.Decimal(*s)
 }
 
 //nolint:unused
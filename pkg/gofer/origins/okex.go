@@ -12,10 +12,10 @@ const okexURL = "https://www.okx.com/api/v5/market/tickers?instType=SPOT"
 
 type okexResponse struct {
 	InstrumentID  string          `json:"instId"`
-	Last          stringAsFloat64 `json:"last"`
-	BestAsk       stringAsFloat64 `json:"askPx"`
-	BestBid       stringAsFloat64 `json:"bidPx"`
-	BaseVolume24H stringAsFloat64 `json:"volCcy24h"`
+	Last          stringAsDecimal `json:"last"`
+	BestAsk       stringAsDecimal `json:"askPx"`
+	BestBid       stringAsDecimal `json:"bidPx"`
+	BaseVolume24H stringAsDecimal `json:"volCcy24h"`
 	Timestamp     string          `json:"ts"`
 }
 
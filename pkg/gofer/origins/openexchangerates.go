@@ -5,15 +5,16 @@ import (
 	"fmt"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Coinbase URL
 const openExchangeRatesURL = "https://openexchangerates.org/api/latest.json?app_id=%s&base=%s&symbols=%s"
 
 type openExchangeRatesResponse struct {
-	Timestamp intAsUnixTimestamp `json:"timestamp"`
-	Base      string             `json:"base"`
-	Rates     map[string]float64 `json:"rates"`
+	Timestamp intAsUnixTimestamp         `json:"timestamp"`
+	Base      string                     `json:"base"`
+	Rates     map[string]decimal.Decimal `json:"rates"`
 }
 
 // OpenExchangeRates origin handler
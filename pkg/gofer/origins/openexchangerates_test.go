@@ -94,7 +94,7 @@ func (suite *OpenExchangeRatesSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(OpenExchangeRates).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(0.000891, cr[0].Price.Price)
+	suite.Equal("0.000891", cr[0].Price.Price.String())
 	suite.Greater(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
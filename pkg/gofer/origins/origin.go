@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
@@ -139,10 +140,10 @@ func (p Pair) Equal(c Pair) bool {
 
 type Price struct {
 	Pair      Pair
-	Price     float64
-	Bid       float64
-	Ask       float64
-	Volume24h float64
+	Price     decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Volume24h decimal.Decimal
 	Timestamp time.Time
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 // Define the suite, and absorb the built-in basic suite
@@ -56,8 +57,8 @@ func (suite *OriginsSuite) TestFailWithNilResponseForBinance() {
 }
 
 func (suite *OriginsSuite) TestSuccessBinance() {
-	price := 0.024361
-	json := fmt.Sprintf(`[{"symbol":"ETHBTC","lastPrice":"%f"}]`, price)
+	price := "0.024361"
+	json := fmt.Sprintf(`[{"symbol":"ETHBTC","lastPrice":"%s"}]`, price)
 	resp := &query.HTTPResponse{
 		Body:  []byte(json),
 		Error: nil,
@@ -70,7 +71,7 @@ func (suite *OriginsSuite) TestSuccessBinance() {
 
 	assert.NoError(suite.T(), cr["binance"][0].Error)
 	assert.EqualValues(suite.T(), pair, cr["binance"][0].Price.Pair)
-	assert.EqualValues(suite.T(), price, cr["binance"][0].Price.Price)
+	assert.Equal(suite.T(), price, cr["binance"][0].Price.Price.String())
 }
 
 // In order for 'go test' to run this suite, we need to create
@@ -91,10 +92,10 @@ func (u mockExchangeHandler) PullPrices(pairs []Pair) []FetchResult {
 		results = append(results, FetchResult{
 			Price: Price{
 				Pair:      pair,
-				Price:     1,
-				Ask:       2,
-				Bid:       3,
-				Volume24h: 4,
+				Price:     decimal.NewFromInt(1),
+				Ask:       decimal.NewFromInt(2),
+				Bid:       decimal.NewFromInt(3),
+				Volume24h: decimal.NewFromInt(4),
 				Timestamp: time.Now(),
 			},
 		})
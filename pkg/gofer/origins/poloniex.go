@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/toknowwhy/theunit-oracle/internal/query"
-	"strconv"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"time"
 )
 
@@ -13,9 +13,9 @@ const poloniexURL = "https://api.poloniex.com/markets/ticker24h"
 
 type poloniexResponse struct {
 	MarkPrice string             `json:"markPrice"`
-	Bid       stringAsFloat64    `json:"bid"`
-	Ask       stringAsFloat64    `json:"ask"`
-	Quantity  stringAsFloat64    `json:"quantity"`
+	Bid       stringAsDecimal    `json:"bid"`
+	Ask       stringAsDecimal    `json:"ask"`
+	Quantity  stringAsDecimal    `json:"quantity"`
 	Symbol    string             `json:"symbol"`
 	TimeStamp intAsUnixTimestamp `json:"ts"`
 }
@@ -105,7 +105,7 @@ func (p Poloniex) PullPrices(pairs []Pair) []FetchResult {
 				continue
 			}
 
-			markPrice, err := strconv.ParseFloat(r.MarkPrice, 64)
+			markPrice, err := decimal.NewFromString(r.MarkPrice)
 
 			if err != nil {
 				results = append(results, FetchResult{
@@ -24,10 +24,10 @@ type sushiswapTokenResponse struct {
 
 type sushiswapPairResponse struct {
 	ID      string                 `json:"id"`
-	Price0  stringAsFloat64        `json:"token0Price"`
-	Price1  stringAsFloat64        `json:"token1Price"`
-	Volume0 stringAsFloat64        `json:"volumeToken0"`
-	Volume1 stringAsFloat64        `json:"volumeToken1"`
+	Price0  stringAsDecimal        `json:"token0Price"`
+	Price1  stringAsDecimal        `json:"token1Price"`
+	Volume0 stringAsDecimal        `json:"volumeToken0"`
+	Volume1 stringAsDecimal        `json:"volumeToken1"`
 	Token0  sushiswapTokenResponse `json:"token0"`
 	Token1  sushiswapTokenResponse `json:"token1"`
 }
@@ -155,10 +155,10 @@ func (suite *SushiswapSuite) TestSuccessResponse() {
 	// SNX/WETH
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairSNXWETH, fr[0].Price.Pair)
-	suite.Equal(0.0006, fr[0].Price.Price)
-	suite.Equal(0.0006, fr[0].Price.Bid)
-	suite.Equal(0.0006, fr[0].Price.Ask)
-	suite.Equal(274940368.6801, fr[0].Price.Volume24h)
+	suite.Equal("0.0006", fr[0].Price.Price.String())
+	suite.Equal("0.0006", fr[0].Price.Bid.String())
+	suite.Equal("0.0006", fr[0].Price.Ask.String())
+	suite.Equal("274940368.6801", fr[0].Price.Volume24h.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 
 	pairCRVWETH := Pair{Base: "CRV", Quote: "WETH"}
@@ -193,10 +193,10 @@ func (suite *SushiswapSuite) TestSuccessResponse() {
 	// CRV/WETH
 	suite.NoError(fr1[0].Error)
 	suite.Equal(pairCRVWETH, fr1[0].Price.Pair)
-	suite.Equal(0.0006, fr1[0].Price.Price)
-	suite.Equal(0.0006, fr1[0].Price.Bid)
-	suite.Equal(0.0006, fr1[0].Price.Ask)
-	suite.Equal(274940368.6801, fr1[0].Price.Volume24h)
+	suite.Equal("0.0006", fr1[0].Price.Price.String())
+	suite.Equal("0.0006", fr1[0].Price.Bid.String())
+	suite.Equal("0.0006", fr1[0].Price.Ask.String())
+	suite.Equal("274940368.6801", fr1[0].Price.Volume24h.String())
 	suite.Greater(fr1[0].Price.Timestamp.Unix(), int64(0))
 }
 
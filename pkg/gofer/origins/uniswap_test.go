@@ -183,19 +183,19 @@ func (suite *UniswapSuite) TestSuccessResponse() {
 	// LRC/WETH
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairLRCWETH, fr[0].Price.Pair)
-	suite.Equal(0.0006, fr[0].Price.Price)
-	suite.Equal(0.0006, fr[0].Price.Bid)
-	suite.Equal(0.0006, fr[0].Price.Ask)
-	suite.Equal(274940368.6801, fr[0].Price.Volume24h)
+	suite.Equal("0.0006", fr[0].Price.Price.String())
+	suite.Equal("0.0006", fr[0].Price.Bid.String())
+	suite.Equal("0.0006", fr[0].Price.Ask.String())
+	suite.Equal("274940368.6801", fr[0].Price.Volume24h.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 
 	// WETH/COMP
 	suite.NoError(fr[1].Error)
 	suite.Equal(pairWETHCOMP, fr[1].Price.Pair)
-	suite.Equal(2.4889, fr[1].Price.Price)
-	suite.Equal(2.4889, fr[1].Price.Bid)
-	suite.Equal(2.4889, fr[1].Price.Ask)
-	suite.Equal(714460.7483, fr[1].Price.Volume24h)
+	suite.Equal("2.4889", fr[1].Price.Price.String())
+	suite.Equal("2.4889", fr[1].Price.Bid.String())
+	suite.Equal("2.4889", fr[1].Price.Ask.String())
+	suite.Equal("714460.7483", fr[1].Price.Volume24h.String())
 	suite.Greater(fr[1].Price.Timestamp.Unix(), int64(0))
 }
 
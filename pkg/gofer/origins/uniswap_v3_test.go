@@ -154,10 +154,10 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 	// SNX/WETH
 	suite.NoError(fr[0].Error)
 	suite.Equal(pairYFIWETH, fr[0].Price.Pair)
-	suite.Equal(15.0952, fr[0].Price.Price)
-	suite.Equal(15.0952, fr[0].Price.Bid)
-	suite.Equal(15.0952, fr[0].Price.Ask)
-	suite.Equal(31.00155, fr[0].Price.Volume24h)
+	suite.Equal("15.0952", fr[0].Price.Price.String())
+	suite.Equal("15.0952", fr[0].Price.Bid.String())
+	suite.Equal("15.0952", fr[0].Price.Ask.String())
+	suite.Equal("31.00155", fr[0].Price.Volume24h.String())
 	suite.Greater(fr[0].Price.Timestamp.Unix(), int64(0))
 
 	pairCRVWETH := Pair{Base: "CRV", Quote: "ETH"}
@@ -192,10 +192,10 @@ func (suite *UniswapV3Suite) TestSuccessResponse() {
 	// CRV/WETH
 	suite.NoError(fr1[0].Error)
 	suite.Equal(pairCRVWETH, fr1[0].Price.Pair)
-	suite.Equal(0.0006, fr1[0].Price.Price)
-	suite.Equal(0.0006, fr1[0].Price.Bid)
-	suite.Equal(0.0006, fr1[0].Price.Ask)
-	suite.Equal(274940368.6801, fr1[0].Price.Volume24h)
+	suite.Equal("0.0006", fr1[0].Price.Price.String())
+	suite.Equal("0.0006", fr1[0].Price.Bid.String())
+	suite.Equal("0.0006", fr1[0].Price.Ask.String())
+	suite.Equal("274940368.6801", fr1[0].Price.Volume24h.String())
 	suite.Greater(fr1[0].Price.Timestamp.Unix(), int64(0))
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/toknowwhy/theunit-oracle/internal/query"
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 )
 
 type Upbit struct {
@@ -36,7 +37,7 @@ type upbitTicker struct {
 	OpeningPrice       float64              `json:"opening_price"`
 	HighPrice          float64              `json:"high_price"`
 	LowPrice           float64              `json:"low_price"`
-	TradePrice         float64              `json:"trade_price"`
+	TradePrice         decimal.Decimal      `json:"trade_price"`
 	PrevClosingPrice   float64              `json:"prev_closing_price"`
 	Change             string               `json:"change"`
 	ChangePrice        float64              `json:"change_price"`
@@ -47,7 +48,7 @@ type upbitTicker struct {
 	AccTradePrice      float64              `json:"acc_trade_price"`
 	AccTradePrice24H   float64              `json:"acc_trade_price_24h"`
 	AccTradeVolume     float64              `json:"acc_trade_volume"`
-	AccTradeVolume24H  float64              `json:"acc_trade_volume_24h"`
+	AccTradeVolume24H  decimal.Decimal      `json:"acc_trade_volume_24h"`
 	Highest52WeekPrice float64              `json:"highest_52_week_price"`
 	Highest52WeekDate  string               `json:"highest_52_week_date"`
 	Lowest52WeekPrice  float64              `json:"lowest_52_week_price"`
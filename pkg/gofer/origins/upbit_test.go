@@ -138,8 +138,8 @@ func (suite *UpbitSuite) TestSuccessResponse() {
 	suite.origin.ExchangeHandler.(Upbit).Pool().(*query.MockWorkerPool).MockResp(resp)
 	cr := suite.origin.Fetch([]Pair{pair})
 	suite.NoError(cr[0].Error)
-	suite.Equal(0.03527794, cr[0].Price.Price)
-	suite.Equal(45.24091194, cr[0].Price.Volume24h)
+	suite.Equal("0.03527794", cr[0].Price.Price.String())
+	suite.Equal("45.24091194", cr[0].Price.Volume24h.String())
 	suite.Equal(cr[0].Price.Timestamp.Unix(), int64(2))
 }
 
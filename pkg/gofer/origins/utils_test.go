@@ -29,6 +29,6 @@ func testRealBatchAPICall(suite Suite, origin *BaseExchangeHandler, pairs []Pair
 
 	for _, cr := range crs {
 		suite.Assert().NoErrorf(cr.Error, "%q", cr.Price.Pair)
-		suite.Assert().Greater(cr.Price.Price, float64(0))
+		suite.Assert().True(cr.Price.Price.IsPositive(), "%q", cr.Price.Pair)
 	}
 }
@@ -68,6 +68,11 @@ func NewAgent(ctx context.Context, cfg AgentConfig) (*Agent, error) {
 	return server, nil
 }
 
+// Gofer returns the Gofer instance used by the agent.
+func (s *Agent) Gofer() gofer.Gofer {
+	return s.gofer
+}
+
 // Start starts the RPC server.
 func (s *Agent) Start() error {
 	s.log.Infof("Starting")
@@ -0,0 +1,44 @@
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactFormatter passes the log message and all fields through the Redact
+// function before formatting. It is used to remove secrets from logs.
+type RedactFormatter struct {
+	Formatter logrus.Formatter
+	Redact    func(string) string
+}
+
+func (f *RedactFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	e.Message = f.Redact(e.Message)
+	data := logrus.Fields{}
+	for k, v := range e.Data {
+		data[k] = f.redactValue(v)
+	}
+	e.Data = data
+	return f.Formatter.Format(e)
+}
+
+// redactValue redacts the value only if its string representation
+// is changed by the Redact function, so other values keep their types.
+func (f *RedactFormatter) redactValue(v interface{}) interface{} {
+	var s string
+	switch tv := v.(type) {
+	case string:
+		s = tv
+	case error:
+		s = tv.Error()
+	case fmt.Stringer:
+		s = tv.String()
+	default:
+		s = fmt.Sprint(tv)
+	}
+	if r := f.Redact(s); r != s {
+		return r
+	}
+	return v
+}
@@ -0,0 +1,34 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactFormatter(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := logrus.New()
+	l.SetOutput(b)
+	l.SetFormatter(&RedactFormatter{
+		Formatter: &logrus.TextFormatter{DisableTimestamp: true},
+		Redact: func(s string) string {
+			return strings.ReplaceAll(s, "secret", "***")
+		},
+	})
+
+	l.WithError(errors.New("invalid secret")).
+		WithField("url", "https://example.com/?key=secret").
+		WithField("n", 42).
+		Info("using secret")
+
+	assert.Equal(
+		t,
+		`level=info msg="using ***" error="invalid ***" n=42 url="https://example.com/?key=***"`+"\n",
+		b.String(),
+	)
+}
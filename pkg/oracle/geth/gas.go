@@ -0,0 +1,314 @@
+package geth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+// defaultGasLimit is used if neither a fixed gas limit nor gas estimation
+// is configured.
+const defaultGasLimit = 200000
+
+// ErrFeeCapExceeded is returned when the current fee per gas is higher than
+// the configured cap.
+type ErrFeeCapExceeded struct {
+	Fee *big.Int
+	Cap *big.Int
+}
+
+func (e ErrFeeCapExceeded) Error() string {
+	return fmt.Sprintf("current fee %s wei exceeds the cap of %s wei", e.Fee, e.Cap)
+}
+
+func (e ErrFeeCapExceeded) Unwrap() error {
+	return oracle.ErrTransactionDeferred
+}
+
+// ErrSpendingLimitExceeded is returned when sending a transaction could
+// exceed the spending limit.
+type ErrSpendingLimitExceeded struct {
+	Cost   *big.Int
+	Spent  *big.Int
+	Limit  *big.Int
+	Period time.Duration
+}
+
+func (e ErrSpendingLimitExceeded) Error() string {
+	return fmt.Sprintf(
+		"transaction may cost up to %s wei, which together with %s wei already spent exceeds the limit of %s wei per %s",
+		e.Cost,
+		e.Spent,
+		e.Limit,
+		e.Period,
+	)
+}
+
+func (e ErrSpendingLimitExceeded) Unwrap() error {
+	return oracle.ErrTransactionDeferred
+}
+
+// GasConfig describes how gas limits and fees of transactions are chosen.
+// The zero value uses the default gas limit and fees suggested by
+// the Ethereum client.
+type GasConfig struct {
+	// GasLimit is a fixed gas limit. If zero, the gas is estimated when
+	// EstimateGas is true, otherwise a default limit is used.
+	GasLimit uint64
+	// EstimateGas enables gas estimation.
+	EstimateGas bool
+	// GasMultiplier is applied to the estimated gas. If zero, the estimated
+	// gas is used as is.
+	GasMultiplier float64
+	// PriorityFeePercentile is the percentile of priority fees paid in recent
+	// blocks used as the priority fee. If zero, the priority fee suggested
+	// by the node is used.
+	PriorityFeePercentile float64
+	// MaxFee is the cap for the fee per gas in wei. If the base fee plus
+	// the priority fee exceeds this value, a transaction is not sent.
+	// If nil, fees are not capped.
+	MaxFee *big.Int
+	// SpendingLimits limit the total amount spent on fees. The same limit
+	// may be shared by multiple contracts.
+	SpendingLimits []*SpendingLimit
+}
+
+// isZero returns true if the config uses only default values.
+func (c GasConfig) isZero() bool {
+	return c.GasLimit == 0 &&
+		!c.EstimateGas &&
+		c.PriorityFeePercentile == 0 &&
+		c.MaxFee == nil &&
+		len(c.SpendingLimits) == 0
+}
+
+// gasLimit returns the gas limit for the given call.
+func (c GasConfig) gasLimit(ctx context.Context, client ethereum.Client, call ethereum.Call) (uint64, error) {
+	if c.GasLimit > 0 {
+		return c.GasLimit, nil
+	}
+	if !c.EstimateGas {
+		return defaultGasLimit, nil
+	}
+	gas, err := client.EstimateGas(ctx, call)
+	if err != nil {
+		return 0, err
+	}
+	if c.GasMultiplier > 0 {
+		gas = uint64(math.Ceil(float64(gas) * c.GasMultiplier))
+	}
+	return gas, nil
+}
+
+// fees returns the priority fee and the max fee for a new transaction.
+// The max fee is twice the base fee plus the priority fee, so
+// the transaction remains valid for a few blocks even if the base
+// fee rises, but it never exceeds the cap.
+func (c GasConfig) fees(ctx context.Context, client ethereum.Client) (priorityFee, maxFee *big.Int, err error) {
+	fees, err := client.SuggestFees(ctx, c.PriorityFeePercentile)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := new(big.Int).Add(fees.BaseFee, fees.PriorityFee)
+	if c.MaxFee != nil && current.Cmp(c.MaxFee) > 0 {
+		return nil, nil, ErrFeeCapExceeded{Fee: current, Cap: c.MaxFee}
+	}
+	maxFee = new(big.Int).Add(new(big.Int).Mul(fees.BaseFee, big.NewInt(2)), fees.PriorityFee)
+	if c.MaxFee != nil && maxFee.Cmp(c.MaxFee) > 0 {
+		maxFee = new(big.Int).Set(c.MaxFee)
+	}
+	return fees.PriorityFee, maxFee, nil
+}
+
+// SpendingLimit limits the amount of wei which may be spent on fees during
+// a period of time. Because the actual cost of a transaction is not known
+// before it is mined, the maximum possible cost, which is the gas limit
+// multiplied by the max fee, is counted.
+//
+// Spends are kept in memory, so the limit is reset when the process is
+// restarted, unless the limit is created with NewPersistentSpendingLimit.
+//
+// SpendingLimit is safe for concurrent use.
+type SpendingLimit struct {
+	mu sync.Mutex
+
+	limit  *big.Int
+	period time.Duration
+	spends []*spend
+	path   string
+}
+
+type spend struct {
+	time time.Time
+	cost *big.Int
+}
+
+// jsonSpend is the JSON representation of the spend structure.
+type jsonSpend struct {
+	Time int64  `json:"time"`
+	Cost string `json:"cost"`
+}
+
+// NewSpendingLimit returns a new SpendingLimit which allows to spend up to
+// limit wei during each period.
+func NewSpendingLimit(limit *big.Int, period time.Duration) *SpendingLimit {
+	return &SpendingLimit{limit: limit, period: period}
+}
+
+// NewPersistentSpendingLimit works like NewSpendingLimit, but recent spends
+// are stored in a file at the given path, so the limit is not reset when
+// the process is restarted. Spends already stored in the file are loaded.
+func NewPersistentSpendingLimit(limit *big.Int, period time.Duration, path string) (*SpendingLimit, error) {
+	l := &SpendingLimit{limit: limit, period: period, path: path}
+	if err := l.load(); err != nil {
+		return nil, fmt.Errorf("unable to load spending limit state from %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Limit returns the maximum amount which may be spent during a period.
+func (l *SpendingLimit) Limit() *big.Int {
+	return l.limit
+}
+
+// Spent returns the amount spent during the last period.
+func (l *SpendingLimit) Spent() *big.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.spent(time.Now())
+}
+
+// Reserve counts the cost of a transaction which is not sent by the Median,
+// e.g. the additional cost of a replacement transaction. If the total cost
+// during the last period would exceed the limit, the
+// ErrSpendingLimitExceeded error is returned. The returned function
+// removes the cost if the transaction was not sent.
+func (l *SpendingLimit) Reserve(now time.Time, cost *big.Int) (func(), error) {
+	s, err := l.reserve(now, cost)
+	if err != nil {
+		return nil, err
+	}
+	return func() { l.release(s) }, nil
+}
+
+// reserve counts the cost of a transaction. If the total cost during
+// the last period would exceed the limit, the ErrSpendingLimitExceeded
+// error is returned. The returned spend may be passed to the release
+// method if the transaction was not sent.
+func (l *SpendingLimit) reserve(now time.Time, cost *big.Int) (*spend, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	spent := l.spent(now)
+	if new(big.Int).Add(spent, cost).Cmp(l.limit) > 0 {
+		return nil, ErrSpendingLimitExceeded{Cost: cost, Spent: spent, Limit: l.limit, Period: l.period}
+	}
+	s := &spend{time: now, cost: cost}
+	l.spends = append(l.spends, s)
+	if err := l.save(); err != nil {
+		l.spends = l.spends[:len(l.spends)-1]
+		return nil, fmt.Errorf("unable to store spending limit state in %s: %w", l.path, err)
+	}
+	return s, nil
+}
+
+// release removes a spend added by the reserve method.
+func (l *SpendingLimit) release(s *spend) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.spends {
+		if e == s {
+			l.spends = append(l.spends[:i], l.spends[i+1:]...)
+			// If the state could not be stored, the released spend is still
+			// counted after a restart, which errs on the safe side:
+			_ = l.save()
+			return
+		}
+	}
+}
+
+// load reads spends from the state file. A missing file is not an error.
+func (l *SpendingLimit) load() error {
+	b, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var spends []jsonSpend
+	if err := json.Unmarshal(b, &spends); err != nil {
+		return err
+	}
+	for _, s := range spends {
+		cost, ok := new(big.Int).SetString(s.Cost, 10)
+		if !ok {
+			return fmt.Errorf("invalid cost: %q", s.Cost)
+		}
+		l.spends = append(l.spends, &spend{time: time.Unix(s.Time, 0), cost: cost})
+	}
+	return nil
+}
+
+// save writes spends to the state file. The file is replaced atomically, so
+// the state is not lost if the process crashes while writing it. It does
+// nothing if the limit is not persistent. It must be called with the mutex
+// locked.
+func (l *SpendingLimit) save() error {
+	if l.path == "" {
+		return nil
+	}
+	spends := make([]jsonSpend, len(l.spends))
+	for i, s := range l.spends {
+		spends[i] = jsonSpend{Time: s.time.Unix(), Cost: s.cost.String()}
+	}
+	b, err := json.Marshal(spends)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), l.path)
+}
+
+// spent removes spends older than the period and returns the sum of
+// remaining ones. It must be called with the mutex locked.
+func (l *SpendingLimit) spent(now time.Time) *big.Int {
+	from := now.Add(-l.period)
+	n := 0
+	for _, s := range l.spends {
+		if s.time.After(from) {
+			l.spends[n] = s
+			n++
+		}
+	}
+	l.spends = l.spends[:n]
+	sum := big.NewInt(0)
+	for _, s := range l.spends {
+		sum.Add(sum, s.cost)
+	}
+	return sum
+}
@@ -0,0 +1,107 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+func TestGasConfig_gasLimit(t *testing.T) {
+	ctx := context.Background()
+	call := ethereum.Call{Address: ethereum.Address{}, Data: []byte{1}}
+
+	c := &mocks.Client{}
+	c.On("EstimateGas", ctx, call).Return(uint64(100000), nil)
+
+	gas, err := GasConfig{}.gasLimit(ctx, c, call)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(defaultGasLimit), gas)
+
+	gas, err = GasConfig{GasLimit: 300000}.gasLimit(ctx, c, call)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300000), gas)
+
+	gas, err = GasConfig{EstimateGas: true}.gasLimit(ctx, c, call)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100000), gas)
+
+	gas, err = GasConfig{EstimateGas: true, GasMultiplier: 1.25}.gasLimit(ctx, c, call)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(125000), gas)
+}
+
+func TestGasConfig_fees(t *testing.T) {
+	ctx := context.Background()
+	c := &mocks.Client{}
+	c.On("SuggestFees", ctx, float64(50)).Return(&ethereum.Fees{BaseFee: big.NewInt(100), PriorityFee: big.NewInt(10)}, nil)
+
+	// Without a cap:
+	tip, maxFee, err := GasConfig{PriorityFeePercentile: 50}.fees(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), tip)
+	assert.Equal(t, big.NewInt(210), maxFee)
+
+	// The max fee is limited by the cap:
+	tip, maxFee, err = GasConfig{PriorityFeePercentile: 50, MaxFee: big.NewInt(150)}.fees(ctx, c)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), tip)
+	assert.Equal(t, big.NewInt(150), maxFee)
+
+	// Current fees exceed the cap:
+	_, _, err = GasConfig{PriorityFeePercentile: 50, MaxFee: big.NewInt(100)}.fees(ctx, c)
+	assert.True(t, errors.Is(err, oracle.ErrTransactionDeferred))
+	assert.Equal(t, ErrFeeCapExceeded{Fee: big.NewInt(110), Cap: big.NewInt(100)}, err)
+}
+
+func TestSpendingLimit(t *testing.T) {
+	l := NewSpendingLimit(big.NewInt(100), time.Hour)
+	now := time.Now()
+
+	_, err := l.reserve(now.Add(-2*time.Hour), big.NewInt(60))
+	require.NoError(t, err)
+	s, err := l.reserve(now.Add(-time.Minute), big.NewInt(60))
+	require.NoError(t, err)
+
+	// The first spend is older than an hour, so it is not counted:
+	assert.Equal(t, big.NewInt(60), l.Spent())
+
+	_, err = l.reserve(now, big.NewInt(50))
+	assert.True(t, errors.Is(err, oracle.ErrTransactionDeferred))
+
+	l.release(s)
+	_, err = l.reserve(now, big.NewInt(50))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(50), l.Spent())
+}
+
+func TestSpendingLimit_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limit.json")
+	now := time.Now()
+
+	l, err := NewPersistentSpendingLimit(big.NewInt(100), time.Hour, path)
+	require.NoError(t, err)
+	_, err = l.reserve(now.Add(-2*time.Hour), big.NewInt(60))
+	require.NoError(t, err)
+	_, err = l.reserve(now.Add(-time.Minute), big.NewInt(60))
+	require.NoError(t, err)
+	s, err := l.reserve(now.Add(-time.Minute), big.NewInt(10))
+	require.NoError(t, err)
+	l.release(s)
+
+	// Recent spends are not forgotten after a restart:
+	l, err = NewPersistentSpendingLimit(big.NewInt(100), time.Hour, path)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(60), l.Spent())
+	_, err = l.reserve(now, big.NewInt(50))
+	assert.True(t, errors.Is(err, oracle.ErrTransactionDeferred))
+}
@@ -15,8 +15,6 @@ import (
 
 var ErrStorageQueryFailed = errors.New("oracle contract storage query failed")
 
-// TODO: make it configurable
-const gasLimit = 200000
 const maxReadRetries = 3
 const delayBetweenReadRetries = 5 * time.Second
 
@@ -24,13 +22,21 @@ const delayBetweenReadRetries = 5 * time.Second
 type Median struct {
 	ethereum ethereum.Client
 	address  ethereum.Address
+	gas      GasConfig
 }
 
 // NewMedian creates the new Median instance.
 func NewMedian(ethereum ethereum.Client, address ethereum.Address) *Median {
+	return NewMedianWithGas(ethereum, address, GasConfig{})
+}
+
+// NewMedianWithGas creates the new Median instance which uses the given gas
+// configuration for sent transactions.
+func NewMedianWithGas(ethereum ethereum.Client, address ethereum.Address, gas GasConfig) *Median {
 	return &Median{
 		ethereum: ethereum,
 		address:  address,
+		gas:      gas,
 	}
 }
 
@@ -115,6 +121,9 @@ func (m *Median) Feeds(ctx context.Context) ([]ethereum.Address, error) {
 		results, err = m.ethereum.MultiCall(ctx, calls)
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse results:
 	for _, data := range results {
@@ -132,6 +141,34 @@ func (m *Median) Feeds(ctx context.Context) ([]ethereum.Address, error) {
 
 // Poke implements the oracle.Median interface.
 func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBeforeRun bool) (*ethereum.Hash, error) {
+	args := pokeArgs(prices)
+
+	if simulateBeforeRun {
+		if _, err := m.read(ctx, "poke", args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.write(ctx, "poke", args...)
+}
+
+// SimulatePoke returns the calldata of the poke method for the given prices
+// and simulates its execution on the EVM without sending a transaction.
+// The calldata is returned even if the simulation fails.
+func (m *Median) SimulatePoke(ctx context.Context, prices []*oracle.Price) ([]byte, error) {
+	args := pokeArgs(prices)
+
+	cd, err := medianABI.Pack("poke", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.read(ctx, "poke", args...)
+	return cd, err
+}
+
+// pokeArgs returns arguments for the poke method.
+func pokeArgs(prices []*oracle.Price) []interface{} {
 	// It's important to send prices in correct order, otherwise contract will fail:
 	sort.Slice(prices, func(i, j int) bool {
 		return prices[i].Val.Cmp(prices[j].Val) < 0
@@ -153,13 +190,7 @@ func (m *Median) Poke(ctx context.Context, prices []*oracle.Price, simulateBefor
 		s = append(s, arg.S)
 	}
 
-	if simulateBeforeRun {
-		if _, err := m.read(ctx, "poke", val, age, v, r, s); err != nil {
-			return nil, err
-		}
-	}
-
-	return m.write(ctx, "poke", val, age, v, r, s)
+	return []interface{}{val, age, v, r, s}
 }
 
 // Lift implements the oracle.Median interface.
@@ -195,6 +226,32 @@ func (m *Median) SetBar(ctx context.Context, bar *big.Int, simulateBeforeRun boo
 	return m.write(ctx, "setBar", bar)
 }
 
+// LiftCalldata returns the ABI-encoded calldata of the lift method. It may
+// be used to send the transaction from a different account, e.g. a multisig
+// wallet.
+func (m *Median) LiftCalldata(addresses []common.Address) ([]byte, error) {
+	return medianABI.Pack("lift", addresses)
+}
+
+// DropCalldata returns the ABI-encoded calldata of the drop method.
+func (m *Median) DropCalldata(addresses []common.Address) ([]byte, error) {
+	return medianABI.Pack("drop", addresses)
+}
+
+// SetBarCalldata returns the ABI-encoded calldata of the setBar method.
+func (m *Median) SetBarCalldata(bar *big.Int) ([]byte, error) {
+	return medianABI.Pack("setBar", bar)
+}
+
+// Simulate executes the given calldata on the EVM without sending
+// a transaction. It returns an error if the call would fail.
+func (m *Median) Simulate(ctx context.Context, calldata []byte) error {
+	return retry(maxReadRetries, delayBetweenReadRetries, func() error {
+		_, err := m.ethereum.Call(ctx, ethereum.Call{Address: m.address, Data: calldata})
+		return err
+	})
+}
+
 func (m *Median) read(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
 	cd, err := medianABI.Pack(method, args...)
 	if err != nil {
@@ -219,11 +276,50 @@ func (m *Median) write(ctx context.Context, method string, args ...interface{})
 		return nil, err
 	}
 
-	return m.ethereum.SendTransaction(ctx, &ethereum.Transaction{
+	gasLimit, err := m.gas.gasLimit(ctx, m.ethereum, ethereum.Call{Address: m.address, Data: cd})
+	if err != nil {
+		return nil, err
+	}
+	tx := &ethereum.Transaction{
 		Address:  m.address,
 		GasLimit: new(big.Int).SetUint64(gasLimit),
 		Data:     cd,
-	})
+	}
+	if m.gas.isZero() {
+		return m.ethereum.SendTransaction(ctx, tx)
+	}
+
+	tx.PriorityFee, tx.MaxFee, err = m.gas.fees(ctx, m.ethereum)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count the maximum possible cost of the transaction in spending limits.
+	// If the transaction could not be sent, the cost is released.
+	var (
+		now      = time.Now()
+		cost     = new(big.Int).Mul(tx.GasLimit, tx.MaxFee)
+		reserved = make(map[*SpendingLimit]*spend)
+	)
+	release := func() {
+		for l, s := range reserved {
+			l.release(s)
+		}
+	}
+	for _, l := range m.gas.SpendingLimits {
+		s, err := l.reserve(now, cost)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		reserved[l] = s
+	}
+	hash, err := m.ethereum.SendTransaction(ctx, tx)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return hash, nil
 }
 
 func retry(maxRetries int, delay time.Duration, f func() error) error {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
@@ -147,7 +149,90 @@ func TestMedian_Poke(t *testing.T) {
 
 	assert.Equal(t, a, tx.Address)
 	assert.Equal(t, (*big.Int)(nil), tx.MaxFee)
-	assert.Equal(t, big.NewInt(gasLimit), tx.GasLimit)
-	assert.Equal(t, uint64(0), tx.Nonce)
+	assert.Equal(t, big.NewInt(defaultGasLimit), tx.GasLimit)
+	assert.Nil(t, tx.Nonce)
 	assert.Equal(t, cd, hex.EncodeToString(tx.Data))
 }
+
+func TestMedian_SimulatePoke(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	m := NewMedian(c, a)
+
+	p1 := &oracle.Price{Wat: "AAABBB"}
+	p1.SetFloat64Price(10)
+	p1.Age = time.Unix(0xAAAAAAAA, 0)
+
+	revert := errors.New("execution reverted")
+	c.On("Call", mock.Anything, mock.Anything).Return([]byte{}, revert).Once()
+
+	// Call SimulatePoke function:
+	cd, err := m.SimulatePoke(context.Background(), []*oracle.Price{p1})
+	assert.Equal(t, revert, err)
+
+	// The calldata is returned even if the simulation failed and it is
+	// the same as the simulated one:
+	call := c.Calls[0].Arguments.Get(1).(ethereum.Call)
+	assert.Equal(t, "89bbb8b2", hex.EncodeToString(cd[:4]))
+	assert.Equal(t, call.Data, cd)
+	assert.Equal(t, a, call.Address)
+	c.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+}
+
+func TestMedian_Simulate(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.HexToAddress("0x1234567890123456789012345678901234567890")
+	m := NewMedian(c, a)
+
+	cd, err := m.LiftCalldata([]common.Address{ethereum.HexToAddress("0x1111111111111111111111111111111111111111")})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"94318106"+
+			"0000000000000000000000000000000000000000000000000000000000000020"+
+			"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000001111111111111111111111111111111111111111",
+		hex.EncodeToString(cd),
+	)
+
+	revert := errors.New("execution reverted")
+	c.On("Call", mock.Anything, ethereum.Call{Address: a, Data: cd}).Return([]byte{}, revert).Once()
+
+	assert.Equal(t, revert, m.Simulate(context.Background(), cd))
+	c.AssertNotCalled(t, "SendTransaction", mock.Anything, mock.Anything)
+}
+
+func TestMedian_SetBar_GasConfig(t *testing.T) {
+	// Prepare test data:
+	c := &mocks.Client{}
+	a := ethereum.Address{}
+	l := NewSpendingLimit(big.NewInt(3000000), time.Hour)
+	m := NewMedianWithGas(c, a, GasConfig{
+		EstimateGas:           true,
+		GasMultiplier:         2,
+		PriorityFeePercentile: 50,
+		MaxFee:                big.NewInt(20),
+		SpendingLimits:        []*SpendingLimit{l},
+	})
+
+	c.On("EstimateGas", mock.Anything, mock.Anything).Return(uint64(50000), nil)
+	c.On("SuggestFees", mock.Anything, float64(50)).Return(&ethereum.Fees{BaseFee: big.NewInt(10), PriorityFee: big.NewInt(2)}, nil)
+	c.On("SendTransaction", mock.Anything, mock.Anything).Return(&ethereum.Hash{}, nil)
+
+	// Call SetBar function:
+	_, err := m.SetBar(context.Background(), big.NewInt(13), false)
+	assert.NoError(t, err)
+
+	// Verify generated transaction:
+	tx := c.Calls[2].Arguments.Get(1).(*ethereum.Transaction)
+	assert.Equal(t, big.NewInt(100000), tx.GasLimit)
+	assert.Equal(t, big.NewInt(2), tx.PriorityFee)
+	assert.Equal(t, big.NewInt(20), tx.MaxFee)
+	assert.Equal(t, big.NewInt(2000000), l.Spent())
+
+	// The next transaction would exceed the spending limit:
+	_, err = m.SetBar(context.Background(), big.NewInt(13), false)
+	assert.True(t, errors.Is(err, oracle.ErrTransactionDeferred))
+	c.AssertNumberOfCalls(t, "SendTransaction", 1)
+}
@@ -2,6 +2,7 @@ package oracle
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"time"
 
@@ -10,6 +11,12 @@ import (
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 )
 
+// ErrTransactionDeferred is wrapped by errors returned from methods which
+// send transactions when the transaction was not sent because of current
+// network conditions, e.g. fees are too high. The transaction may be
+// retried later.
+var ErrTransactionDeferred = errors.New("transaction deferred")
+
 // Median is an interface for the median oracle contract:
 //
 // Contract documentation:
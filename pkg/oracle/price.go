@@ -10,12 +10,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 )
 
 const PriceMultiplier = 1e18
 
+// PriceDecimals is the number of decimals used to represent the Val field,
+// it corresponds to the PriceMultiplier.
+const PriceDecimals = 18
+
 var ErrPriceNotSet = errors.New("unable to sign a price because the price is not set")
 var ErrUnmarshallingFailure = errors.New("unable to unmarshal given JSON")
 
@@ -60,6 +65,18 @@ func (p *Price) SetFloat64Price(price float64) {
 	p.Val = pi
 }
 
+// SetDecimalPrice sets the Val field to the given price multiplied by
+// the PriceMultiplier. Digits beyond PriceDecimals are truncated. Unlike
+// the SetFloat64Price method, the conversion is exact.
+func (p *Price) SetDecimalPrice(price decimal.Decimal) {
+	p.Val = price.BigInt(PriceDecimals)
+}
+
+// DecimalPrice returns the Val field divided by the PriceMultiplier.
+func (p *Price) DecimalPrice() decimal.Decimal {
+	return decimal.NewFromBigInt(p.Val, -PriceDecimals)
+}
+
 func (p *Price) Float64Price() float64 {
 	x := new(big.Float).SetInt(p.Val)
 	x = new(big.Float).Quo(x, new(big.Float).SetFloat64(PriceMultiplier))
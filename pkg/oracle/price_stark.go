@@ -0,0 +1,100 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
+)
+
+// Limits of fields of the StarkEx price message:
+const (
+	starkAssetNameLen  = 16
+	starkOracleNameLen = 5
+	starkPriceBits     = 120
+	starkTimestampBits = 32
+)
+
+var ErrStarkSignatureMissing = errors.New("price does not have a StarkWare signature")
+
+// StarkHash returns the hash of the price used to create StarkWare
+// signatures. It is calculated in the same way as by StarkEx oracles, as
+// the Pedersen hash of two numbers: the asset name padded to 16 bytes
+// followed by the oracle name padded to 5 bytes, and the price followed by
+// a 32-bit timestamp.
+func (p *Price) StarkHash(oracleName string) (*big.Int, error) {
+	if len(p.Wat) > starkAssetNameLen {
+		return nil, fmt.Errorf("asset name must not be longer than %d bytes", starkAssetNameLen)
+	}
+	if len(oracleName) == 0 || len(oracleName) > starkOracleNameLen {
+		return nil, fmt.Errorf("oracle name must be between 1 and %d bytes long", starkOracleNameLen)
+	}
+	if p.Val == nil || p.Val.Sign() < 0 || p.Val.BitLen() > starkPriceBits {
+		return nil, fmt.Errorf("price must be a non-negative number lower than 2^%d", starkPriceBits)
+	}
+	age := p.Age.Unix()
+	if age < 0 || age >= 1<<starkTimestampBits {
+		return nil, errors.New("timestamp does not fit in 32 bits")
+	}
+
+	asset := make([]byte, starkAssetNameLen+starkOracleNameLen)
+	copy(asset, p.Wat)
+	copy(asset[starkAssetNameLen:], oracleName)
+
+	price := new(big.Int).Lsh(p.Val, starkTimestampBits)
+	price.Or(price, big.NewInt(age))
+
+	return stark.PedersenHash(new(big.Int).SetBytes(asset), price)
+}
+
+// StarkSign signs the price using the StarkWare signer and stores
+// the signature and the public key in the StarkR, StarkS and StarkPK
+// fields.
+func (p *Price) StarkSign(signer stark.Signer, oracleName string) error {
+	if p.Val == nil {
+		return ErrPriceNotSet
+	}
+	hash, err := p.StarkHash(oracleName)
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Signature(hash)
+	if err != nil {
+		return err
+	}
+	p.StarkR = sig.R.Bytes()
+	p.StarkS = sig.S.Bytes()
+	p.StarkPK = signer.PublicKey().Bytes()
+	return nil
+}
+
+// HasStarkSignature returns true if the price has a StarkWare signature.
+func (p *Price) HasStarkSignature() bool {
+	for _, b := range [][]byte{p.StarkR, p.StarkS, p.StarkPK} {
+		if new(big.Int).SetBytes(b).Sign() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// StarkVerify verifies the StarkWare signature of the price against
+// the public key in the StarkPK field.
+func (p *Price) StarkVerify(oracleName string) error {
+	if !p.HasStarkSignature() {
+		return ErrStarkSignatureMissing
+	}
+	hash, err := p.StarkHash(oracleName)
+	if err != nil {
+		return err
+	}
+	return stark.Verify(
+		new(big.Int).SetBytes(p.StarkPK),
+		hash,
+		stark.Signature{
+			R: new(big.Int).SetBytes(p.StarkR),
+			S: new(big.Int).SetBytes(p.StarkS),
+		},
+	)
+}
@@ -0,0 +1,65 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/stark"
+)
+
+func TestPrice_StarkHash(t *testing.T) {
+	val, _ := new(big.Int).SetString("11512340000000000000000", 10)
+	p := &Price{Wat: "BTCUSD", Val: val, Age: time.Unix(1577898000, 0)}
+
+	// The asset name is padded to 16 bytes and followed by the oracle name,
+	// the price is followed by the 32-bit timestamp:
+	a, _ := new(big.Int).SetString("425443555344000000000000000000004d616b6572", 16)
+	b, _ := new(big.Int).SetString("27015cfcb02308200005e0cd010", 16)
+	expected, err := stark.PedersenHash(a, b)
+	require.NoError(t, err)
+
+	h, err := p.StarkHash("Maker")
+	require.NoError(t, err)
+	assert.Equal(t, expected, h)
+
+	// Invalid fields:
+	_, err = p.StarkHash("TooLong")
+	assert.Error(t, err)
+	_, err = (&Price{Wat: "AAAAAAAABBBBBBBBC", Val: val, Age: time.Unix(1, 0)}).StarkHash("Maker")
+	assert.Error(t, err)
+	_, err = (&Price{Wat: "BTCUSD", Val: new(big.Int).Lsh(big.NewInt(1), 120), Age: time.Unix(1, 0)}).StarkHash("Maker")
+	assert.Error(t, err)
+}
+
+func TestPrice_StarkSign(t *testing.T) {
+	signer, err := stark.NewSigner(big.NewInt(42))
+	require.NoError(t, err)
+
+	p := &Price{Wat: "BTCUSD", Age: time.Unix(1577898000, 0)}
+	assert.ErrorIs(t, p.StarkSign(signer, "Maker"), ErrPriceNotSet)
+	assert.ErrorIs(t, p.StarkVerify("Maker"), ErrStarkSignatureMissing)
+
+	p.SetFloat64Price(42)
+	require.NoError(t, p.StarkSign(signer, "Maker"))
+	assert.True(t, p.HasStarkSignature())
+	assert.Equal(t, signer.PublicKey().Bytes(), p.StarkPK)
+	assert.NoError(t, p.StarkVerify("Maker"))
+
+	// The signature must survive the JSON round trip:
+	b, err := p.MarshalJSON()
+	require.NoError(t, err)
+	u := &Price{}
+	require.NoError(t, u.UnmarshalJSON(b))
+	assert.NoError(t, u.StarkVerify("Maker"))
+
+	// Different oracle name:
+	assert.ErrorIs(t, p.StarkVerify("Other"), stark.ErrInvalidSignature)
+
+	// Modified price:
+	p.SetFloat64Price(43)
+	assert.ErrorIs(t, p.StarkVerify("Maker"), stark.ErrInvalidSignature)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/toknowwhy/theunit-oracle/pkg/decimal"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
 )
@@ -48,6 +49,53 @@ func TestPrice_SetFloat64Price(t *testing.T) {
 	}
 }
 
+func TestPrice_SetDecimalPrice(t *testing.T) {
+	tests := []struct {
+		name  string
+		price string
+		val   string
+	}{
+		{
+			// Smallest possible price but greater than 0:
+			name:  "1/PriceMultiplier",
+			price: "0.000000000000000001",
+			val:   "1",
+		},
+		{
+			// SHIB/ETH like price, which is not representable as float64:
+			name:  "small",
+			price: "0.000000002713742016",
+			val:   "2713742016",
+		},
+		{
+			// A price greater than 2^53 with full precision:
+			name:  "large",
+			price: "123456789012345678901.123456789012345678",
+			val:   "123456789012345678901123456789012345678",
+		},
+		{
+			// Digits beyond PriceDecimals are truncated:
+			name:  "truncated",
+			price: "1.0000000000000000019",
+			val:   "1000000000000000001",
+		},
+		{
+			// Zero:
+			name:  "0",
+			price: "0",
+			val:   "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Price{Wat: "AAABBB"}
+			p.SetDecimalPrice(decimal.RequireFromString(tt.price))
+			assert.Equal(t, tt.val, p.Val.String())
+			assert.Equal(t, p.Val, p.DecimalPrice().BigInt(PriceDecimals))
+		})
+	}
+}
+
 func TestPrice_Sign(t *testing.T) {
 	s := &mocks.Signer{}
 	p := &Price{Wat: "AAABBB"}
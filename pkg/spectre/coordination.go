@@ -0,0 +1,200 @@
+package spectre
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/log"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+// Coordination configures how multiple Spectre instances take turns
+// updating Oracles. Time is divided into slots and in each slot only one
+// relayer may update a given Oracle. The order of relayers is derived from
+// the relayer set and the asset pair name, so all relayers with the same
+// configuration agree on whose turn it is.
+type Coordination struct {
+	// Relayers is the list of addresses of all relayers, including this one.
+	Relayers []ethereum.Address
+	// SlotDuration is the duration of a single turn.
+	SlotDuration time.Duration
+	// GracePeriod is the time after the Oracle expiration after which every
+	// relayer may update the Oracle, regardless of whose turn it is.
+	GracePeriod time.Duration
+	// Transport is used to announce intents to update an Oracle. If nil,
+	// intents are not announced.
+	Transport transport.Transport
+}
+
+type errNotOurTurn struct {
+	AssetPair string
+	Relayer   ethereum.Address
+}
+
+func (e errNotOurTurn) Error() string {
+	return fmt.Sprintf("it is the turn of the %s relayer to update the Oracle for %s pair", e.Relayer.String(), e.AssetPair)
+}
+
+type errIntentAnnounced struct {
+	AssetPair string
+	Relayer   ethereum.Address
+}
+
+func (e errIntentAnnounced) Error() string {
+	return fmt.Sprintf("the %s relayer announced an update of the Oracle for %s pair", e.Relayer.String(), e.AssetPair)
+}
+
+// coordinator decides whether this relayer may update an Oracle.
+type coordinator struct {
+	self      ethereum.Address
+	relayers  []ethereum.Address
+	slot      time.Duration
+	grace     time.Duration
+	transport transport.Transport
+	log       log.Logger
+
+	mu      sync.Mutex
+	intents map[string]*messages.Intent
+}
+
+func newCoordinator(self ethereum.Address, cfg *Coordination, logger log.Logger) (*coordinator, error) {
+	if cfg.SlotDuration <= 0 {
+		return nil, errors.New("slot duration must be greater than zero")
+	}
+	relayers := make([]ethereum.Address, len(cfg.Relayers))
+	copy(relayers, cfg.Relayers)
+	sort.Slice(relayers, func(i, j int) bool {
+		return bytes.Compare(relayers[i][:], relayers[j][:]) < 0
+	})
+	found := false
+	for _, r := range relayers {
+		if r == self {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("relayer %s is not on the list of relayers", self.String())
+	}
+	return &coordinator{
+		self:      self,
+		relayers:  relayers,
+		slot:      cfg.SlotDuration,
+		grace:     cfg.GracePeriod,
+		transport: cfg.Transport,
+		log:       logger,
+		intents:   make(map[string]*messages.Intent),
+	}, nil
+}
+
+// turn returns the relayer whose turn it is to update the Oracle for
+// the given pair at the given time. The pair name is used as an offset,
+// so different Oracles are updated by different relayers in the same slot.
+func (c *coordinator) turn(assetPair string, t time.Time) ethereum.Address {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(assetPair))
+	n := uint64(len(c.relayers))
+	slot := uint64(t.UnixNano() / int64(c.slot))
+	return c.relayers[(slot+uint64(h.Sum32()))%n]
+}
+
+// mayPoke checks if this relayer may update the Oracle for the given pair.
+// Relayers may update Oracle only in their turn, unless the Oracle is
+// expired for longer than the grace period. In both cases, an update is
+// skipped if another relayer announced an update of the same Oracle price.
+func (c *coordinator) mayPoke(pair *Pair, oracleAge time.Time, now time.Time) error {
+	c.mu.Lock()
+	i, ok := c.intents[pair.AssetPair]
+	c.mu.Unlock()
+	if ok && i.OracleAge.Unix() == oracleAge.Unix() && now.Sub(i.Time) < c.slot {
+		return errIntentAnnounced{AssetPair: pair.AssetPair, Relayer: i.Relayer}
+	}
+	if now.After(oracleAge.Add(pair.OracleExpiration).Add(c.grace)) {
+		return nil
+	}
+	if r := c.turn(pair.AssetPair, now); r != c.self {
+		return errNotOurTurn{AssetPair: pair.AssetPair, Relayer: r}
+	}
+	return nil
+}
+
+// announce broadcasts an intent to update the Oracle for the given pair.
+// It must be called only after the update transaction was sent.
+func (c *coordinator) announce(assetPair string, oracleAge time.Time, now time.Time) {
+	if c.transport == nil {
+		return
+	}
+	err := c.transport.Broadcast(messages.IntentMessageName, &messages.Intent{
+		AssetPair: assetPair,
+		Relayer:   c.self,
+		OracleAge: oracleAge,
+		Time:      now,
+	})
+	if err != nil {
+		c.log.
+			WithError(err).
+			WithField("assetPair", assetPair).
+			Warn("Unable to announce an intent")
+	}
+}
+
+// addIntent stores an intent received from another relayer. Intents from
+// unknown relayers are ignored.
+func (c *coordinator) addIntent(i *messages.Intent) {
+	if i.Relayer == c.self {
+		return
+	}
+	known := false
+	for _, r := range c.relayers {
+		if r == i.Relayer {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prev, ok := c.intents[i.AssetPair]; ok && prev.Time.After(i.Time) {
+		return
+	}
+	c.intents[i.AssetPair] = i
+}
+
+// intentLoop collects intents announced by other relayers until
+// the context is cancelled.
+func (c *coordinator) intentLoop(ctx context.Context) {
+	if c.transport == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m := <-c.transport.Messages(messages.IntentMessageName):
+				if m.Error != nil {
+					c.log.
+						WithError(m.Error).
+						Warn("Unable to read intents from the transport")
+					continue
+				}
+				intent, ok := m.Message.(*messages.Intent)
+				if !ok {
+					c.log.Error("Unexpected value returned from transport layer")
+					continue
+				}
+				c.addIntent(intent)
+			}
+		}
+	}()
+}
@@ -0,0 +1,152 @@
+package spectre
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/local"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+var (
+	testRelayer1 = ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+	testRelayer2 = ethereum.HexToAddress("0x2222222222222222222222222222222222222222")
+	testRelayer3 = ethereum.HexToAddress("0x3333333333333333333333333333333333333333")
+)
+
+func newTestCoordinator(t *testing.T, self ethereum.Address, tra transport.Transport) *coordinator {
+	c, err := newCoordinator(self, &Coordination{
+		// The order must not matter:
+		Relayers:     []ethereum.Address{testRelayer3, testRelayer1, testRelayer2},
+		SlotDuration: time.Minute,
+		GracePeriod:  time.Minute,
+		Transport:    tra,
+	}, null.New())
+	require.NoError(t, err)
+	return c
+}
+
+func TestCoordinator_UnknownRelayer(t *testing.T) {
+	_, err := newCoordinator(testRelayer1, &Coordination{
+		Relayers:     []ethereum.Address{testRelayer2},
+		SlotDuration: time.Minute,
+	}, null.New())
+	assert.Error(t, err)
+}
+
+func TestCoordinator_turn(t *testing.T) {
+	c1 := newTestCoordinator(t, testRelayer1, nil)
+	c2 := newTestCoordinator(t, testRelayer2, nil)
+
+	// All relayers must agree on the turn and every relayer must have
+	// exactly one turn in consecutive slots:
+	turns := map[ethereum.Address]int{}
+	for i := 0; i < 3; i++ {
+		now := time.Unix(int64(i*60), 0)
+		r := c1.turn("AAABBB", now)
+		assert.Equal(t, r, c2.turn("AAABBB", now))
+		assert.Equal(t, r, c1.turn("AAABBB", now.Add(59*time.Second)))
+		turns[r]++
+	}
+	assert.Equal(t, map[ethereum.Address]int{testRelayer1: 1, testRelayer2: 1, testRelayer3: 1}, turns)
+}
+
+func TestCoordinator_mayPoke(t *testing.T) {
+	c := newTestCoordinator(t, testRelayer1, nil)
+	pair := &Pair{AssetPair: "AAABBB", OracleExpiration: time.Hour}
+	oracleAge := time.Unix(0, 0)
+
+	// Find slots in which it is and it is not our turn:
+	var ours, theirs time.Time
+	for i := 0; i < 3; i++ {
+		now := oracleAge.Add(time.Duration(i) * time.Minute)
+		if c.turn(pair.AssetPair, now) == testRelayer1 {
+			ours = now
+		} else {
+			theirs = now
+		}
+	}
+	assert.NoError(t, c.mayPoke(pair, oracleAge, ours))
+	assert.IsType(t, errNotOurTurn{}, c.mayPoke(pair, oracleAge, theirs))
+
+	// After the expiration and the grace period, everyone may update
+	// the Oracle:
+	late := oracleAge.Add(pair.OracleExpiration + c.grace + time.Second)
+	assert.NoError(t, c.mayPoke(pair, oracleAge, late))
+}
+
+func TestCoordinator_intents(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	tra := local.New(ctx, 1, map[string]transport.Message{messages.IntentMessageName: (*messages.Intent)(nil)})
+	require.NoError(t, tra.Start())
+
+	c1 := newTestCoordinator(t, testRelayer1, tra)
+	c2 := newTestCoordinator(t, testRelayer2, nil)
+	pair := &Pair{AssetPair: "AAABBB", OracleExpiration: time.Hour}
+	oracleAge := time.Unix(0, 0)
+	now := oracleAge.Add(2 * time.Hour)
+
+	// Another relayer announced an update:
+	c1.announce(pair.AssetPair, oracleAge, now)
+	msg := <-tra.Messages(messages.IntentMessageName)
+	require.NoError(t, msg.Error)
+	c2.addIntent(msg.Message.(*messages.Intent))
+	assert.IsType(t, errIntentAnnounced{}, c2.mayPoke(pair, oracleAge, now))
+
+	// The intent is no longer valid if the Oracle was updated or
+	// the intent is outdated:
+	assert.NoError(t, c2.mayPoke(pair, oracleAge.Add(time.Second), now))
+	assert.NoError(t, c2.mayPoke(pair, oracleAge, now.Add(c2.slot)))
+
+	// Intents from unknown relayers and own intents are ignored:
+	c1.addIntent(&messages.Intent{AssetPair: "AAABBB", Relayer: testRelayer1, OracleAge: oracleAge, Time: now})
+	c1.addIntent(&messages.Intent{AssetPair: "AAABBB", Relayer: ethereum.HexToAddress("0x4"), OracleAge: oracleAge, Time: now})
+	assert.NoError(t, c1.mayPoke(pair, oracleAge, now))
+}
+
+func TestSpectre_relay_AnnounceAfterPoke(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	tra := local.New(ctx, 1, map[string]transport.Message{messages.IntentMessageName: (*messages.Intent)(nil)})
+	require.NoError(t, tra.Start())
+
+	// The Oracle is expired for longer than the grace period, so it is
+	// always our turn:
+	m := &testMedian{age: time.Unix(0, 0), pokeErr: oracle.ErrTransactionDeferred}
+	s := newTestSpectre(t, ctx, &Pair{
+		AssetPair:        "AAABBB",
+		Median:           m,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Since(time.Unix(0, 0)),
+	})
+	s.coord = newTestCoordinator(t, testRelayer1, tra)
+
+	// The intent must not be announced if the transaction was not sent:
+	_, err := s.relay("AAABBB")
+	require.ErrorIs(t, err, oracle.ErrTransactionDeferred)
+	select {
+	case <-tra.Messages(messages.IntentMessageName):
+		t.Fatal("intent announced although the transaction was not sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.pokeErr = nil
+	tx, err := s.relay("AAABBB")
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	msg := <-tra.Messages(messages.IntentMessageName)
+	require.NoError(t, msg.Error)
+	assert.Equal(t, "AAABBB", msg.Message.(*messages.Intent).AssetPair)
+	assert.Equal(t, testRelayer1, msg.Message.(*messages.Intent).Relayer)
+}
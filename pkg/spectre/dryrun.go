@@ -0,0 +1,123 @@
+package spectre
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+var errSimulationNotSupported = errors.New("the Oracle does not support simulating updates")
+
+// PokeSimulator is implemented by oracle.Median implementations which are
+// able to simulate an update without sending a transaction. It is used in
+// the dry run mode.
+type PokeSimulator interface {
+	// SimulatePoke returns the ABI-encoded calldata of the poke method for
+	// the given prices and the error returned by the simulated call.
+	SimulatePoke(ctx context.Context, prices []*oracle.Price) ([]byte, error)
+}
+
+// DryRunResult describes the evaluation of an Oracle in the dry run mode.
+type DryRunResult struct {
+	AssetPair string
+	// Err is set if the Oracle could not be evaluated. Other fields are not
+	// set in that case.
+	Err error
+
+	Bar     int64
+	Age     time.Time
+	Val     *big.Int
+	Spread  float64
+	Expired bool
+	Stale   bool
+	// Quorum is true if there are enough prices to achieve a quorum.
+	Quorum bool
+	// Prices are the prices chosen to update the Oracle.
+	Prices []DryRunPrice
+	// Excluded are valid prices which are not needed to achieve a quorum.
+	Excluded []DryRunPrice
+	// Coordination is set if the update would be left to another relayer.
+	Coordination error
+	// Calldata is the ABI-encoded poke calldata. It is nil if there are not
+	// enough prices to achieve a quorum.
+	Calldata []byte
+	// Simulation is the error returned by the simulated poke or nil if
+	// the simulation succeeded.
+	Simulation error
+}
+
+// Update returns true if the Oracle would be updated.
+func (r *DryRunResult) Update() bool {
+	return r.Err == nil && (r.Expired || r.Stale) && r.Quorum && r.Coordination == nil
+}
+
+// DryRunPrice is a price along with its feeder.
+type DryRunPrice struct {
+	// Feeder is the address of the feeder, it is nil if the address could
+	// not be recovered from the signature.
+	Feeder *ethereum.Address
+	Price  *oracle.Price
+}
+
+// DryRun evaluates Oracles for all pairs in the same way as before sending
+// updates, but instead of sending transactions, updates are simulated.
+// Updates are simulated whenever there are enough prices, even if
+// the Oracle does not need to be updated. Results are sorted by asset pair.
+func (s *Spectre) DryRun() []*DryRunResult {
+	var results []*DryRunResult
+	for _, state := range s.pairs {
+		results = append(results, s.dryRun(state))
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].AssetPair < results[j].AssetPair
+	})
+	return results
+}
+
+func (s *Spectre) dryRun(state *pairState) *DryRunResult {
+	state.relayMu.Lock()
+	defer state.relayMu.Unlock()
+	pair := state.pair
+
+	r := &DryRunResult{AssetPair: pair.AssetPair}
+	d, err := s.evaluate(pair)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Bar = d.bar
+	r.Age = d.age
+	r.Val = d.val
+	r.Spread = d.spread
+	r.Expired = d.expired
+	r.Stale = d.stale
+	r.Quorum = d.quorum()
+	r.Prices = s.dryRunPrices(d.prices.messages())
+	r.Excluded = s.dryRunPrices(d.excluded)
+	if d.update() && s.coord != nil {
+		r.Coordination = s.coord.mayPoke(pair, d.age, time.Now())
+	}
+	if r.Quorum {
+		if sim, ok := pair.Median.(PokeSimulator); ok {
+			r.Calldata, r.Simulation = sim.SimulatePoke(s.ctx, d.prices.oraclePrices())
+		} else {
+			r.Simulation = errSimulationNotSupported
+		}
+	}
+	return r
+}
+
+func (s *Spectre) dryRunPrices(msgs []*messages.Price) []DryRunPrice {
+	var prices []DryRunPrice
+	for _, msg := range msgs {
+		feeder, _ := msg.Price.From(s.signer)
+		prices = append(prices, DryRunPrice{Feeder: feeder, Price: msg.Price})
+	}
+	return prices
+}
@@ -0,0 +1,80 @@
+package spectre
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/datastore/memory/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+// testSimulatingMedian is a testMedian which is able to simulate updates.
+type testSimulatingMedian struct {
+	*testMedian
+
+	prices []*oracle.Price
+}
+
+func (m *testSimulatingMedian) SimulatePoke(_ context.Context, prices []*oracle.Price) ([]byte, error) {
+	m.prices = prices
+	return []byte{1, 2, 3}, nil
+}
+
+func TestSpectre_DryRun(t *testing.T) {
+	m := &testSimulatingMedian{testMedian: &testMedian{age: time.Unix(0, 0)}}
+	s := newTestSpectre(
+		t,
+		context.Background(),
+		&Pair{
+			AssetPair:        "XXXYYY",
+			Median:           &testMedian{age: time.Unix(0, 0)},
+			OracleExpiration: time.Hour,
+			PriceExpiration:  time.Since(time.Unix(0, 0)),
+		},
+		&Pair{
+			AssetPair:        "AAABBB",
+			Median:           m,
+			OracleExpiration: time.Hour,
+			PriceExpiration:  time.Since(time.Unix(0, 0)),
+		},
+		&Pair{
+			AssetPair: "CCCDDD",
+			Median:    &testMedian{},
+		},
+	)
+
+	results := s.DryRun()
+	require.Len(t, results, 3)
+
+	// The Oracle must be evaluated and the update simulated:
+	r := results[0]
+	assert.Equal(t, "AAABBB", r.AssetPair)
+	assert.NoError(t, r.Err)
+	assert.Equal(t, int64(1), r.Bar)
+	assert.True(t, r.Expired)
+	assert.True(t, r.Quorum)
+	assert.True(t, r.Update())
+	require.Len(t, r.Prices, 1)
+	assert.Equal(t, testutil.PriceAAABBB1.Price, r.Prices[0].Price)
+	assert.Equal(t, &testutil.Address1, r.Prices[0].Feeder)
+	assert.Equal(t, []byte{1, 2, 3}, r.Calldata)
+	assert.NoError(t, r.Simulation)
+	assert.Equal(t, []*oracle.Price{testutil.PriceAAABBB1.Price}, m.prices)
+
+	// There are no prices for the pair:
+	assert.Equal(t, "CCCDDD", results[1].AssetPair)
+	assert.Equal(t, errNoPrices{AssetPair: "CCCDDD"}, results[1].Err)
+	assert.False(t, results[1].Update())
+
+	// The Oracle does not support simulations:
+	assert.Equal(t, "XXXYYY", results[2].AssetPair)
+	assert.Equal(t, errSimulationNotSupported, results[2].Simulation)
+
+	// No transactions may be sent:
+	assert.Equal(t, int32(0), atomic.LoadInt32(&m.pokes))
+}
@@ -1,9 +1,10 @@
 package spectre
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"math/big"
-	"math/rand"
 	"sort"
 	"time"
 
@@ -42,21 +43,100 @@ func (p *prices) oraclePrices() []*oracle.Price {
 	return prices
 }
 
-// truncate removes random msgs until the number of remaining prices is equal
-// to n. If the number of prices is less or equal to n, it does nothing.
+// PriceSelection describes which prices are used to update an Oracle when
+// there are more prices than required to achieve a quorum. All strategies
+// are deterministic, so the same prices are always chosen from the same set.
+type PriceSelection int
+
+const (
+	// SelectClosestToMedian chooses prices closest to the median of all
+	// prices, so outliers are excluded first. Ties are resolved in favor of
+	// fresher prices.
+	SelectClosestToMedian PriceSelection = iota
+	// SelectFreshest chooses the most recent prices. Ties are resolved in
+	// favor of prices closer to the median.
+	SelectFreshest
+)
+
+// String implements the fmt.Stringer interface.
+func (s PriceSelection) String() string {
+	switch s {
+	case SelectClosestToMedian:
+		return "closestToMedian"
+	case SelectFreshest:
+		return "freshest"
+	}
+	return fmt.Sprintf("PriceSelection(%d)", int(s))
+}
+
+// truncate removes msgs until the number of remaining prices is equal to n,
+// using the given selection strategy. Removed messages are returned. If
+// the number of prices is less or equal to n, it does nothing.
 //
 // This method is used to reduce number of arguments in transaction which will
 // reduce transaction costs.
-func (p *prices) truncate(n int64) {
+func (p *prices) truncate(n int64, selection PriceSelection) []*messages.Price {
 	if int64(len(p.msgs)) <= n {
-		return
+		return nil
 	}
 
-	rand.Shuffle(len(p.msgs), func(i, j int) {
-		p.msgs[i], p.msgs[j] = p.msgs[j], p.msgs[i]
+	median := p.median()
+	sort.Slice(p.msgs, func(i, j int) bool {
+		a, b := p.msgs[i].Price, p.msgs[j].Price
+		var c int
+		switch selection {
+		case SelectFreshest:
+			c = compareAge(a, b)
+			if c == 0 {
+				c = compareDistance(a, b, median)
+			}
+		default:
+			c = compareDistance(a, b, median)
+			if c == 0 {
+				c = compareAge(a, b)
+			}
+		}
+		if c == 0 {
+			c = compareSignature(a, b)
+		}
+		return c < 0
 	})
 
+	excluded := p.msgs[n:]
 	p.msgs = p.msgs[0:n]
+	return excluded
+}
+
+// compareDistance compares the distances of prices to the median. Prices
+// closer to the median come first.
+func compareDistance(a, b *oracle.Price, median *big.Int) int {
+	da := new(big.Int).Abs(new(big.Int).Sub(a.Val, median))
+	db := new(big.Int).Abs(new(big.Int).Sub(b.Val, median))
+	return da.Cmp(db)
+}
+
+// compareAge compares the ages of prices. Fresher prices come first.
+func compareAge(a, b *oracle.Price) int {
+	switch {
+	case a.Age.After(b.Age):
+		return -1
+	case a.Age.Before(b.Age):
+		return 1
+	}
+	return 0
+}
+
+// compareSignature compares signatures of prices. It is used to order
+// prices which are otherwise equal, so the order never depends on the order
+// in which prices were received.
+func compareSignature(a, b *oracle.Price) int {
+	if c := bytes.Compare(a.R[:], b.R[:]); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(a.S[:], b.S[:]); c != 0 {
+		return c
+	}
+	return int(a.V) - int(b.V)
 }
 
 // median calculates the median price for all messages in the list.
@@ -3,13 +3,16 @@ package spectre
 import (
 	"math"
 	"math/big"
+	"math/rand"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore/memory/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 )
 
@@ -63,18 +66,141 @@ func TestPrices_truncate(t *testing.T) {
 	}
 
 	ps1 := newPrices(msgs)
-	ps1.truncate(5)
+	ps1.truncate(5, SelectClosestToMedian)
 	assert.Len(t, ps1.messages(), 4)
 
 	ps2 := newPrices(msgs)
-	ps2.truncate(4)
+	ps2.truncate(4, SelectClosestToMedian)
 	assert.Len(t, ps2.messages(), 4)
 
 	ps3 := newPrices(msgs)
-	ps3.truncate(3)
+	ps3.truncate(3, SelectClosestToMedian)
 	assert.Len(t, ps3.messages(), 3)
 }
 
+func newTestPrice(val int64, age int64, r byte) *messages.Price {
+	return &messages.Price{Price: &oracle.Price{
+		Wat: "AAABBB",
+		Val: big.NewInt(val),
+		Age: time.Unix(age, 0),
+		R:   [32]byte{r},
+	}}
+}
+
+func vals(msgs []*messages.Price) []int64 {
+	var v []int64
+	for _, m := range msgs {
+		v = append(v, m.Price.Val.Int64())
+	}
+	return v
+}
+
+func TestPrices_truncate_ClosestToMedian(t *testing.T) {
+	ps := newPrices([]*messages.Price{
+		newTestPrice(1, 10, 1),
+		newTestPrice(100, 10, 2),
+		newTestPrice(101, 9, 3),
+		newTestPrice(99, 11, 4),
+		newTestPrice(1000, 12, 5),
+	})
+	excluded := ps.truncate(3, SelectClosestToMedian)
+	assert.ElementsMatch(t, []int64{99, 100, 101}, vals(ps.messages()))
+	assert.ElementsMatch(t, []int64{1, 1000}, vals(excluded))
+}
+
+func TestPrices_truncate_Freshest(t *testing.T) {
+	ps := newPrices([]*messages.Price{
+		newTestPrice(1, 10, 1),
+		newTestPrice(100, 10, 2),
+		newTestPrice(101, 9, 3),
+		newTestPrice(99, 11, 4),
+		newTestPrice(1000, 12, 5),
+	})
+	// Prices with the same age are ordered by the distance to the median:
+	excluded := ps.truncate(3, SelectFreshest)
+	assert.ElementsMatch(t, []int64{1000, 99, 100}, vals(ps.messages()))
+	assert.ElementsMatch(t, []int64{1, 101}, vals(excluded))
+}
+
+func TestPrices_truncate_Deterministic(t *testing.T) {
+	msgs := []*messages.Price{
+		newTestPrice(90, 10, 1),
+		newTestPrice(110, 10, 2),
+		newTestPrice(90, 10, 3),
+		newTestPrice(110, 10, 4),
+		newTestPrice(100, 10, 5),
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, sel := range []PriceSelection{SelectClosestToMedian, SelectFreshest} {
+		var expected []*messages.Price
+		for i := 0; i < 20; i++ {
+			shuffled := append([]*messages.Price{}, msgs...)
+			rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			ps := newPrices(shuffled)
+			ps.truncate(3, sel)
+			if expected == nil {
+				expected = ps.messages()
+			}
+			assert.Equal(t, expected, ps.messages())
+		}
+	}
+}
+
+func TestPrices_truncate_MedianWithinBounds(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		var msgs []*messages.Price
+		count := 3 + rnd.Intn(20)
+		for j := 0; j < count; j++ {
+			msgs = append(msgs, newTestPrice(1000+rnd.Int63n(100), rnd.Int63n(100), byte(j)))
+		}
+		n := int64(1 + rnd.Intn(count))
+
+		full := newPrices(append([]*messages.Price{}, msgs...))
+		fullMedian := full.median()
+		sorted := vals(full.messages())
+
+		for _, sel := range []PriceSelection{SelectClosestToMedian, SelectFreshest} {
+			ps := newPrices(append([]*messages.Price{}, msgs...))
+			excluded := ps.truncate(n, sel)
+			require.Len(t, ps.messages(), int(n))
+			require.Len(t, excluded, count-int(n))
+
+			// The median of the chosen prices must be within the range of
+			// all prices:
+			median := ps.median().Int64()
+			assert.GreaterOrEqual(t, median, sorted[0])
+			assert.LessOrEqual(t, median, sorted[len(sorted)-1])
+
+			if sel != SelectClosestToMedian {
+				continue
+			}
+			// The chosen prices are closer to the median than the excluded
+			// ones, so the median of the chosen prices is within the range
+			// of the middle n prices of the full set:
+			lo, hi := (count-int(n))/2, (count+int(n)-1)/2
+			assert.GreaterOrEqual(t, median, sorted[lo])
+			assert.LessOrEqual(t, median, sorted[hi])
+			maxDist := int64(0)
+			for _, m := range ps.messages() {
+				if d := abs(m.Price.Val.Int64() - fullMedian.Int64()); d > maxDist {
+					maxDist = d
+				}
+			}
+			for _, m := range excluded {
+				assert.GreaterOrEqual(t, abs(m.Price.Val.Int64()-fullMedian.Int64()), maxDist)
+			}
+		}
+	}
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 func TestPrices_median_Even(t *testing.T) {
 	ps := newPrices([]*messages.Price{
 		testutil.PriceAAABBB1,
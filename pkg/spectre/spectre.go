@@ -4,17 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
 	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/txmanager"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 )
 
 const LoggerTag = "SPECTRE"
 
+// DefaultWorkers is the default number of pairs for which Oracles are
+// updated concurrently.
+const DefaultWorkers = 4
+
 type errNotEnoughPricesForQuorum struct {
 	AssetPair string
 }
@@ -42,24 +49,74 @@ func (e errNoPrices) Error() string {
 	return fmt.Sprintf("there is no prices in the datastore for %s pair", e.AssetPair)
 }
 
+type errPendingTx struct {
+	AssetPair string
+	Tx        ethereum.Hash
+}
+
+func (e errPendingTx) Error() string {
+	return fmt.Sprintf("previous update transaction %s for %s pair is still pending", e.Tx.String(), e.AssetPair)
+}
+
+// TxStatus provides statuses of sent transactions. It is implemented by
+// the txmanager.Manager.
+type TxStatus interface {
+	Status(hash ethereum.Hash) txmanager.Status
+}
+
 type Spectre struct {
 	ctx    context.Context
-	mu     sync.Mutex
 	doneCh chan struct{}
 
 	signer    ethereum.Signer
 	datastore datastore.Datastore
 	interval  time.Duration
+	workers   int
+	txStatus  TxStatus
+	coord     *coordinator
 	log       log.Logger
-	pairs     map[string]*Pair
+
+	// pairs is not modified after the Spectre is created, so it may be
+	// read without locking. The state of each pair is guarded by its own
+	// mutex.
+	pairs map[string]*pairState
+}
+
+// pairState contains the configuration of a pair and information about
+// the last check of its Oracle.
+type pairState struct {
+	pair *Pair
+
+	// relayMu is held while the Oracle of the pair is checked and updated,
+	// so reads for the same pair never overlap. It also guards pendingTx.
+	relayMu sync.Mutex
+	// pendingTx is the hash of the last update transaction, if its status
+	// is not resolved yet.
+	pendingTx *ethereum.Hash
+
+	// mu guards the fields below.
+	mu        sync.Mutex
+	busy      bool
+	lastCheck time.Time
 }
 
 type Config struct {
 	Signer ethereum.Signer
 	// Datastore provides prices for Spectre.
 	Datastore datastore.Datastore
-	// Interval describes how often we should try to update Oracles.
+	// Interval describes how often we should try to update Oracles. It is
+	// used for pairs which do not define their own interval.
 	Interval time.Duration
+	// Workers is the maximum number of pairs for which Oracles are updated
+	// concurrently. If zero, the DefaultWorkers value is used.
+	Workers int
+	// TxStatus is used to check if the previous update transaction for
+	// a pair is still pending. A new transaction is not sent until then.
+	// If nil, transactions are not tracked.
+	TxStatus TxStatus
+	// Coordination enables taking turns with other relayers. If nil,
+	// Oracles are updated whenever they are expired or stale.
+	Coordination *Coordination
 	// Pairs is the list supported pairs by Spectre with their configuration.
 	Pairs []*Pair
 	// Logger is a current logger interface used by the Spectre. The Logger is
@@ -70,6 +127,9 @@ type Config struct {
 type Pair struct {
 	// AssetPair is the name of asset pair, e.g. ETHUSD.
 	AssetPair string
+	// Interval describes how often the Oracle is checked. If zero,
+	// the Spectre's interval is used.
+	Interval time.Duration
 	// OracleSpread is the minimum spread between the Oracle price and new price
 	// required to send update.
 	OracleSpread float64
@@ -79,6 +139,9 @@ type Pair struct {
 	// PriceExpiration is the maximum amount of time before price received
 	// from the feeder will be considered as expired.
 	PriceExpiration time.Duration
+	// PriceSelection describes which prices are used if there are more
+	// prices than required to achieve a quorum.
+	PriceSelection PriceSelection
 	// Median is the instance of the oracle.Median which is the interface for
 	// the Oracle contract.
 	Median oracle.Median
@@ -88,17 +151,30 @@ func NewSpectre(ctx context.Context, cfg Config) (*Spectre, error) {
 	if ctx == nil {
 		return nil, errors.New("context must not be nil")
 	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
 	r := &Spectre{
 		ctx:       ctx,
 		doneCh:    make(chan struct{}),
 		signer:    cfg.Signer,
 		datastore: cfg.Datastore,
 		interval:  cfg.Interval,
-		pairs:     make(map[string]*Pair),
+		workers:   workers,
+		txStatus:  cfg.TxStatus,
+		pairs:     make(map[string]*pairState),
 		log:       cfg.Logger.WithField("tag", LoggerTag),
 	}
 	for _, p := range cfg.Pairs {
-		r.pairs[p.AssetPair] = p
+		r.pairs[p.AssetPair] = &pairState{pair: p}
+	}
+	if cfg.Coordination != nil {
+		coord, err := newCoordinator(cfg.Signer.Address(), cfg.Coordination, r.log)
+		if err != nil {
+			return nil, err
+		}
+		r.coord = coord
 	}
 	return r, nil
 }
@@ -107,6 +183,9 @@ func (s *Spectre) Start() error {
 	s.log.Info("Starting")
 
 	go s.contextCancelHandler()
+	if s.coord != nil {
+		s.coord.intentLoop(s.ctx)
+	}
 	s.relayerLoop()
 
 	return nil
@@ -117,19 +196,93 @@ func (s *Spectre) Wait() {
 }
 
 // relay tries to update an Oracle contract for given pair. It'll return
-// transaction hash or nil if there is no need to update Oracle.
+// transaction hash or nil if there is no need to update Oracle. Calls for
+// the same pair are serialized, calls for different pairs may run
+// concurrently.
 func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	pair, ok := s.pairs[assetPair]
+	state, ok := s.pairs[assetPair]
 	if !ok {
 		return nil, errUnknownAsset{AssetPair: assetPair}
 	}
+	state.relayMu.Lock()
+	defer state.relayMu.Unlock()
+	pair := state.pair
+
+	// Do not send another transaction until the previous one is resolved:
+	if state.pendingTx != nil && s.txStatus != nil {
+		if s.txStatus.Status(*state.pendingTx) == txmanager.StatusPending {
+			return nil, errPendingTx{AssetPair: assetPair, Tx: *state.pendingTx}
+		}
+		state.pendingTx = nil
+	}
+
+	d, err := s.evaluate(pair)
+	if err != nil {
+		return nil, err
+	}
 
-	prices := newPrices(s.datastore.Prices().AssetPair(assetPair))
+	if d.update() {
+		// Check if there are enough prices to achieve a quorum:
+		if !d.quorum() {
+			return nil, errNotEnoughPricesForQuorum{AssetPair: assetPair}
+		}
+
+		// Let other relayers update the Oracle if it is not our turn:
+		now := time.Now()
+		if s.coord != nil {
+			if err := s.coord.mayPoke(pair, d.age, now); err != nil {
+				return nil, err
+			}
+		}
+
+		// Send *actual* transaction to the Ethereum network:
+		tx, err := pair.Median.Poke(s.ctx, d.prices.oraclePrices(), true)
+		if err != nil {
+			return nil, err
+		}
+		state.pendingTx = tx
+
+		// Other relayers are informed only about sent transactions,
+		// otherwise they could back off while nobody updates the Oracle:
+		if s.coord != nil {
+			s.coord.announce(assetPair, d.age, now)
+		}
+		return tx, nil
+	}
+
+	// There is no need to update Oracle:
+	return nil, nil
+}
+
+// decision contains the state of an Oracle and prices which may be used
+// to update it.
+type decision struct {
+	bar      int64
+	age      time.Time
+	val      *big.Int
+	spread   float64
+	expired  bool
+	stale    bool
+	prices   *prices
+	excluded []*messages.Price
+}
+
+// update returns true if the Oracle should be updated.
+func (d *decision) update() bool {
+	return d.expired || d.stale
+}
+
+// quorum returns true if there are enough prices to update the Oracle.
+func (d *decision) quorum() bool {
+	return int64(d.prices.len()) == d.bar
+}
+
+// evaluate reads the state of the Oracle for the pair and chooses prices
+// which may be used to update it.
+func (s *Spectre) evaluate(pair *Pair) (*decision, error) {
+	prices := newPrices(s.datastore.Prices().AssetPair(pair.AssetPair))
 	if prices == nil || prices.len() == 0 {
-		return nil, errNoPrices{AssetPair: assetPair}
+		return nil, errNoPrices{AssetPair: pair.AssetPair}
 	}
 
 	oracleQuorum, err := pair.Median.Bar(s.ctx)
@@ -150,7 +303,7 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 	prices.clearOlderThan(oracleTime)
 
 	// Use only a minimum prices required to achieve a quorum:
-	prices.truncate(oracleQuorum)
+	excluded := prices.truncate(oracleQuorum, pair.PriceSelection)
 
 	spread := prices.spread(oraclePrice)
 	isExpired := oracleTime.Add(pair.OracleExpiration).Before(time.Now())
@@ -159,7 +312,7 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 	// Print logs:
 	s.log.
 		WithFields(log.Fields{
-			"assetPair":        assetPair,
+			"assetPair":        pair.AssetPair,
 			"bar":              oracleQuorum,
 			"age":              oracleTime.String(),
 			"val":              oraclePrice.String(),
@@ -169,6 +322,7 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 			"oracleSpread":     pair.OracleSpread,
 			"timeToExpiration": time.Since(oracleTime).String(),
 			"currentSpread":    spread,
+			"priceSelection":   pair.PriceSelection.String(),
 		}).
 		Debug("Trying to update Oracle")
 	for _, price := range prices.oraclePrices() {
@@ -176,58 +330,58 @@ func (s *Spectre) relay(assetPair string) (*ethereum.Hash, error) {
 			WithFields(price.Fields(s.signer)).
 			Debug("Feed")
 	}
-
-	if isExpired || isStale {
-		// Check if there are enough prices to achieve a quorum:
-		if int64(prices.len()) != oracleQuorum {
-			return nil, errNotEnoughPricesForQuorum{AssetPair: assetPair}
-		}
-
-		// Send *actual* transaction to the Ethereum network:
-		tx, err := pair.Median.Poke(s.ctx, prices.oraclePrices(), true)
-		return tx, err
+	for _, price := range excluded {
+		s.log.
+			WithFields(price.Price.Fields(s.signer)).
+			Debug("Excluded feed")
 	}
 
-	// There is no need to update Oracle:
-	return nil, nil
+	return &decision{
+		bar:      oracleQuorum,
+		age:      oracleTime,
+		val:      oraclePrice,
+		spread:   spread,
+		expired:  isExpired,
+		stale:    isStale,
+		prices:   prices,
+		excluded: excluded,
+	}, nil
 }
 
 // relayerLoop creates a asynchronous loop which tries to send an update
-// to an Oracle contract at a specified interval.
+// to an Oracle contract at a specified interval. The loop ticks as often as
+// the GCD of all pair intervals and on every tick pairs whose interval has
+// elapsed are passed to a pool of workers. A pair which is still being
+// checked is not queued again, so a slow RPC or a pending transaction
+// delays only its own pair.
 func (s *Spectre) relayerLoop() {
 	if s.interval == 0 {
 		return
 	}
 
-	ticker := time.NewTicker(s.interval)
+	// The channel is large enough to hold all pairs, and every pair is
+	// queued at most once, so sending never blocks:
+	pairCh := make(chan *pairState, len(s.pairs))
+	for i := 0; i < s.workers; i++ {
+		go s.relayWorker(pairCh)
+	}
+
+	tick := s.tickInterval()
+	ticker := time.NewTicker(tick)
 	go func() {
 		for {
 			select {
 			case <-s.doneCh:
 				ticker.Stop()
 				return
-			case <-ticker.C:
-				for assetPair := range s.pairs {
-					tx, err := s.relay(assetPair)
-
-					// Print log in case of an error:
-					if err != nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							WithError(err).
-							Warn("Unable to update Oracle")
-					}
-					// Print log if there was no need to update prices:
-					if err == nil && tx == nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair}).
-							Info("Oracle price is still valid")
-					}
-					// Print log if Oracle update transaction was sent:
-					if tx != nil {
-						s.log.
-							WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
-							Info("Oracle updated")
+			case t := <-ticker.C:
+				// Half of the tick is added to the current time to check
+				// pairs which would be due before the next tick, otherwise
+				// small delays of the ticker could cause skipping a tick:
+				due := t.Add(tick / 2)
+				for _, state := range s.pairs {
+					if s.markDue(state, t, due) {
+						pairCh <- state
 					}
 				}
 			}
@@ -235,6 +389,111 @@ func (s *Spectre) relayerLoop() {
 	}()
 }
 
+// relayWorker relays prices for pairs received from the channel until
+// the Spectre is stopped.
+func (s *Spectre) relayWorker(pairCh chan *pairState) {
+	for {
+		select {
+		case <-s.doneCh:
+			return
+		case state := <-pairCh:
+			s.relayAndLog(state.pair.AssetPair)
+			state.mu.Lock()
+			state.busy = false
+			state.mu.Unlock()
+		}
+	}
+}
+
+// markDue checks if the pair's interval has elapsed at the due time and
+// the pair is not being checked already. If so, it marks the pair as busy
+// and returns true.
+func (s *Spectre) markDue(state *pairState, now, due time.Time) bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.busy || state.lastCheck.Add(s.pairInterval(state.pair)).After(due) {
+		return false
+	}
+	state.busy = true
+	state.lastCheck = now
+	return true
+}
+
+// relayAndLog relays prices for the pair and logs the result.
+func (s *Spectre) relayAndLog(assetPair string) {
+	tx, err := s.relay(assetPair)
+
+	// Print log if the previous transaction is not mined yet:
+	var pendingErr errPendingTx
+	if errors.As(err, &pendingErr) {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair, "tx": pendingErr.Tx.String()}).
+			Info("Previous Oracle update is still pending")
+		return
+	}
+	// Print log if another relayer is going to update the Oracle:
+	var notOurTurnErr errNotOurTurn
+	var intentErr errIntentAnnounced
+	if errors.As(err, &notOurTurnErr) || errors.As(err, &intentErr) {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Info("Oracle update left to another relayer")
+		return
+	}
+	// Print log if the update was deferred, it will be retried on the next
+	// check of the pair:
+	if errors.Is(err, oracle.ErrTransactionDeferred) {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Warn("Oracle update deferred")
+		return
+	}
+	// Print log in case of an error:
+	if err != nil {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			WithError(err).
+			Warn("Unable to update Oracle")
+	}
+	// Print log if there was no need to update prices:
+	if err == nil && tx == nil {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair}).
+			Info("Oracle price is still valid")
+	}
+	// Print log if Oracle update transaction was sent:
+	if tx != nil {
+		s.log.
+			WithFields(log.Fields{"assetPair": assetPair, "tx": tx.String()}).
+			Info("Oracle updated")
+	}
+}
+
+// tickInterval returns the GCD of intervals of all pairs.
+func (s *Spectre) tickInterval() time.Duration {
+	tick := s.interval
+	for _, state := range s.pairs {
+		a, b := tick, s.pairInterval(state.pair)
+		for b != 0 {
+			a, b = b, a%b
+		}
+		tick = a
+	}
+	if tick < time.Second {
+		tick = time.Second
+	}
+	return tick
+}
+
+func (s *Spectre) pairInterval(p *Pair) time.Duration {
+	if p.Interval > 0 {
+		return p.Interval
+	}
+	return s.interval
+}
+
 func (s *Spectre) contextCancelHandler() {
 	defer func() { close(s.doneCh) }()
 	defer s.log.Info("Stopped")
@@ -0,0 +1,195 @@
+package spectre
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/datastore"
+	datastoreMemory "github.com/toknowwhy/theunit-oracle/pkg/datastore/memory"
+	"github.com/toknowwhy/theunit-oracle/pkg/datastore/memory/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	ethereumMocks "github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/txmanager"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+	"github.com/toknowwhy/theunit-oracle/pkg/oracle"
+)
+
+type testDatastore struct {
+	prices *datastoreMemory.PriceStore
+}
+
+func (d *testDatastore) Start() error                 { return nil }
+func (d *testDatastore) Wait()                        {}
+func (d *testDatastore) Prices() datastore.PriceStore { return d.prices }
+
+// testMedian is an oracle.Median implementation which counts calls of
+// the Bar method and blocks them until the release channel is closed.
+type testMedian struct {
+	oracle.Median
+
+	release chan struct{}
+	calls   int32
+	running int32
+	overlap int32
+
+	age     time.Time
+	pokes   int32
+	pokeErr error
+}
+
+func (m *testMedian) Bar(context.Context) (int64, error) {
+	atomic.AddInt32(&m.calls, 1)
+	if atomic.AddInt32(&m.running, 1) > 1 {
+		atomic.StoreInt32(&m.overlap, 1)
+	}
+	defer atomic.AddInt32(&m.running, -1)
+	if m.release != nil {
+		<-m.release
+	}
+	return 1, nil
+}
+
+func (m *testMedian) Age(context.Context) (time.Time, error) {
+	if m.age.IsZero() {
+		return time.Now(), nil
+	}
+	return m.age, nil
+}
+
+func (m *testMedian) Poke(context.Context, []*oracle.Price, bool) (*ethereum.Hash, error) {
+	atomic.AddInt32(&m.pokes, 1)
+	if m.pokeErr != nil {
+		return nil, m.pokeErr
+	}
+	return &ethereum.Hash{1}, nil
+}
+
+func (m *testMedian) Val(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (m *testMedian) Address() common.Address {
+	return common.Address{}
+}
+
+func newTestSpectre(t *testing.T, ctx context.Context, pairs ...*Pair) *Spectre {
+	ps := datastoreMemory.NewPriceStore()
+	ps.Add(testutil.Address1, testutil.PriceAAABBB1)
+	ps.Add(testutil.Address1, testutil.PriceXXXYYY1)
+	signer := &ethereumMocks.Signer{}
+	signer.On("Recover", mock.Anything, mock.Anything).Return(&testutil.Address1, nil)
+	s, err := NewSpectre(ctx, Config{
+		Signer:    signer,
+		Datastore: &testDatastore{prices: ps},
+		Interval:  time.Second,
+		Pairs:     pairs,
+		Logger:    null.New(),
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestSpectre_relay_NoOverlap(t *testing.T) {
+	m := &testMedian{release: make(chan struct{})}
+	s := newTestSpectre(t, context.Background(), &Pair{AssetPair: "AAABBB", Median: m, OracleExpiration: time.Hour})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.relay("AAABBB")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(m.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&m.calls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&m.overlap))
+}
+
+func TestSpectre_relayerLoop_SlowPair(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	slow := &testMedian{release: make(chan struct{})}
+	fast := &testMedian{}
+	s := newTestSpectre(
+		t,
+		ctx,
+		&Pair{AssetPair: "AAABBB", Median: slow, OracleExpiration: time.Hour},
+		&Pair{AssetPair: "XXXYYY", Median: fast, OracleExpiration: time.Hour},
+	)
+	require.NoError(t, s.Start())
+
+	// The slow pair must not block the fast one:
+	time.Sleep(2500 * time.Millisecond)
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&fast.calls), int32(2))
+
+	// The slow pair must not be queued again while it is checked:
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slow.calls))
+	close(slow.release)
+
+	ctxCancel()
+	s.Wait()
+}
+
+type testTxStatus struct {
+	status txmanager.Status
+}
+
+func (s *testTxStatus) Status(ethereum.Hash) txmanager.Status {
+	return s.status
+}
+
+func TestSpectre_relay_PendingTx(t *testing.T) {
+	m := &testMedian{age: time.Unix(0, 0)}
+	txs := &testTxStatus{status: txmanager.StatusPending}
+	s := newTestSpectre(t, context.Background(), &Pair{
+		AssetPair:        "AAABBB",
+		Median:           m,
+		OracleExpiration: time.Hour,
+		PriceExpiration:  time.Since(time.Unix(0, 0)),
+	})
+	s.txStatus = txs
+
+	tx, err := s.relay("AAABBB")
+	require.NoError(t, err)
+	assert.Equal(t, ethereum.Hash{1}, *tx)
+
+	// The previous transaction is still pending:
+	_, err = s.relay("AAABBB")
+	assert.Equal(t, errPendingTx{AssetPair: "AAABBB", Tx: ethereum.Hash{1}}, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&m.pokes))
+
+	// The previous transaction is mined:
+	txs.status = txmanager.StatusMined
+	_, err = s.relay("AAABBB")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&m.pokes))
+}
+
+func TestSpectre_tickInterval(t *testing.T) {
+	s := newTestSpectre(
+		t,
+		context.Background(),
+		&Pair{AssetPair: "AAABBB", Interval: 4 * time.Second},
+		&Pair{AssetPair: "XXXYYY", Interval: 6 * time.Second},
+	)
+	s.interval = 10 * time.Second
+	assert.Equal(t, 2*time.Second, s.tickInterval())
+
+	// The interval is never lower than one second:
+	s.pairs["AAABBB"].pair.Interval = time.Millisecond
+	assert.Equal(t, time.Second, s.tickInterval())
+}
@@ -0,0 +1,148 @@
+package spectre
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+// OracleStatus describes the health of an Oracle and prices available to
+// update it.
+type OracleStatus struct {
+	AssetPair string
+	Contract  ethereum.Address
+	// Err is set if the state of the Oracle could not be read. Fields
+	// below are not set in that case.
+	Err error
+
+	Wat string
+	Age time.Time
+	Val *big.Int
+	Bar int64
+	// Feeds is the list of feeds lifted in the Oracle contract.
+	Feeds []ethereum.Address
+	// OracleExpiration is the configured expiration of the Oracle price.
+	OracleExpiration time.Duration
+	// TimeToExpiration is the time left until the Oracle price expires. It
+	// is negative if the price is already expired.
+	TimeToExpiration time.Duration
+	// OracleSpread is the configured spread which triggers an update.
+	OracleSpread float64
+	// Spread is the spread between the Oracle price and the median of
+	// prices which would be used to update it. It is +Inf if there are no
+	// valid prices.
+	Spread float64
+	// ValidPrices is the number of fresh prices from lifted feeds which are
+	// newer than the Oracle price.
+	ValidPrices int
+	// InactiveFeeds is the list of lifted feeds which have not sent
+	// a fresh price.
+	InactiveFeeds []ethereum.Address
+}
+
+// Expired returns true if the Oracle price is expired.
+func (s *OracleStatus) Expired() bool {
+	return s.TimeToExpiration < 0
+}
+
+// Stale returns true if the spread exceeds the configured one.
+func (s *OracleStatus) Stale() bool {
+	return s.Spread >= s.OracleSpread
+}
+
+// Quorum returns true if there are enough valid prices to update
+// the Oracle.
+func (s *OracleStatus) Quorum() bool {
+	return int64(s.ValidPrices) >= s.Bar
+}
+
+// Status reads the state of Oracles for all pairs and compares it with
+// the given prices, e.g. prices from a datastore or a Spire agent. Results
+// are sorted by asset pair.
+func (s *Spectre) Status(prices []*messages.Price) []*OracleStatus {
+	pairPrices := make(map[string][]*messages.Price)
+	for _, msg := range prices {
+		pairPrices[msg.Price.Wat] = append(pairPrices[msg.Price.Wat], msg)
+	}
+	var statuses []*OracleStatus
+	for _, state := range s.pairs {
+		statuses = append(statuses, s.status(state.pair, pairPrices[state.pair.AssetPair]))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].AssetPair < statuses[j].AssetPair
+	})
+	return statuses
+}
+
+func (s *Spectre) status(pair *Pair, msgs []*messages.Price) *OracleStatus {
+	st := &OracleStatus{
+		AssetPair:        pair.AssetPair,
+		Contract:         pair.Median.Address(),
+		OracleExpiration: pair.OracleExpiration,
+		OracleSpread:     pair.OracleSpread,
+	}
+
+	var err error
+	if st.Wat, err = pair.Median.Wat(s.ctx); err != nil {
+		st.Err = err
+		return st
+	}
+	if st.Age, err = pair.Median.Age(s.ctx); err != nil {
+		st.Err = err
+		return st
+	}
+	if st.Val, err = pair.Median.Val(s.ctx); err != nil {
+		st.Err = err
+		return st
+	}
+	if st.Bar, err = pair.Median.Bar(s.ctx); err != nil {
+		st.Err = err
+		return st
+	}
+	if st.Feeds, err = pair.Median.Feeds(s.ctx); err != nil {
+		st.Err = err
+		return st
+	}
+	st.Wat = strings.TrimRight(st.Wat, "\x00")
+
+	now := time.Now()
+	st.TimeToExpiration = st.Age.Add(pair.OracleExpiration).Sub(now)
+
+	lifted := make(map[ethereum.Address]bool)
+	for _, f := range st.Feeds {
+		lifted[f] = true
+	}
+	active := make(map[ethereum.Address]bool)
+	var valid []*messages.Price
+	for _, msg := range msgs {
+		from, err := msg.Price.From(s.signer)
+		if err != nil || !lifted[*from] {
+			continue
+		}
+		if msg.Price.Age.Before(now.Add(-1 * pair.PriceExpiration)) {
+			continue
+		}
+		active[*from] = true
+		if msg.Price.Age.Before(st.Age) {
+			continue
+		}
+		valid = append(valid, msg)
+	}
+	for _, f := range st.Feeds {
+		if !active[f] {
+			st.InactiveFeeds = append(st.InactiveFeeds, f)
+		}
+	}
+
+	// Calculate the spread in the same way as before an update:
+	prices := newPrices(valid)
+	st.ValidPrices = prices.len()
+	prices.truncate(st.Bar, pair.PriceSelection)
+	st.Spread = prices.spread(st.Val)
+
+	return st
+}
@@ -0,0 +1,83 @@
+package spectre
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/datastore/memory/testutil"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+)
+
+// testStatusMedian is a testMedian which also returns the asset name and
+// lifted feeds.
+type testStatusMedian struct {
+	*testMedian
+
+	feeds []ethereum.Address
+	err   error
+}
+
+func (m *testStatusMedian) Wat(context.Context) (string, error) {
+	return "AAABBB\x00\x00", m.err
+}
+
+func (m *testStatusMedian) Feeds(context.Context) ([]ethereum.Address, error) {
+	return m.feeds, nil
+}
+
+func TestSpectre_Status(t *testing.T) {
+	m := &testStatusMedian{
+		testMedian: &testMedian{age: time.Unix(50, 0)},
+		feeds:      []ethereum.Address{testutil.Address1, testutil.Address2},
+	}
+	s := newTestSpectre(
+		t,
+		context.Background(),
+		&Pair{
+			AssetPair:        "AAABBB",
+			Median:           m,
+			OracleSpread:     1,
+			OracleExpiration: time.Hour,
+			PriceExpiration:  time.Since(time.Unix(0, 0)),
+		},
+		&Pair{
+			AssetPair: "XXXYYY",
+			Median:    &testStatusMedian{testMedian: &testMedian{}, err: errors.New("err")},
+		},
+	)
+
+	statuses := s.Status([]*messages.Price{testutil.PriceAAABBB1, testutil.PriceXXXYYY1})
+	require.Len(t, statuses, 2)
+
+	st := statuses[0]
+	require.NoError(t, st.Err)
+	assert.Equal(t, "AAABBB", st.AssetPair)
+	assert.Equal(t, "AAABBB", st.Wat)
+	assert.Equal(t, int64(1), st.Bar)
+	assert.True(t, st.Expired())
+	assert.Equal(t, time.Unix(50, 0).Add(time.Hour), time.Now().Add(st.TimeToExpiration).Round(time.Second))
+	assert.Equal(t, 1, st.ValidPrices)
+	assert.True(t, st.Quorum())
+	// The Oracle price is 1 and the feed price is 10:
+	assert.Equal(t, float64(900), st.Spread)
+	assert.True(t, st.Stale())
+	assert.Equal(t, []ethereum.Address{testutil.Address2}, st.InactiveFeeds)
+
+	// The state of the Oracle could not be read:
+	assert.Equal(t, "XXXYYY", statuses[1].AssetPair)
+	assert.Error(t, statuses[1].Err)
+
+	// There are no prices:
+	st = s.Status(nil)[0]
+	assert.Equal(t, 0, st.ValidPrices)
+	assert.False(t, st.Quorum())
+	assert.True(t, math.IsInf(st.Spread, 1))
+	assert.Equal(t, []ethereum.Address{testutil.Address1, testutil.Address2}, st.InactiveFeeds)
+}
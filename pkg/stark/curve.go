@@ -0,0 +1,126 @@
+package stark
+
+import (
+	"math/big"
+)
+
+// Parameters of the STARK-friendly elliptic curve used by StarkEx and
+// Starknet: y^2 = x^3 + alpha*x + beta (mod fieldPrime).
+var (
+	fieldPrime = hexToInt("800000000000011000000000000000000000000000000000000000000000001")
+	curveAlpha = big.NewInt(1)
+	curveBeta  = hexToInt("6f21413efbe40de150e596d72f7a8c5609ad26c15c915c1f4cdfcb99cee9e89")
+	curveOrder = hexToInt("800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f")
+	generator  = point{
+		x: hexToInt("1ef15c18599971b7beced415a40f0c7deacfd9b0d1819e03d723d8bc943cfca"),
+		y: hexToInt("5668060aa49730b7be4801df46ec62de53ecd11abe43a32873000c36e8dc1f"),
+	}
+)
+
+// point is a point on the curve in affine coordinates. The point at
+// infinity is represented by nil coordinates.
+type point struct {
+	x, y *big.Int
+}
+
+func (p point) isInfinity() bool {
+	return p.x == nil
+}
+
+func (p point) neg() point {
+	if p.isInfinity() {
+		return p
+	}
+	return point{x: p.x, y: new(big.Int).Sub(fieldPrime, p.y)}
+}
+
+// add returns a + b.
+func add(a, b point) point {
+	switch {
+	case a.isInfinity():
+		return b
+	case b.isInfinity():
+		return a
+	case a.x.Cmp(b.x) == 0:
+		if a.y.Cmp(b.y) == 0 && a.y.Sign() != 0 {
+			return double(a)
+		}
+		return point{}
+	}
+	// m = (b.y - a.y) / (b.x - a.x)
+	m := new(big.Int).Sub(b.y, a.y)
+	d := new(big.Int).Sub(b.x, a.x)
+	d.Mod(d, fieldPrime).ModInverse(d, fieldPrime)
+	m.Mul(m, d).Mod(m, fieldPrime)
+	return line(a, b.x, m)
+}
+
+// double returns 2a.
+func double(a point) point {
+	if a.isInfinity() || a.y.Sign() == 0 {
+		return point{}
+	}
+	// m = (3 * a.x^2 + alpha) / (2 * a.y)
+	m := new(big.Int).Mul(a.x, a.x)
+	m.Mul(m, big.NewInt(3)).Add(m, curveAlpha)
+	d := new(big.Int).Lsh(a.y, 1)
+	d.ModInverse(d, fieldPrime)
+	m.Mul(m, d).Mod(m, fieldPrime)
+	return line(a, a.x, m)
+}
+
+// line returns the third intersection point of the line with the slope m,
+// which goes through the a and b points, mirrored over the x axis. Only
+// the x coordinate of the b point is needed.
+func line(a point, bx, m *big.Int) point {
+	// x = m^2 - a.x - b.x
+	x := new(big.Int).Mul(m, m)
+	x.Sub(x, a.x).Sub(x, bx).Mod(x, fieldPrime)
+	// y = m * (a.x - x) - a.y
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, m).Sub(y, a.y).Mod(y, fieldPrime)
+	return point{x: x, y: y}
+}
+
+// mul returns k * a.
+func mul(a point, k *big.Int) point {
+	r := point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = double(r)
+		if k.Bit(i) == 1 {
+			r = add(r, a)
+		}
+	}
+	return r
+}
+
+// pointFromX returns a point with the given x coordinate. Because there
+// are two such points, the one with the lower y coordinate is returned.
+// It returns false if there is no point with the given x coordinate.
+func pointFromX(x *big.Int) (point, bool) {
+	if x.Sign() < 0 || x.Cmp(fieldPrime) >= 0 {
+		return point{}, false
+	}
+	// y^2 = x^3 + alpha*x + beta
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	y2.Add(y2, new(big.Int).Mul(curveAlpha, x))
+	y2.Add(y2, curveBeta).Mod(y2, fieldPrime)
+	y := new(big.Int).ModSqrt(y2, fieldPrime)
+	if y == nil {
+		return point{}, false
+	}
+	p := point{x: new(big.Int).Set(x), y: y}
+	if n := p.neg(); n.y.Cmp(p.y) < 0 {
+		return n, true
+	}
+	return p, true
+}
+
+func hexToInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex number: " + s)
+	}
+	return n
+}
@@ -0,0 +1,55 @@
+package stark
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Constant points used by the Pedersen hash function. The first point is
+// the shift point, the remaining ones are used to hash the low and high
+// parts of both inputs.
+var pedersenPoints = [5]point{
+	{
+		x: hexToInt("49ee3eba8c1600700ee1b87eb599f16716b0b1022947733551fde4050ca6804"),
+		y: hexToInt("3ca0cfe4b3bc6ddf346d49d06ea0ed34e621062c0e056c1d0405d266e10268a"),
+	},
+	{
+		x: hexToInt("234287dcbaffe7f969c748655fca9e58fa8120b6d56eb0c1080d17957ebe47b"),
+		y: hexToInt("3b056f100f96fb21e889527d41f4e39940135dd7a6c94cc6ed0268ee89e5615"),
+	},
+	{
+		x: hexToInt("4fa56f376c83db33f9dab2656558f3399099ec1de5e3018b7a6932dba8aa378"),
+		y: hexToInt("3fa0984c931c9e38113e0c0e47e4401562761f92a7a23b45168f4e80ff5b54d"),
+	},
+	{
+		x: hexToInt("4ba4cc166be8dec764910f75b45f74b40c690c74709e90f3aa372f0bd2d6997"),
+		y: hexToInt("40301cf5c1751f4b971e46c4ede85fcac5c59a5ce5ae7c48151f27b24b219c"),
+	},
+	{
+		x: hexToInt("54302dcb0e6cc1c6e44cca8f61a63bb2ca65048d53fb325d36ff12c49a58202"),
+		y: hexToInt("1b77b3e37d13504b348046268d8ae25ce98ad783c25561a879dcc77e99c2426"),
+	},
+}
+
+// pedersenLowBits is the number of bits in the low part of an input.
+const pedersenLowBits = 248
+
+var pedersenLowMask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), pedersenLowBits), big.NewInt(1))
+
+var ErrInvalidPedersenInput = errors.New("pedersen hash input must be a non-negative number lower than the field prime")
+
+// PedersenHash returns the Pedersen hash of two field elements, the same
+// as the one used by StarkEx and Starknet.
+func PedersenHash(a, b *big.Int) (*big.Int, error) {
+	r := pedersenPoints[0]
+	for i, n := range []*big.Int{a, b} {
+		if n.Sign() < 0 || n.Cmp(fieldPrime) >= 0 {
+			return nil, ErrInvalidPedersenInput
+		}
+		low := new(big.Int).And(n, pedersenLowMask)
+		high := new(big.Int).Rsh(n, pedersenLowBits)
+		r = add(r, mul(pedersenPoints[1+i*2], low))
+		r = add(r, mul(pedersenPoints[2+i*2], high))
+	}
+	return r.x, nil
+}
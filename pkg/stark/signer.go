@@ -0,0 +1,180 @@
+package stark
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// MaxHashBits is the maximum number of bits of a signed hash.
+const MaxHashBits = 251
+
+var maxHash = new(big.Int).Lsh(big.NewInt(1), MaxHashBits)
+
+var ErrInvalidSignature = errors.New("invalid STARK signature")
+var ErrInvalidHash = fmt.Errorf("hash must be a non-negative number lower than 2^%d", MaxHashBits)
+var ErrInvalidPrivateKey = errors.New("private key must be a positive number lower than the curve order")
+
+// Signature is an ECDSA signature created using the STARK curve.
+type Signature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// Signer signs hashes using the STARK curve, in the same way as StarkEx
+// does.
+type Signer interface {
+	// PublicKey returns the public key, which is the x coordinate of
+	// the public key point.
+	PublicKey() *big.Int
+	// Signature signs the hash. The hash must be lower than 2^251.
+	Signature(hash *big.Int) (*Signature, error)
+}
+
+// PrivateKeySigner implements the Signer interface using a private key
+// stored in memory.
+type PrivateKeySigner struct {
+	privateKey *big.Int
+	publicKey  *big.Int
+}
+
+// NewSigner returns a new PrivateKeySigner instance.
+func NewSigner(privateKey *big.Int) (*PrivateKeySigner, error) {
+	if privateKey.Sign() <= 0 || privateKey.Cmp(curveOrder) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	return &PrivateKeySigner{
+		privateKey: new(big.Int).Set(privateKey),
+		publicKey:  mul(generator, privateKey).x,
+	}, nil
+}
+
+// PublicKey implements the Signer interface.
+func (s *PrivateKeySigner) PublicKey() *big.Int {
+	return new(big.Int).Set(s.publicKey)
+}
+
+// Signature implements the Signer interface. The nonce is generated
+// deterministically from the private key and the hash, as described in
+// RFC 6979.
+func (s *PrivateKeySigner) Signature(hash *big.Int) (*Signature, error) {
+	if hash.Sign() < 0 || hash.Cmp(maxHash) >= 0 {
+		return nil, ErrInvalidHash
+	}
+	nonces := newNonceGenerator(s.privateKey, hash)
+	for {
+		k := nonces.next()
+		r := mul(generator, k).x
+		if r.Sign() == 0 || r.Cmp(maxHash) >= 0 {
+			continue
+		}
+		// w = k / (hash + r * privateKey) mod order
+		m := new(big.Int).Mul(r, s.privateKey)
+		m.Add(m, hash).Mod(m, curveOrder)
+		if m.Sign() == 0 {
+			continue
+		}
+		w := new(big.Int).ModInverse(m, curveOrder)
+		w.Mul(w, k).Mod(w, curveOrder)
+		if w.Sign() == 0 || w.Cmp(maxHash) >= 0 {
+			continue
+		}
+		return &Signature{R: r, S: new(big.Int).ModInverse(w, curveOrder)}, nil
+	}
+}
+
+// Verify checks if the signature of the hash was created by the owner of
+// the public key. It returns the ErrInvalidSignature error if it was not.
+func Verify(publicKey, hash *big.Int, sig Signature) error {
+	if sig.R == nil || sig.S == nil {
+		return ErrInvalidSignature
+	}
+	if hash.Sign() < 0 || hash.Cmp(maxHash) >= 0 {
+		return ErrInvalidHash
+	}
+	if sig.R.Sign() <= 0 || sig.R.Cmp(maxHash) >= 0 {
+		return ErrInvalidSignature
+	}
+	if sig.S.Sign() <= 0 || sig.S.Cmp(curveOrder) >= 0 {
+		return ErrInvalidSignature
+	}
+	w := new(big.Int).ModInverse(sig.S, curveOrder)
+	if w == nil || w.Cmp(maxHash) >= 0 {
+		return ErrInvalidSignature
+	}
+	q, ok := pointFromX(publicKey)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	// Only the x coordinate of the public key is known, so both points
+	// with that coordinate are checked:
+	zG := mul(generator, hash)
+	rQ := mul(q, sig.R)
+	for _, p := range []point{add(zG, rQ), add(zG, rQ.neg())} {
+		if p = mul(p, w); !p.isInfinity() && p.x.Cmp(sig.R) == 0 {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// nonceGenerator generates nonces as described in the section 3.2 of
+// RFC 6979, using the SHA-256 hash function.
+type nonceGenerator struct {
+	k, v []byte
+}
+
+func newNonceGenerator(privateKey, hash *big.Int) *nonceGenerator {
+	x := int2octets(privateKey)
+	h := int2octets(new(big.Int).Mod(hash, curveOrder))
+	g := &nonceGenerator{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.mac(g.k, g.v, []byte{0x00}, x, h)
+	g.v = g.mac(g.k, g.v)
+	g.k = g.mac(g.k, g.v, []byte{0x01}, x, h)
+	g.v = g.mac(g.k, g.v)
+	return g
+}
+
+// next returns the next nonce candidate from the range [1, order).
+func (g *nonceGenerator) next() *big.Int {
+	qlen := curveOrder.BitLen()
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			g.v = g.mac(g.k, g.v)
+			t = append(t, g.v...)
+		}
+		k := new(big.Int).SetBytes(t)
+		if excess := len(t)*8 - qlen; excess > 0 {
+			k.Rsh(k, uint(excess))
+		}
+		g.k = g.mac(g.k, g.v, []byte{0x00})
+		g.v = g.mac(g.k, g.v)
+		if k.Sign() > 0 && k.Cmp(curveOrder) < 0 {
+			return k
+		}
+	}
+}
+
+func (g *nonceGenerator) mac(key []byte, data ...[]byte) []byte {
+	m := hmac.New(sha256.New, key)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+// int2octets encodes the number as a big-endian byte slice with the same
+// length as the curve order.
+func int2octets(n *big.Int) []byte {
+	b := make([]byte, (curveOrder.BitLen()+7)/8)
+	return n.FillBytes(b)
+}
@@ -0,0 +1,74 @@
+package stark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_curve(t *testing.T) {
+	// The generator must be a point of the given order:
+	assert.True(t, mul(generator, curveOrder).isInfinity())
+	assert.False(t, mul(generator, new(big.Int).Sub(curveOrder, big.NewInt(1))).isInfinity())
+
+	// Constant points must lie on the curve:
+	for _, p := range append([]point{generator}, pedersenPoints[:]...) {
+		q, ok := pointFromX(p.x)
+		require.True(t, ok)
+		assert.True(t, q.y.Cmp(p.y) == 0 || q.neg().y.Cmp(p.y) == 0)
+	}
+
+	// 2G + G == 3G:
+	assert.Equal(t, mul(generator, big.NewInt(3)), add(double(generator), generator))
+}
+
+func TestPedersenHash(t *testing.T) {
+	// Test vector from the StarkWare's crypto library:
+	h, err := PedersenHash(
+		hexToInt("3d937c035c878245caf64531a5756109c53068da139362728feb561405371cb"),
+		hexToInt("208a0a10250e382e1e4bbe2880906c2791bf6275695e02fbbc6aeff9cd8b31a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "30e480bed5fe53fa909cc0f8c4d99b8f9f2c016be4c41e13a4848797979c662", h.Text(16))
+
+	_, err = PedersenHash(fieldPrime, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrInvalidPedersenInput)
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	// Test vector from the StarkWare's crypto library:
+	s, err := NewSigner(hexToInt("3c1e9550e66958296d11b60f8e8e7a7ad990d07fa65d5f7652c4a6c87d4e3cc"))
+	require.NoError(t, err)
+	assert.Equal(t, "77a3b314db07c45076d11f62b6f9e748a39790441823307743cf00d6597ea43", s.PublicKey().Text(16))
+
+	hash := hexToInt("397e76d1667c4454bfb83514e120583af836f8e32a516765497823eabe16a3f")
+	sig, err := s.Signature(hash)
+	require.NoError(t, err)
+	assert.NoError(t, Verify(s.PublicKey(), hash, *sig))
+
+	// Signatures are deterministic:
+	sig2, err := s.Signature(hash)
+	require.NoError(t, err)
+	assert.Equal(t, sig, sig2)
+
+	// Different hash:
+	assert.ErrorIs(t, Verify(s.PublicKey(), big.NewInt(1), *sig), ErrInvalidSignature)
+
+	// Different key:
+	o, err := NewSigner(big.NewInt(42))
+	require.NoError(t, err)
+	assert.ErrorIs(t, Verify(o.PublicKey(), hash, *sig), ErrInvalidSignature)
+
+	// Invalid hash:
+	_, err = s.Signature(maxHash)
+	assert.ErrorIs(t, err, ErrInvalidHash)
+}
+
+func TestNewSigner_Invalid(t *testing.T) {
+	_, err := NewSigner(big.NewInt(0))
+	assert.ErrorIs(t, err, ErrInvalidPrivateKey)
+	_, err = NewSigner(curveOrder)
+	assert.ErrorIs(t, err, ErrInvalidPrivateKey)
+}
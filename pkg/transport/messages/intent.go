@@ -0,0 +1,67 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+)
+
+var IntentMessageName = "intent/v0"
+
+var ErrIntentMalformedMessage = errors.New("malformed intent message")
+
+// Intent is broadcast by a relayer right after it sends a transaction
+// updating an Oracle, so other relayers can skip the same update while
+// the transaction is pending.
+type Intent struct {
+	// AssetPair is the name of the asset pair, e.g. ETHUSD.
+	AssetPair string
+	// Relayer is the address from which the update was sent.
+	Relayer ethereum.Address
+	// OracleAge is the age of the Oracle price which will be replaced.
+	OracleAge time.Time
+	// Time is the time when the intent was created.
+	Time time.Time
+}
+
+// jsonIntent is the JSON representation of the Intent structure.
+type jsonIntent struct {
+	AssetPair string           `json:"assetPair"`
+	Relayer   ethereum.Address `json:"relayer"`
+	OracleAge int64            `json:"oracleAge"`
+	Time      int64            `json:"time"`
+}
+
+func (i *Intent) Marshall() ([]byte, error) {
+	return json.Marshal(jsonIntent{
+		AssetPair: i.AssetPair,
+		Relayer:   i.Relayer,
+		OracleAge: i.OracleAge.Unix(),
+		Time:      i.Time.Unix(),
+	})
+}
+
+func (i *Intent) Unmarshall(b []byte) error {
+	j := &jsonIntent{}
+	if err := json.Unmarshal(b, j); err != nil {
+		return err
+	}
+	if j.AssetPair == "" || j.Relayer == ethereum.EmptyAddress {
+		return ErrIntentMalformedMessage
+	}
+	i.AssetPair = j.AssetPair
+	i.Relayer = j.Relayer
+	i.OracleAge = time.Unix(j.OracleAge, 0)
+	i.Time = time.Unix(j.Time, 0)
+	return nil
+}
+
+func (i *Intent) MarshalBinary() ([]byte, error) {
+	return i.Marshall()
+}
+
+func (i *Intent) UnmarshalBinary(data []byte) error {
+	return i.Unmarshall(data)
+}
@@ -0,0 +1,45 @@
+package p2p
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/toknowwhy/theunit-oracle/internal/p2p"
+	"github.com/toknowwhy/theunit-oracle/pkg/log"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/p2p/crypto/ethkey"
+)
+
+// maxIntentAge is the maximum age of an intent message. Older messages
+// are ignored.
+const maxIntentAge = 5 * time.Minute
+
+// intent adds a validator for intent messages. The validator checks if
+// the author of the message is the relayer mentioned in the message and
+// ignores outdated intents.
+func intent(logger log.Logger) p2p.Options {
+	return func(n *p2p.Node) error {
+		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
+			intentMsg, ok := psMsg.ValidatorData.(*messages.Intent)
+			if !ok {
+				return pubsub.ValidationAccept
+			}
+			if ethkey.AddressToPeerID(intentMsg.Relayer) != psMsg.GetFrom() {
+				logger.
+					WithField("peerID", psMsg.GetFrom().String()).
+					WithField("relayer", intentMsg.Relayer.String()).
+					WithField("assetPair", intentMsg.AssetPair).
+					Warn("The intent message was rejected, the message author and relayer don't match")
+				return pubsub.ValidationReject
+			}
+			if time.Since(intentMsg.Time) > maxIntentAge {
+				return pubsub.ValidationIgnore
+			}
+			return pubsub.ValidationAccept
+		})
+		return nil
+	}
+}
@@ -2,89 +2,146 @@ package p2p
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 
 	"github.com/toknowwhy/theunit-oracle/internal/p2p"
+	"github.com/toknowwhy/theunit-oracle/internal/p2p/sets"
 	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
 	"github.com/toknowwhy/theunit-oracle/pkg/log"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
 	"github.com/toknowwhy/theunit-oracle/pkg/transport/p2p/crypto/ethkey"
 )
 
-// oracle adds a validator for price messages. The validator checks if the
-// author of the message is allowed to send price messages, the price
-// message is valid, and if the price is not older than 5 min.
-func oracle(feeders []ethereum.Address, signer ethereum.Signer, logger log.Logger) p2p.Options {
+// feederSet is a list of feeders allowed to send price messages. The list
+// may be replaced while the node is running.
+type feederSet struct {
+	mu    sync.RWMutex
+	addrs map[ethereum.Address]struct{}
+}
+
+func newFeederSet(addrs []ethereum.Address) *feederSet {
+	f := &feederSet{}
+	f.set(addrs)
+	return f
+}
+
+// set replaces the list of feeders and returns the number of feeders
+// before the change.
+func (f *feederSet) set(addrs []ethereum.Address) int {
+	m := make(map[ethereum.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		m[addr] = struct{}{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prev := len(f.addrs)
+	f.addrs = m
+	return prev
+}
+
+// allowed returns true if the given address is on the list.
+func (f *feederSet) allowed(addr ethereum.Address) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.addrs[addr]
+	return ok
+}
+
+// len returns the number of feeders on the list.
+func (f *feederSet) len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.addrs)
+}
+
+// oracle adds a validator for price messages.
+func oracle(feeders *feederSet, signer ethereum.Signer, starkOracleName string, logger log.Logger) p2p.Options {
 	return func(n *p2p.Node) error {
-		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
-			priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
-			if !ok {
-				return pubsub.ValidationAccept
-			}
-			// Check is a message signature is valid and extract author's address:
-			priceFrom, err := priceMsg.Price.From(signer)
-			wat := priceMsg.Price.Wat
-			age := priceMsg.Price.Age.UTC().Format(time.RFC3339)
-			val := priceMsg.Price.Val.String()
-			if err != nil {
+		n.AddValidator(oracleValidator(feeders, signer, starkOracleName, logger))
+		return nil
+	}
+}
+
+// oracleValidator returns a validator for price messages. The validator
+// checks if the author of the message is allowed to send price messages,
+// the price message is valid, and if the price is not older than 5 min. If
+// the starkOracleName is not empty, StarkWare signatures are verified too.
+func oracleValidator(feeders *feederSet, signer ethereum.Signer, starkOracleName string, logger log.Logger) sets.Validator {
+	return func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
+		priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
+		if !ok {
+			return pubsub.ValidationAccept
+		}
+		// Check is a message signature is valid and extract author's address:
+		priceFrom, err := priceMsg.Price.From(signer)
+		wat := priceMsg.Price.Wat
+		age := priceMsg.Price.Age.UTC().Format(time.RFC3339)
+		val := priceMsg.Price.Val.String()
+		if err != nil {
+			logger.
+				WithError(err).
+				WithField("peerID", psMsg.GetFrom().String()).
+				WithField("wat", wat).
+				WithField("age", age).
+				WithField("val", val).
+				Warn("The price message was rejected, invalid signature")
+			return pubsub.ValidationReject
+		}
+		// The libp2p message should be created by the same person who signs the price message:
+		if ethkey.AddressToPeerID(*priceFrom) != psMsg.GetFrom() {
+			logger.
+				WithField("peerID", psMsg.GetFrom().String()).
+				WithField("from", priceFrom.String()).
+				WithField("wat", wat).
+				WithField("age", age).
+				WithField("val", val).
+				Warn("The price message was rejected, the message author and price signature don't match")
+			return pubsub.ValidationReject
+		}
+		// Check if an author is allowed to send price messages:
+		if !feeders.allowed(*priceFrom) {
+			logger.
+				WithField("peerID", psMsg.GetFrom().String()).
+				WithField("from", priceFrom.String()).
+				WithField("wat", wat).
+				WithField("age", age).
+				WithField("val", val).
+				Warn("The price message was ignored, the feeder is not allowed to send price messages")
+			return pubsub.ValidationIgnore
+		}
+		// Check the StarkWare signature, if the price has one:
+		if starkOracleName != "" && priceMsg.Price.HasStarkSignature() {
+			if err := priceMsg.Price.StarkVerify(starkOracleName); err != nil {
 				logger.
 					WithError(err).
-					WithField("peerID", psMsg.GetFrom().String()).
-					WithField("wat", wat).
-					WithField("age", age).
-					WithField("val", val).
-					Warn("The price message was rejected, invalid signature")
-				return pubsub.ValidationReject
-			}
-			// The libp2p message should be created by the same person who signs the price message:
-			if ethkey.AddressToPeerID(*priceFrom) != psMsg.GetFrom() {
-				logger.
 					WithField("peerID", psMsg.GetFrom().String()).
 					WithField("from", priceFrom.String()).
 					WithField("wat", wat).
 					WithField("age", age).
 					WithField("val", val).
-					Warn("The price message was rejected, the message author and price signature don't match")
+					Warn("The price message was rejected, invalid StarkWare signature")
 				return pubsub.ValidationReject
 			}
-			// Check if an author is allowed to send price messages:
-			feedAllowed := false
-			for _, addr := range feeders {
-				if addr == *priceFrom {
-					feedAllowed = true
-					break
-				}
-			}
-			if !feedAllowed {
-				logger.
-					WithField("peerID", psMsg.GetFrom().String()).
-					WithField("from", priceFrom.String()).
-					WithField("wat", wat).
-					WithField("age", age).
-					WithField("val", val).
-					Warn("The price message was ignored, the feeder is not allowed to send price messages")
-				return pubsub.ValidationIgnore
-			}
-			// Check when message was created, ignore if older than 5 min, reject if older than 10 min:
-			if time.Since(priceMsg.Price.Age) > 5*time.Minute {
-				logger.
-					WithField("peerID", psMsg.GetFrom().String()).
-					WithField("from", priceFrom.String()).
-					WithField("wat", wat).
-					WithField("age", age).
-					WithField("val", val).
-					Warn("The price message was rejected, the message is older than 5 min")
-				if time.Since(priceMsg.Price.Age) > 10*time.Minute {
-					return pubsub.ValidationReject
-				}
-				return pubsub.ValidationIgnore
+		}
+		// Check when message was created, ignore if older than 5 min, reject if older than 10 min:
+		if time.Since(priceMsg.Price.Age) > 5*time.Minute {
+			logger.
+				WithField("peerID", psMsg.GetFrom().String()).
+				WithField("from", priceFrom.String()).
+				WithField("wat", wat).
+				WithField("age", age).
+				WithField("val", val).
+				Warn("The price message was rejected, the message is older than 5 min")
+			if time.Since(priceMsg.Price.Age) > 10*time.Minute {
+				return pubsub.ValidationReject
 			}
+			return pubsub.ValidationIgnore
+		}
 
-			return pubsub.ValidationAccept
-		})
-		return nil
+		return pubsub.ValidationAccept
 	}
 }
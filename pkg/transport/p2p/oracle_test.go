@@ -0,0 +1,71 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum"
+	"github.com/toknowwhy/theunit-oracle/pkg/ethereum/mocks"
+	"github.com/toknowwhy/theunit-oracle/pkg/log/null"
+	pkgOracle "github.com/toknowwhy/theunit-oracle/pkg/oracle"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/messages"
+	"github.com/toknowwhy/theunit-oracle/pkg/transport/p2p/crypto/ethkey"
+)
+
+var (
+	testFeeder1 = ethereum.HexToAddress("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	testFeeder2 = ethereum.HexToAddress("0xe3ced0f62f7eb2856d37bed128d2b195712d2644")
+)
+
+func testPriceMsg(from ethereum.Address) *pubsub.Message {
+	price := &pkgOracle.Price{Wat: "AAABBB", Age: time.Now()}
+	price.SetFloat64Price(10)
+	return &pubsub.Message{
+		Message:       &pubsub_pb.Message{From: []byte(ethkey.AddressToPeerID(from))},
+		ValidatorData: &messages.Price{Price: price},
+	}
+}
+
+func TestP2P_SetFeeders(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	signer := &mocks.Signer{}
+	p, err := New(ctx, Config{
+		Mode:         ClientMode,
+		FeedersAddrs: []ethereum.Address{testFeeder1},
+		Signer:       signer,
+		Logger:       null.New(),
+	})
+	require.NoError(t, err)
+	validator := oracleValidator(p.feeders, signer, "", null.New())
+
+	// The feeder is not allowed to send prices yet:
+	signer.On("Recover", mock.Anything, mock.Anything).Return(&testFeeder2, nil)
+	assert.Equal(t, pubsub.ValidationIgnore, validator(ctx, messages.PriceMessageName, "", testPriceMsg(testFeeder2)))
+
+	// Prices from the feeder lifted after startup are accepted:
+	require.NoError(t, p.SetFeeders([]ethereum.Address{testFeeder1, testFeeder2}))
+	assert.Equal(t, pubsub.ValidationAccept, validator(ctx, messages.PriceMessageName, "", testPriceMsg(testFeeder2)))
+
+	// And ignored again after the feeder is dropped:
+	require.NoError(t, p.SetFeeders([]ethereum.Address{testFeeder1}))
+	assert.Equal(t, pubsub.ValidationIgnore, validator(ctx, messages.PriceMessageName, "", testPriceMsg(testFeeder2)))
+}
+
+func TestMessageRateLimit(t *testing.T) {
+	bytesPerSecond, burstSize := messageRateLimit(4)
+	assert.Equal(t, maxBytesPerSecond/4, bytesPerSecond)
+	assert.Equal(t, int(maxBytesPerSecond*priceUpdateInterval.Seconds()/4), burstSize)
+
+	// Without feeders, the whole limit is used:
+	bytesPerSecond, _ = messageRateLimit(0)
+	assert.Equal(t, maxBytesPerSecond, bytesPerSecond)
+}
@@ -49,8 +49,9 @@ var defaultListenAddrs = []string{"/ip4/0.0.0.0/tcp/0"}
 // P2P is a little wrapper for the Node that implements the transport.Transport
 // interface.
 type P2P struct {
-	node   *p2p.Node
-	topics map[string]transport.Message
+	node    *p2p.Node
+	topics  map[string]transport.Message
+	feeders *feederSet
 }
 
 // Config is a configuration for the P2P transport.
@@ -82,7 +83,8 @@ type Config struct {
 	// will be blocked separately.
 	BlockedAddrs []string
 	// FeedersAddrs is a list of price feeders. Only feeders can create new
-	// messages in the network.
+	// messages in the network. The list can be replaced later using the
+	// SetFeeders method.
 	FeedersAddrs []ethereum.Address
 	// Discovery indicates whenever peer discovery should be enabled.
 	// If discovery is disabled, then DirectPeersAddrs must be used
@@ -90,6 +92,10 @@ type Config struct {
 	Discovery bool
 	// Signer used to verify price messages. Ignored in bootstrap mode.
 	Signer ethereum.Signer
+	// StarkOracleName is the oracle name used to verify StarkWare signatures
+	// of price messages. If empty, StarkWare signatures are not verified.
+	// Ignored in bootstrap mode.
+	StarkOracleName string
 	// Logger is a custom logger instance. If not provided then null
 	// logger is used.
 	Logger log.Logger
@@ -128,6 +134,7 @@ func New(ctx context.Context, cfg Config) (*P2P, error) {
 	}
 
 	logger := cfg.Logger.WithField("tag", LoggerTag)
+	feeders := newFeederSet(cfg.FeedersAddrs)
 	opts := []p2p.Options{
 		p2p.Logger(logger),
 		p2p.ConnectionLogger(),
@@ -150,14 +157,15 @@ func New(ctx context.Context, cfg Config) (*P2P, error) {
 	case ClientMode:
 		opts = append(opts,
 			p2p.MessageLogger(),
-			p2p.RateLimiter(rateLimiterConfig(cfg)),
+			p2p.RateLimiter(rateLimiterConfig(feeders.len())),
 			p2p.PeerScoring(peerScoreParams, thresholds, func(topic string) *pubsub.TopicScoreParams {
 				if topic == messages.PriceMessageName {
-					return priceTopicScoreParams(cfg)
+					return priceTopicScoreParams(feeders.len())
 				}
 				return nil
 			}),
-			oracle(cfg.FeedersAddrs, cfg.Signer, logger),
+			oracle(feeders, cfg.Signer, cfg.StarkOracleName, logger),
+			intent(logger),
 		)
 		if cfg.MessagePrivKey != nil {
 			opts = append(opts, p2p.MessagePrivKey(cfg.MessagePrivKey))
@@ -178,7 +186,31 @@ func New(ctx context.Context, cfg Config) (*P2P, error) {
 		return nil, fmt.Errorf("P2P transport error, unable to initialize node: %w", err)
 	}
 
-	return &P2P{node: n, topics: cfg.Topics}, nil
+	return &P2P{node: n, topics: cfg.Topics, feeders: feeders}, nil
+}
+
+// SetFeeders replaces the list of feeders allowed to send price messages.
+// If the number of feeders changes, the rate limits and the peer scoring
+// of the price topic, which are sized by the number of feeders, are
+// recalculated.
+func (p *P2P) SetFeeders(feeders []ethereum.Address) error {
+	prev := p.feeders.set(feeders)
+	count := p.feeders.len()
+	if prev == count {
+		return nil
+	}
+	p.node.SetMessageRateLimit(messageRateLimit(count))
+	sub, err := p.node.Subscription(messages.PriceMessageName)
+	if err != nil {
+		// The price topic is not subscribed yet, score parameters will be
+		// calculated during subscription.
+		return nil
+	}
+	err = sub.SetScoreParams(priceTopicScoreParams(count))
+	if err != nil {
+		return fmt.Errorf("P2P transport error, unable to update score parameters: %w", err)
+	}
+	return nil
 }
 
 // Start implements the transport.Transport interface.
@@ -210,6 +242,27 @@ func (p *P2P) Broadcast(topic string, message transport.Message) error {
 	return sub.Publish(message)
 }
 
+// WaitForPeers blocks until at least n peers subscribed to the topic are
+// connected or until the context is cancelled.
+func (p *P2P) WaitForPeers(ctx context.Context, topic string, n int) error {
+	ps := p.node.PubSub()
+	if ps == nil {
+		return errors.New("P2P transport error, pubsub is disabled")
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if len(ps.ListPeers(topic)) >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("P2P transport error, not enough peers for %s topic: %w", topic, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
 // Messages implements the transport.Transport interface.
 func (p *P2P) Messages(topic string) chan transport.ReceivedMessage {
 	sub, err := p.node.Subscription(topic)
@@ -241,13 +294,24 @@ func strsToMaddrs(addrs []string) ([]core.Multiaddr, error) {
 	return maddrs, nil
 }
 
-func rateLimiterConfig(cfg Config) p2p.RateLimiterConfig {
+func rateLimiterConfig(feederCount int) p2p.RateLimiterConfig {
 	bytesPerSecond := maxBytesPerSecond
 	burstSize := maxBytesPerSecond * priceUpdateInterval.Seconds()
+	msgBytesPerSecond, msgBurstSize := messageRateLimit(feederCount)
 	return p2p.RateLimiterConfig{
-		BytesPerSecond:      maxBytesPerSecond / float64(len(cfg.FeedersAddrs)),
-		BurstSize:           int(burstSize / float64(len(cfg.FeedersAddrs))),
+		BytesPerSecond:      msgBytesPerSecond,
+		BurstSize:           msgBurstSize,
 		RelayBytesPerSecond: bytesPerSecond,
 		RelayBurstSize:      int(burstSize),
 	}
 }
+
+// messageRateLimit returns the rate limit for a single message author. The
+// maximum rate is divided equally between all feeders.
+func messageRateLimit(feederCount int) (float64, int) {
+	if feederCount < 1 {
+		feederCount = 1
+	}
+	burstSize := maxBytesPerSecond * priceUpdateInterval.Seconds()
+	return maxBytesPerSecond / float64(feederCount), int(burstSize / float64(feederCount))
+}
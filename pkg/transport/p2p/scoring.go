@@ -72,11 +72,14 @@ const priceP2Length = 15 * time.Minute
 const priceP3Length = 15 * time.Minute
 const priceP3BLength = 15 * time.Minute
 
-func priceTopicScoreParams(cfg Config) *pubsub.TopicScoreParams {
+func priceTopicScoreParams(feederCount int) *pubsub.TopicScoreParams {
+	if feederCount < 1 {
+		feederCount = 1
+	}
 	var maxPeers = float64(pubsub.GossipSubDhi)
 	// Minimum and maximum expected number of feeders connected to the network:
-	var minFeederCount = float64(len(cfg.FeedersAddrs)) / 2 // assume that 50% of feeders are offline
-	var maxFeederCount = float64(len(cfg.FeedersAddrs))
+	var minFeederCount = float64(feederCount) / 2 // assume that 50% of feeders are offline
+	var maxFeederCount = float64(feederCount)
 	// Minimum and maximum expected number of asset pairs to be broadcast by each feeder:
 	var minAssetPairCount = float64(1)
 	var maxAssetPairCount = maxBytesPerSecond / maxMsgSizeInBytes * priceUpdateInterval.Seconds() / maxFeederCount